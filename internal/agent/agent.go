@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -71,6 +72,9 @@ type SessionAgent interface {
 	QueuedPromptsList(sessionID string) []string
 	ClearQueue(sessionID string)
 	Summarize(context.Context, string, fantasy.ProviderOptions) error
+	Generate(ctx context.Context, modelType config.SelectedModelType, systemPrompt, prompt string) (string, error)
+	SetSessionTools(sessionID string, tools []fantasy.AgentTool)
+	SetSessionSystemPrompt(sessionID, systemPrompt string)
 	Model() Model
 }
 
@@ -94,6 +98,10 @@ type sessionAgent struct {
 
 	messageQueue   *csync.Map[string, []SessionAgentCall]
 	activeRequests *csync.Map[string, context.CancelFunc]
+	// sessionTools replaces tools for individual sessions
+	sessionTools *csync.Map[string, []fantasy.AgentTool]
+	// sessionPrompts replaces systemPrompt for individual sessions
+	sessionPrompts *csync.Map[string, string]
 }
 
 type SessionAgentOptions struct {
@@ -125,6 +133,8 @@ func NewSessionAgent(
 		isYolo:               opts.IsYolo,
 		messageQueue:         csync.NewMap[string, []SessionAgentCall](),
 		activeRequests:       csync.NewMap[string, context.CancelFunc](),
+		sessionTools:         csync.NewMap[string, []fantasy.AgentTool](),
+		sessionPrompts:       csync.NewMap[string, string](),
 	}
 }
 
@@ -147,15 +157,25 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 		return nil, nil
 	}
 
-	if len(a.tools) > 0 {
+	agentTools := a.tools
+	if sessionTools, ok := a.sessionTools.Get(call.SessionID); ok {
+		agentTools = sessionTools
+		// Every prompt of the session gets the full read-only tool budget
+		tools.ResetBudget(agentTools)
+	}
+	if len(agentTools) > 0 {
 		// Add Anthropic caching to the last tool.
-		a.tools[len(a.tools)-1].SetProviderOptions(a.getCacheControlOptions())
+		agentTools[len(agentTools)-1].SetProviderOptions(a.getCacheControlOptions())
 	}
 
+	systemPrompt := a.systemPrompt
+	if sessionPrompt, ok := a.sessionPrompts.Get(call.SessionID); ok {
+		systemPrompt = sessionPrompt
+	}
 	agent := fantasy.NewAgent(
 		a.largeModel.Model,
-		fantasy.WithSystemPrompt(a.systemPrompt),
-		fantasy.WithTools(a.tools...),
+		fantasy.WithSystemPrompt(systemPrompt),
+		fantasy.WithTools(agentTools...),
 	)
 
 	sessionLock := sync.Mutex{}
@@ -229,18 +249,19 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 
 			prepared.Messages = a.workaroundProviderMediaLimitations(prepared.Messages)
 
+			_, review := a.sessionPrompts.Get(call.SessionID)
+			cachedInx := cachedMessageIndexes(prepared.Messages, review)
 			lastSystemRoleInx := 0
 			systemMessageUpdated := false
 			for i, msg := range prepared.Messages {
-				// Only add cache control to the last message.
+				// Only add cache control to the last system message.
 				if msg.Role == fantasy.MessageRoleSystem {
 					lastSystemRoleInx = i
 				} else if !systemMessageUpdated {
 					prepared.Messages[lastSystemRoleInx].ProviderOptions = a.getCacheControlOptions()
 					systemMessageUpdated = true
 				}
-				// Than add cache control to the last 2 messages.
-				if i > len(prepared.Messages)-3 {
+				if slices.Contains(cachedInx, i) {
 					prepared.Messages[i].ProviderOptions = a.getCacheControlOptions()
 				}
 			}
@@ -649,6 +670,33 @@ func (a *sessionAgent) getCacheControlOptions() fantasy.ProviderOptions {
 	}
 }
 
+// cachedMessageIndexes returns the messages that get a cache breakpoint besides the last system message:
+// the last two, or for review sessions (which have their own system prompt) the first prompt, the review's
+// static context that every follow-up reuses, and the last one. With the last tool that's Anthropic's limit
+// of four. Only Anthropic and Bedrock get the markers (see getCacheControlOptions).
+func cachedMessageIndexes(messages []fantasy.Message, review bool) []int {
+	last := len(messages) - 1
+	if !review {
+		return []int{last - 1, last}
+	}
+	return []int{contextMessageIndex(messages), last}
+}
+
+// contextMessageIndex returns the index of the session's first user prompt, skipping the todo reminder
+// (-1 when there is none)
+func contextMessageIndex(messages []fantasy.Message) int {
+	for i, msg := range messages {
+		if msg.Role != fantasy.MessageRoleUser || len(msg.Content) == 0 {
+			continue
+		}
+		if text, ok := msg.Content[0].(fantasy.TextPart); ok && strings.HasPrefix(text.Text, "<system_reminder>") {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
 func (a *sessionAgent) createUserMessage(ctx context.Context, call SessionAgentCall) (message.Message, error) {
 	parts := []message.ContentPart{message.TextContent{Text: call.Prompt}}
 	var attachmentParts []message.ContentPart
@@ -937,10 +985,61 @@ func (a *sessionAgent) SetTools(tools []fantasy.AgentTool) {
 	a.tools = tools
 }
 
+// SetSessionTools replaces the tools of one session (nil restores the agent's own; empty allows none)
+func (a *sessionAgent) SetSessionTools(sessionID string, tools []fantasy.AgentTool) {
+	if tools == nil {
+		a.sessionTools.Del(sessionID)
+		return
+	}
+	a.sessionTools.Set(sessionID, tools)
+}
+
 func (a *sessionAgent) Model() Model {
 	return a.largeModel
 }
 
+// SetSessionSystemPrompt replaces the system prompt of one session ("" restores the agent's own)
+func (a *sessionAgent) SetSessionSystemPrompt(sessionID, systemPrompt string) {
+	if systemPrompt == "" {
+		a.sessionPrompts.Del(sessionID)
+		return
+	}
+	a.sessionPrompts.Set(sessionID, systemPrompt)
+}
+
+// Generate runs a one-shot completion without tools or session history
+func (a *sessionAgent) Generate(ctx context.Context, modelType config.SelectedModelType, systemPrompt, prompt string) (string, error) {
+	model := a.largeModel
+	if modelType == config.SelectedModelTypeSmall {
+		model = a.smallModel
+	}
+
+	agent := fantasy.NewAgent(model.Model,
+		fantasy.WithSystemPrompt(systemPrompt),
+		fantasy.WithMaxOutputTokens(model.CatwalkCfg.DefaultMaxTokens),
+	)
+	resp, err := agent.Generate(ctx, fantasy.AgentCall{
+		Prompt: prompt,
+		PrepareStep: func(callContext context.Context, options fantasy.PrepareStepFunctionOptions) (_ context.Context, prepared fantasy.PrepareStepResult, err error) {
+			prepared.Messages = options.Messages
+			if a.systemPromptPrefix != "" {
+				prepared.Messages = append([]fantasy.Message{fantasy.NewSystemMessage(a.systemPromptPrefix)}, prepared.Messages...)
+			}
+			return callContext, prepared, nil
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	text := resp.Response.Content.Text()
+	// Remove thinking tags if present.
+	if idx := strings.Index(text, "</think>"); idx >= 0 {
+		text = text[idx+len("</think>"):]
+	}
+	return strings.TrimSpace(text), nil
+}
+
 func (a *sessionAgent) promptPrefix() string {
 	if a.isClaudeCode() {
 		return "You are Claude Code, Anthropic's official CLI for Claude."
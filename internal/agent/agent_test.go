@@ -0,0 +1,40 @@
+package agent
+
+import (
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextMessageIndex(t *testing.T) {
+	t.Parallel()
+
+	messages := []fantasy.Message{
+		fantasy.NewSystemMessage("system"),
+		fantasy.NewUserMessage("<system_reminder>todos</system_reminder>"),
+		fantasy.NewUserMessage("review request"),
+		{Role: fantasy.MessageRoleAssistant, Content: []fantasy.MessagePart{fantasy.TextPart{Text: "findings"}}},
+		fantasy.NewUserMessage("follow-up"),
+	}
+	require.Equal(t, 2, contextMessageIndex(messages))
+	require.Equal(t, -1, contextMessageIndex(messages[:2]))
+	require.Equal(t, -1, contextMessageIndex(nil))
+}
+
+func TestCachedMessageIndexes(t *testing.T) {
+	t.Parallel()
+
+	messages := []fantasy.Message{
+		fantasy.NewSystemMessage("system"),
+		fantasy.NewUserMessage("review request"),
+		{Role: fantasy.MessageRoleAssistant, Content: []fantasy.MessagePart{fantasy.TextPart{Text: "findings"}}},
+		fantasy.NewUserMessage("follow-up"),
+		{Role: fantasy.MessageRoleAssistant, Content: []fantasy.MessagePart{fantasy.TextPart{Text: "answer"}}},
+		fantasy.NewUserMessage("second follow-up"),
+	}
+	// Review sessions keep the review request cached for every follow-up
+	require.Equal(t, []int{1, 5}, cachedMessageIndexes(messages, true))
+	// Other sessions cache the last two messages
+	require.Equal(t, []int{4, 5}, cachedMessageIndexes(messages, false))
+}
@@ -53,6 +53,14 @@ type Coordinator interface {
 	QueuedPromptsList(sessionID string) []string
 	ClearQueue(sessionID string)
 	Summarize(context.Context, string) error
+	// Generate runs a one-shot completion on the large or small model, without tools or session history
+	Generate(ctx context.Context, modelType config.SelectedModelType, systemPrompt, prompt string) (string, error)
+	// DisableTools runs one session without tools (false restores the agent's tools)
+	DisableTools(sessionID string, disabled bool)
+	// SetSystemPrompt replaces the agent's system prompt for one session ("" restores it)
+	SetSystemPrompt(sessionID, systemPrompt string)
+	// SetReadOnlyTools restricts one session to the read-only tools under a policy (nil restores the agent's tools)
+	SetReadOnlyTools(sessionID string, policy *tools.ReadOnlyPolicy)
 	Model() Model
 	UpdateModels(ctx context.Context) error
 }
@@ -764,6 +772,43 @@ func isExactoSupported(modelID string) bool {
 	return slices.Contains(supportedModels, modelID)
 }
 
+// Generate implements Coordinator.
+func (c *coordinator) Generate(ctx context.Context, modelType config.SelectedModelType, systemPrompt, prompt string) (string, error) {
+	if err := c.readyWg.Wait(); err != nil {
+		return "", err
+	}
+	return c.currentAgent.Generate(ctx, modelType, systemPrompt, prompt)
+}
+
+func (c *coordinator) DisableTools(sessionID string, disabled bool) {
+	if !disabled {
+		c.currentAgent.SetSessionTools(sessionID, nil)
+		return
+	}
+	c.currentAgent.SetSessionTools(sessionID, []fantasy.AgentTool{})
+}
+
+func (c *coordinator) SetSystemPrompt(sessionID, systemPrompt string) {
+	c.currentAgent.SetSessionSystemPrompt(sessionID, systemPrompt)
+}
+
+func (c *coordinator) SetReadOnlyTools(sessionID string, policy *tools.ReadOnlyPolicy) {
+	if policy == nil || policy.Budget <= 0 {
+		c.DisableTools(sessionID, policy != nil)
+		return
+	}
+	readOnly := []fantasy.AgentTool{
+		tools.NewGlobTool(c.cfg.WorkingDir()),
+		tools.NewGrepTool(c.cfg.WorkingDir()),
+		tools.NewLsTool(c.permissions, c.cfg.WorkingDir(), c.cfg.Tools.Ls),
+		tools.NewViewTool(c.lspClients, c.permissions, c.cfg.WorkingDir()),
+	}
+	if len(c.cfg.LSP) > 0 {
+		readOnly = append(readOnly, tools.NewReferencesTool(c.lspClients))
+	}
+	c.currentAgent.SetSessionTools(sessionID, tools.NewReadOnlyTools(readOnly, c.cfg.WorkingDir(), *policy))
+}
+
 func (c *coordinator) Cancel(sessionID string) {
 	c.currentAgent.Cancel(sessionID)
 }
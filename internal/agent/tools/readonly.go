@@ -0,0 +1,253 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"charm.land/fantasy"
+	"github.com/bytedance/sonic"
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/filepathext"
+)
+
+// DefaultToolBudget is the default number of tool calls a read-only session may make
+const DefaultToolBudget = 20
+
+// ReadOnlyToolNames are the tools that explore the repository without changing it
+var ReadOnlyToolNames = []string{GlobToolName, GrepToolName, LSToolName, ReferencesToolName, ViewToolName}
+
+// ReadOnlyPolicy restricts a session to the read-only tools
+type ReadOnlyPolicy struct {
+	// Budget caps the tool calls of the session; calls beyond it fail without running
+	Budget int
+	// Ignore reports whether a repo-relative path is excluded; its content is never returned
+	Ignore func(path string) bool
+	// Redact masks secrets in returned content
+	Redact func(content string) string
+}
+
+// readOnlyTool runs a read-only tool under a policy, sharing the call budget with the session's other tools
+type readOnlyTool struct {
+	fantasy.AgentTool
+	policy     ReadOnlyPolicy
+	workingDir string
+	calls      *atomic.Int64
+}
+
+// NewReadOnlyTools wraps the read-only tools among tools with a policy for one session
+// Other tools are left out, and every path must stay inside workingDir
+func NewReadOnlyTools(tools []fantasy.AgentTool, workingDir string, policy ReadOnlyPolicy) []fantasy.AgentTool {
+	calls := &atomic.Int64{}
+	var readOnly []fantasy.AgentTool
+	for _, tool := range tools {
+		if slices.Contains(ReadOnlyToolNames, tool.Info().Name) {
+			readOnly = append(readOnly, &readOnlyTool{AgentTool: tool, policy: policy, workingDir: workingDir, calls: calls})
+		}
+	}
+	return readOnly
+}
+
+// ResetBudget gives the read-only tools among tools their full call budget again, e.g. for a new user prompt
+func ResetBudget(tools []fantasy.AgentTool) {
+	for _, tool := range tools {
+		if readOnly, ok := tool.(*readOnlyTool); ok {
+			readOnly.calls.Store(0)
+		}
+	}
+}
+
+// Run implements fantasy.AgentTool
+func (t *readOnlyTool) Run(ctx context.Context, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+	if n := t.calls.Add(1); n > int64(t.policy.Budget) {
+		return fantasy.NewTextErrorResponse(fmt.Sprintf("tool-call budget of %d exhausted: finish the review with the context you have", t.policy.Budget)), nil
+	}
+
+	var params struct {
+		FilePath string `json:"file_path"`
+		Path     string `json:"path"`
+	}
+	if err := sonic.UnmarshalString(call.Input, &params); err != nil {
+		return fantasy.NewTextErrorResponse(fmt.Sprintf("invalid input: %v", err)), nil
+	}
+	for _, path := range []string{params.FilePath, params.Path} {
+		if path == "" {
+			continue
+		}
+		relPath, ok := t.relPath(path)
+		if !ok {
+			return fantasy.NewTextErrorResponse(fmt.Sprintf("%s is outside the repository", path)), nil
+		}
+		// Directories can be searched; only an excluded file (or a path inside an excluded directory) is refused
+		if relPath != "." && t.ignored(relPath) {
+			return fantasy.NewTextErrorResponse(fmt.Sprintf("%s is excluded from the review", relPath)), nil
+		}
+	}
+
+	response, err := t.AgentTool.Run(ctx, call)
+	if err != nil || response.Type != "text" {
+		return response, err
+	}
+	switch t.Info().Name {
+	case GrepToolName:
+		response.Content = t.filterGrepOutput(response.Content)
+	case GlobToolName:
+		response.Content = t.filterGlobOutput(response.Content)
+	case LSToolName:
+		response.Content = t.filterLSOutput(response.Content)
+	case ReferencesToolName:
+		response.Content = t.filterReferencesOutput(response.Content)
+	}
+	response.Content = t.redact(response.Content)
+	response.Metadata = t.redact(response.Metadata)
+	return response, nil
+}
+
+// filterGrepOutput drops the matches in excluded files from grep output ("path:" headers followed by indented matches)
+func (t *readOnlyTool) filterGrepOutput(output string) string {
+	return t.filterFileGroups(output, func(line string) (string, bool) {
+		return strings.CutSuffix(line, ":")
+	})
+}
+
+// filterReferencesOutput drops the references in excluded files from lsp_references output
+// ("path (N reference(s)):" headers followed by indented locations)
+func (t *readOnlyTool) filterReferencesOutput(output string) string {
+	return t.filterFileGroups(output, func(line string) (string, bool) {
+		i := strings.LastIndex(line, " (")
+		if i < 0 || !strings.HasSuffix(line, " reference(s)):") {
+			return "", false
+		}
+		return line[:i], true
+	})
+}
+
+// filterFileGroups drops the groups of excluded files from output made of unindented headers,
+// whose path headerPath returns, each followed by indented lines up to a blank line
+func (t *readOnlyTool) filterFileGroups(output string, headerPath func(line string) (string, bool)) string {
+	var kept []string
+	skip := false
+	dropped := 0
+	for _, line := range strings.Split(output, "\n") {
+		if path, ok := headerPath(line); ok && !strings.HasPrefix(line, " ") {
+			relPath, inside := t.relPath(path)
+			skip = !inside || t.ignored(relPath)
+			if skip {
+				dropped++
+			}
+		}
+		if !skip {
+			kept = append(kept, line)
+		}
+		if line == "" {
+			skip = false
+		}
+	}
+	if dropped > 0 {
+		kept = append(kept, fmt.Sprintf("(%d file(s) excluded from the review were left out)", dropped))
+	}
+	return strings.Join(kept, "\n")
+}
+
+// filterGlobOutput drops the excluded files from glob output (one path per line)
+func (t *readOnlyTool) filterGlobOutput(output string) string {
+	lines := strings.Split(output, "\n")
+	kept := lo.Reject(lines, func(line string, _ int) bool {
+		relPath, inside := t.relPath(line)
+		return line != "" && (!inside || t.ignored(relPath))
+	})
+	if dropped := len(lines) - len(kept); dropped > 0 {
+		kept = append(kept, fmt.Sprintf("(%d file(s) excluded from the review were left out)", dropped))
+	}
+	return strings.Join(kept, "\n")
+}
+
+// filterLSOutput drops the excluded files and directories from ls output, a tree of "- name" lines
+// indented by two spaces per level under a "- /root/" line
+func (t *readOnlyTool) filterLSOutput(output string) string {
+	var kept []string
+	// parents holds the directory path at each level, starting with the listed root
+	var parents []string
+	skipBelow := -1
+	dropped := 0
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		name, isNode := strings.CutPrefix(trimmed, "- ")
+		if !isNode {
+			kept = append(kept, line)
+			continue
+		}
+		level := (len(line) - len(trimmed)) / 2
+		if skipBelow >= 0 && level > skipBelow {
+			continue
+		}
+		skipBelow = -1
+		if level == 0 {
+			parents = []string{strings.TrimSuffix(name, "/")}
+		}
+		if level == 0 || level > len(parents) {
+			kept = append(kept, line)
+			continue
+		}
+
+		parents = parents[:level]
+		nodePath := filepath.Join(parents[level-1], strings.TrimSuffix(name, "/"))
+		relPath, inside := t.relPath(nodePath)
+		if strings.HasSuffix(name, "/") {
+			relPath += "/"
+		}
+		if !inside || t.ignored(relPath) {
+			skipBelow = level
+			dropped++
+			continue
+		}
+		parents = append(parents, nodePath)
+		kept = append(kept, line)
+	}
+	if dropped > 0 {
+		kept = append(kept, fmt.Sprintf("(%d excluded file(s) or directories were left out)", dropped))
+	}
+	return strings.Join(kept, "\n")
+}
+
+// relPath returns a path relative to the working directory, and false when it's outside
+// Symlinks are resolved, so a link inside the repository can't reach outside it or an excluded file
+func (t *readOnlyTool) relPath(path string) (string, bool) {
+	absWorkingDir, err := filepath.Abs(t.workingDir)
+	if err != nil {
+		return "", false
+	}
+	absPath, err := filepath.Abs(filepathext.SmartJoin(t.workingDir, path))
+	if err != nil {
+		return "", false
+	}
+	// Paths that don't exist (yet) are checked as written; the tool reports them as missing
+	if resolved, err := filepath.EvalSymlinks(absPath); err == nil {
+		absPath = resolved
+		if resolvedDir, err := filepath.EvalSymlinks(absWorkingDir); err == nil {
+			absWorkingDir = resolvedDir
+		}
+	}
+	relPath, err := filepath.Rel(absWorkingDir, absPath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, "../") {
+		return "", false
+	}
+	return filepath.ToSlash(relPath), true
+}
+
+// ignored reports whether the policy excludes a repo-relative path
+func (t *readOnlyTool) ignored(relPath string) bool {
+	return t.policy.Ignore != nil && t.policy.Ignore(relPath)
+}
+
+// redact masks the secrets in content
+func (t *readOnlyTool) redact(content string) string {
+	if t.policy.Redact == nil || content == "" {
+		return content
+	}
+	return t.policy.Redact(content)
+}
@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeToolInput struct {
+	FilePath string `json:"file_path"`
+	Path     string `json:"path"`
+}
+
+// fakeTool returns output without touching the filesystem
+func fakeTool(name, output string) fantasy.AgentTool {
+	return fantasy.NewAgentTool(name, "fake "+name, func(ctx context.Context, input fakeToolInput, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+		return fantasy.NewTextResponse(output), nil
+	})
+}
+
+func TestReadOnlyTools(t *testing.T) {
+	t.Parallel()
+
+	newTools := func(budget int) map[string]fantasy.AgentTool {
+		readOnly := NewReadOnlyTools([]fantasy.AgentTool{
+			fakeTool(ViewToolName, "token = sk-secret"),
+			fakeTool(GrepToolName, "Found 3 matches\nmain.go:\n  Line 3: foo()\n\nvendor/lib/lib.go:\n  Line 9: foo()\n\nlib/lib.go:\n  Line 1: foo()\n"),
+			fakeTool(GlobToolName, "/repo/main.go\n/repo/vendor/lib/lib.go\nlib/lib.go"),
+			fakeTool(LSToolName, "- /repo/\n  - lib/\n    - lib.go\n  - vendor/\n    - lib/\n      - lib.go\n  - main.go\n"),
+			fakeTool(ReferencesToolName, "Found 2 reference(s) in 2 file(s):\n\n/repo/main.go (1 reference(s)):\n  Line 3, Column 1\n\n/repo/vendor/lib/lib.go (1 reference(s)):\n  Line 9, Column 1\n\n"),
+			fakeTool(EditToolName, "edited"),
+		}, "/repo", ReadOnlyPolicy{
+			Budget: budget,
+			Ignore: func(path string) bool { return strings.HasPrefix(path, "vendor/") },
+			Redact: func(content string) string { return strings.ReplaceAll(content, "sk-secret", "********") },
+		})
+		byName := make(map[string]fantasy.AgentTool)
+		for _, tool := range readOnly {
+			byName[tool.Info().Name] = tool
+		}
+		return byName
+	}
+	run := func(tool fantasy.AgentTool, input string) fantasy.ToolResponse {
+		response, err := tool.Run(t.Context(), fantasy.ToolCall{ID: "1", Name: tool.Info().Name, Input: input})
+		require.NoError(t, err)
+		return response
+	}
+
+	readOnly := newTools(3)
+	require.Len(t, readOnly, 5)
+	require.NotContains(t, readOnly, EditToolName)
+
+	tests := []struct {
+		name    string
+		tool    string
+		input   string
+		want    string
+		isError bool
+	}{
+		{"redacted", ViewToolName, `{"file_path":"main.go"}`, "token = ********", false},
+		{"outside the repository", ViewToolName, `{"file_path":"../etc/passwd"}`, "../etc/passwd is outside the repository", true},
+		{"excluded file", ViewToolName, `{"file_path":"/repo/vendor/lib/lib.go"}`, "vendor/lib/lib.go is excluded from the review", true},
+		{"glob filtered", GlobToolName, `{"path":"."}`, "/repo/main.go\nlib/lib.go\n(1 file(s) excluded from the review were left out)", false},
+		{"ls filtered", LSToolName, `{"path":"/repo"}`, "- /repo/\n  - lib/\n    - lib.go\n  - main.go\n\n(1 excluded file(s) or directories were left out)", false},
+		{"references filtered", ReferencesToolName, `{"symbol":"foo"}`, "Found 2 reference(s) in 2 file(s):\n\n/repo/main.go (1 reference(s)):\n  Line 3, Column 1\n\n\n(1 file(s) excluded from the review were left out)", false},
+		{"grep filtered", GrepToolName, `{"path":"."}`, "Found 3 matches\nmain.go:\n  Line 3: foo()\n\nlib/lib.go:\n  Line 1: foo()\n\n(1 file(s) excluded from the review were left out)", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			response := run(newTools(1)[tt.tool], tt.input)
+			require.Equal(t, tt.isError, response.IsError)
+			require.Equal(t, tt.want, response.Content)
+		})
+	}
+
+	// The budget is shared by the session's tools, and refused calls count against it
+	require.False(t, run(readOnly[ViewToolName], `{"file_path":"main.go"}`).IsError)
+	require.True(t, run(readOnly[ViewToolName], `{"file_path":"../x"}`).IsError)
+	require.False(t, run(readOnly[GrepToolName], `{"path":"."}`).IsError)
+	exhausted := run(readOnly[ViewToolName], `{"file_path":"main.go"}`)
+	require.True(t, exhausted.IsError)
+	require.Contains(t, exhausted.Content, "tool-call budget of 3 exhausted")
+
+	// A new prompt gets the full budget again
+	ResetBudget(slices.Collect(maps.Values(readOnly)))
+	require.False(t, run(readOnly[ViewToolName], `{"file_path":"main.go"}`).IsError)
+}
+
+func TestReadOnlyToolsResolveSymlinks(t *testing.T) {
+	t.Parallel()
+
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0o644))
+	repo := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "vendor"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "vendor", "lib.go"), []byte("package lib"), 0o644))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "secret.txt"), filepath.Join(repo, "escape.txt")))
+	require.NoError(t, os.Symlink(filepath.Join(repo, "vendor", "lib.go"), filepath.Join(repo, "lib.go")))
+
+	view := NewReadOnlyTools([]fantasy.AgentTool{fakeTool(ViewToolName, "content")}, repo, ReadOnlyPolicy{
+		Budget: 10,
+		Ignore: func(path string) bool { return strings.HasPrefix(path, "vendor/") },
+	})[0]
+	run := func(path string) fantasy.ToolResponse {
+		response, err := view.Run(t.Context(), fantasy.ToolCall{ID: "1", Name: ViewToolName, Input: `{"file_path":"` + path + `"}`})
+		require.NoError(t, err)
+		return response
+	}
+
+	require.Equal(t, "escape.txt is outside the repository", run("escape.txt").Content)
+	require.Equal(t, "vendor/lib.go is excluded from the review", run("lib.go").Content)
+	require.False(t, run("missing.go").IsError)
+}
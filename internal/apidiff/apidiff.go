@@ -0,0 +1,144 @@
+// Package apidiff compares the exported API of Go packages between two revisions
+// and classifies every change as compatible or incompatible, in the spirit of golang.org/x/exp/apidiff
+package apidiff
+
+import (
+	"cmp"
+	"fmt"
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/prompt"
+)
+
+// Kind classifies an API change
+type Kind string
+
+const (
+	// KindCompatible changes keep existing importers compiling (e.g. an added function)
+	KindCompatible Kind = "compatible"
+	// KindIncompatible changes can break importers (e.g. a removed field or a changed signature)
+	KindIncompatible Kind = "incompatible"
+)
+
+// Change is one change to a package's exported API
+type Change struct {
+	// Package is the import path of the changed package
+	Package string `json:"package"`
+	// Symbol is the changed name (e.g. NewClient, Client.Do, Options.Timeout; "" for the package itself)
+	Symbol  string `json:"symbol,omitempty"`
+	Kind    Kind   `json:"kind"`
+	Message string `json:"message"`
+}
+
+// Report is the API comparison of the changed packages
+type Report struct {
+	// Base and Head are the compared revisions
+	Base    string   `json:"base"`
+	Head    string   `json:"head"`
+	Changes []Change `json:"changes"`
+}
+
+// Compare compares the exported API of packages keyed by directory, base against head
+// Packages named main have no importers and are skipped
+func Compare(base, head map[string]*types.Package) []Change {
+	dirs := lo.Uniq(append(slices.Sorted(maps.Keys(base)), slices.Sorted(maps.Keys(head))...))
+
+	var changes []Change
+	for _, dir := range dirs {
+		oldPkg, newPkg := base[dir], head[dir]
+		switch {
+		case oldPkg != nil && oldPkg.Name() == "main", newPkg != nil && newPkg.Name() == "main":
+			continue
+		case oldPkg == nil:
+			changes = append(changes, Change{Package: newPkg.Path(), Kind: KindCompatible, Message: "package added"})
+		case newPkg == nil:
+			changes = append(changes, Change{Package: oldPkg.Path(), Kind: KindIncompatible, Message: "package removed"})
+		default:
+			c := &comparer{pkg: newPkg}
+			c.packages(oldPkg, newPkg)
+			changes = append(changes, c.changes...)
+		}
+	}
+
+	slices.SortStableFunc(changes, func(a, b Change) int {
+		return cmp.Or(cmp.Compare(a.Package, b.Package), cmp.Compare(b.Kind, a.Kind), cmp.Compare(a.Symbol, b.Symbol))
+	})
+	return changes
+}
+
+// Incompatible returns the changes that can break importers
+func (r *Report) Incompatible() []Change {
+	return r.withKind(KindIncompatible)
+}
+
+// Compatible returns the changes that keep importers compiling
+func (r *Report) Compatible() []Change {
+	return r.withKind(KindCompatible)
+}
+
+// Breaking reports whether the report has incompatible changes (false for a nil report)
+func (r *Report) Breaking() bool {
+	return r != nil && len(r.Incompatible()) > 0
+}
+
+// Summary returns the change counts
+func (r *Report) Summary() string {
+	return fmt.Sprintf(SummaryFormat, len(r.Incompatible()), len(r.Compatible()))
+}
+
+// Sections renders the changes as a prompt section
+// Returns nil for a nil report or one without changes
+func (r *Report) Sections() []prompt.Section {
+	if r == nil || len(r.Changes) == 0 {
+		return nil
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf(SectionIntroFormat, shortRev(r.Base), shortRev(r.Head)))
+	for _, group := range []struct {
+		title   string
+		changes []Change
+	}{
+		{"Incompatible", r.Incompatible()},
+		{"Compatible", r.Compatible()},
+	} {
+		if len(group.changes) == 0 {
+			continue
+		}
+		builder.WriteString(group.title + ":\n")
+		for _, change := range group.changes {
+			builder.WriteString("- " + change.String() + "\n")
+		}
+	}
+	return []prompt.Section{{Title: SectionTitle, Body: builder.String()}}
+}
+
+// withKind returns the changes of one kind
+func (r *Report) withKind(kind Kind) []Change {
+	if r == nil {
+		return nil
+	}
+	return lo.Filter(r.Changes, func(c Change, _ int) bool { return c.Kind == kind })
+}
+
+// String formats a change as "`pkg.Symbol`: message"
+func (c Change) String() string {
+	name := c.Package
+	if c.Symbol != "" {
+		name += "." + c.Symbol
+	}
+	return fmt.Sprintf("`%s`: %s", name, c.Message)
+}
+
+// shortRev shortens a commit hash for messages
+func shortRev(rev string) string {
+	if len(rev) > shortRevLength && strings.Trim(rev, "0123456789abcdef") == "" {
+		return rev[:shortRevLength]
+	}
+	return rev
+}
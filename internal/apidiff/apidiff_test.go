@@ -0,0 +1,152 @@
+package apidiff
+
+import (
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trankhanh040147/revcli/internal/goref"
+)
+
+const baseSource = `package lib
+
+import "context"
+
+const Version = "1"
+
+type Options struct {
+	Timeout int
+	Name    string
+}
+
+type Store interface {
+	Get(ctx context.Context, id string) (string, error)
+}
+
+type sealed interface {
+	Kind() string
+	seal()
+}
+
+type Sealed = sealed
+
+type Client struct{}
+
+func (c *Client) Do(ctx context.Context) error { return nil }
+
+func (c *Client) Close() error { return nil }
+
+func New(opts Options) *Client { return nil }
+
+func Old() {}
+`
+
+const headSource = `package lib
+
+import "context"
+
+const Version = "2"
+
+type Options struct {
+	Timeout int64
+	Retries int
+}
+
+type Store interface {
+	Get(ctx context.Context, id string) (string, error)
+	Put(ctx context.Context, id string) error
+}
+
+type sealed interface {
+	Kind() string
+	Name() string
+	seal()
+}
+
+type Sealed = sealed
+
+type Client struct{}
+
+func (c *Client) Do(ctx context.Context, retries int) error { return nil }
+
+func (c *Client) Ping() error { return nil }
+
+func New(opts Options) *Client { return nil }
+
+func Fresh() {}
+`
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	base := loadModule(t, map[string]string{"lib/lib.go": baseSource, "cmd/main.go": "package main\n\nfunc main() {}\n"})
+	head := loadModule(t, map[string]string{"lib/lib.go": headSource, "cmd/main.go": "package main\n\nfunc Main() {}\n"})
+
+	changes := Compare(base, head)
+	require.Equal(t, []Change{
+		{Package: "example.com/lib/lib", Symbol: "Client.Close", Kind: KindIncompatible, Message: "method removed"},
+		{Package: "example.com/lib/lib", Symbol: "Client.Do", Kind: KindIncompatible, Message: "method signature changed from func(ctx context.Context) error to func(ctx context.Context, retries int) error"},
+		{Package: "example.com/lib/lib", Symbol: "Old", Kind: KindIncompatible, Message: "func removed"},
+		{Package: "example.com/lib/lib", Symbol: "Options.Name", Kind: KindIncompatible, Message: "field removed"},
+		{Package: "example.com/lib/lib", Symbol: "Options.Timeout", Kind: KindIncompatible, Message: "field type changed from int to int64"},
+		{Package: "example.com/lib/lib", Symbol: "Store.Put", Kind: KindIncompatible, Message: "method added to interface (breaks implementations)"},
+		{Package: "example.com/lib/lib", Symbol: "Version", Kind: KindIncompatible, Message: `value changed from "1" to "2"`},
+		{Package: "example.com/lib/lib", Symbol: "Client.Ping", Kind: KindCompatible, Message: "method added"},
+		{Package: "example.com/lib/lib", Symbol: "Fresh", Kind: KindCompatible, Message: "func added"},
+		{Package: "example.com/lib/lib", Symbol: "Options.Retries", Kind: KindCompatible, Message: "field added"},
+		{Package: "example.com/lib/lib", Symbol: "Sealed.Name", Kind: KindCompatible, Message: "method added to an interface that can't be implemented outside its package"},
+	}, changes)
+
+	report := &Report{Base: "0123456789abcdef0123", Head: WorkingTree, Changes: changes}
+	require.True(t, report.Breaking())
+	require.Equal(t, "API changes: 7 incompatible, 4 compatible", report.Summary())
+
+	sections := report.Sections()
+	require.Len(t, sections, 1)
+	require.Contains(t, sections[0].Body, "from 0123456789ab to working tree")
+	require.Contains(t, sections[0].Body, "Incompatible:\n- `example.com/lib/lib.Client.Close`: method removed\n")
+}
+
+func TestCompareAddedAndRemovedPackages(t *testing.T) {
+	t.Parallel()
+
+	base := loadModule(t, map[string]string{"old/old.go": "package old\n\nfunc F() {}\n"})
+	head := loadModule(t, map[string]string{"fresh/fresh.go": "package fresh\n\nfunc F() {}\n"})
+
+	require.Equal(t, []Change{
+		{Package: "example.com/lib/fresh", Kind: KindCompatible, Message: "package added"},
+		{Package: "example.com/lib/old", Kind: KindIncompatible, Message: "package removed"},
+	}, Compare(base, head))
+
+	var report *Report
+	require.False(t, report.Breaking())
+	require.Nil(t, report.Sections())
+	require.Nil(t, (&Report{}).Sections())
+}
+
+func TestChangedPackageDirs(t *testing.T) {
+	t.Parallel()
+
+	diff := "diff --git a/lib/lib.go b/lib/lib.go\n+x\ndiff --git a/lib/lib_test.go b/lib/lib_test.go\n+x\n" +
+		"diff --git a/README.md b/README.md\n+x\ndiff --git a/cmd/main.go b/cmd/main.go\n+x\ndiff --git a/lib/other.go b/lib/other.go\n+x\n"
+	require.Equal(t, []string{"cmd", "lib"}, changedPackageDirs(diff))
+}
+
+// loadModule writes a module with the given files and type-checks every package directory
+func loadModule(t *testing.T, files map[string]string) map[string]*types.Package {
+	t.Helper()
+
+	root := t.TempDir()
+	files["go.mod"] = "module example.com/lib\n\ngo 1.22\n"
+	var dirs []string
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		dirs = append(dirs, filepath.Dir(name))
+	}
+	return goref.LoadPackages(root, nil, dirs)
+}
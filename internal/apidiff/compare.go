@@ -0,0 +1,240 @@
+package apidiff
+
+import (
+	"fmt"
+	"go/types"
+	"maps"
+	"slices"
+
+	"github.com/samber/lo"
+)
+
+// comparer collects the changes between two versions of one package
+type comparer struct {
+	// pkg is the new package; its own types are printed unqualified
+	pkg     *types.Package
+	changes []Change
+}
+
+// add records a change
+func (c *comparer) add(kind Kind, symbol, format string, args ...any) {
+	c.changes = append(c.changes, Change{Package: c.pkg.Path(), Symbol: symbol, Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+// typeString prints a type, qualifying other packages by name
+func (c *comparer) typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p.Path() == c.pkg.Path() {
+			return ""
+		}
+		return p.Name()
+	})
+}
+
+// packages compares the exported package-level objects
+func (c *comparer) packages(oldPkg, newPkg *types.Package) {
+	oldScope, newScope := oldPkg.Scope(), newPkg.Scope()
+	for _, name := range oldScope.Names() {
+		old := oldScope.Lookup(name)
+		if !old.Exported() {
+			continue
+		}
+		if new := newScope.Lookup(name); new != nil {
+			c.object(name, old, new)
+		} else {
+			c.add(KindIncompatible, name, "%s removed", objectKind(old))
+		}
+	}
+	for _, name := range newScope.Names() {
+		if new := newScope.Lookup(name); new.Exported() && oldScope.Lookup(name) == nil {
+			c.add(KindCompatible, name, "%s added", objectKind(new))
+		}
+	}
+}
+
+// object compares two versions of a package-level object
+func (c *comparer) object(name string, old, new types.Object) {
+	if objectKind(old) != objectKind(new) {
+		c.add(KindIncompatible, name, "changed from %s to %s", objectKind(old), objectKind(new))
+		return
+	}
+
+	switch old := old.(type) {
+	case *types.TypeName:
+		c.typeName(name, old, new.(*types.TypeName))
+	case *types.Const:
+		newConst := new.(*types.Const)
+		if from, to := c.typeString(old.Type()), c.typeString(newConst.Type()); from != to {
+			c.add(KindIncompatible, name, "type changed from %s to %s", from, to)
+		} else if old.Val().ExactString() != newConst.Val().ExactString() {
+			c.add(KindIncompatible, name, "value changed from %s to %s", old.Val().ExactString(), newConst.Val().ExactString())
+		}
+	default:
+		// Functions and variables
+		if from, to := c.typeString(old.Type()), c.typeString(new.Type()); from != to {
+			c.add(KindIncompatible, name, "type changed from %s to %s", from, to)
+		}
+	}
+}
+
+// typeName compares two versions of a type declaration: its type parameters, fields or methods
+func (c *comparer) typeName(name string, old, new *types.TypeName) {
+	if from, to := c.typeParams(old.Type()), c.typeParams(new.Type()); from != to {
+		c.add(KindIncompatible, name, "type parameters changed from [%s] to [%s]", from, to)
+		return
+	}
+
+	oldUnder, newUnder := old.Type().Underlying(), new.Type().Underlying()
+	switch oldUnder := oldUnder.(type) {
+	case *types.Struct:
+		newStruct, ok := newUnder.(*types.Struct)
+		if !ok {
+			c.add(KindIncompatible, name, "changed from struct to %s", c.typeString(newUnder))
+			return
+		}
+		c.fields(name, oldUnder, newStruct)
+	case *types.Interface:
+		newInterface, ok := newUnder.(*types.Interface)
+		if !ok {
+			c.add(KindIncompatible, name, "changed from interface to %s", c.typeString(newUnder))
+			return
+		}
+		c.interfaceMethods(name, oldUnder, newInterface)
+		return
+	default:
+		if from, to := c.typeString(oldUnder), c.typeString(newUnder); from != to {
+			c.add(KindIncompatible, name, "underlying type changed from %s to %s", from, to)
+			return
+		}
+	}
+	c.methods(name, methodSet(old.Type()), methodSet(new.Type()))
+}
+
+// typeParams prints the type parameters of a named type ("" when it has none)
+func (c *comparer) typeParams(t types.Type) string {
+	named, ok := t.(*types.Named)
+	if !ok || named.TypeParams().Len() == 0 {
+		return ""
+	}
+	params := make([]string, named.TypeParams().Len())
+	for i := range params {
+		param := named.TypeParams().At(i)
+		params[i] = c.typeString(param.Constraint())
+	}
+	return fmt.Sprint(params)
+}
+
+// fields compares the exported fields of two struct versions
+func (c *comparer) fields(name string, old, new *types.Struct) {
+	oldFields, newFields := exportedFields(old), exportedFields(new)
+	for _, fieldName := range slices.Sorted(maps.Keys(oldFields)) {
+		oldField := oldFields[fieldName]
+		newField, ok := newFields[fieldName]
+		switch {
+		case !ok:
+			c.add(KindIncompatible, name+"."+fieldName, "field removed")
+		case c.typeString(oldField.Type()) != c.typeString(newField.Type()):
+			c.add(KindIncompatible, name+"."+fieldName, "field type changed from %s to %s", c.typeString(oldField.Type()), c.typeString(newField.Type()))
+		}
+	}
+	for _, fieldName := range slices.Sorted(maps.Keys(newFields)) {
+		if _, ok := oldFields[fieldName]; !ok {
+			c.add(KindCompatible, name+"."+fieldName, "field added")
+		}
+	}
+}
+
+// interfaceMethods compares two interface versions
+// Adding a method breaks implementations outside the package, unless the interface already had an
+// unexported method (so it can't be implemented outside the package)
+func (c *comparer) interfaceMethods(name string, old, new *types.Interface) {
+	oldMethods, newMethods := interfaceMethodMap(old), interfaceMethodMap(new)
+	sealed := lo.SomeBy(lo.Values(oldMethods), func(m *types.Func) bool { return !m.Exported() })
+
+	c.compareMethods(name, oldMethods, newMethods)
+	for _, methodName := range slices.Sorted(maps.Keys(newMethods)) {
+		if _, ok := oldMethods[methodName]; ok || !newMethods[methodName].Exported() {
+			continue
+		}
+		if sealed {
+			c.add(KindCompatible, name+"."+methodName, "method added to an interface that can't be implemented outside its package")
+		} else {
+			c.add(KindIncompatible, name+"."+methodName, "method added to interface (breaks implementations)")
+		}
+	}
+}
+
+// methods compares the exported method sets of two versions of a non-interface type
+func (c *comparer) methods(name string, old, new map[string]*types.Func) {
+	c.compareMethods(name, old, new)
+	for _, methodName := range slices.Sorted(maps.Keys(new)) {
+		if _, ok := old[methodName]; !ok && new[methodName].Exported() {
+			c.add(KindCompatible, name+"."+methodName, "method added")
+		}
+	}
+}
+
+// compareMethods reports exported methods that were removed or whose signature changed
+func (c *comparer) compareMethods(name string, old, new map[string]*types.Func) {
+	for _, methodName := range slices.Sorted(maps.Keys(old)) {
+		if !old[methodName].Exported() {
+			continue
+		}
+		newMethod, ok := new[methodName]
+		if !ok {
+			c.add(KindIncompatible, name+"."+methodName, "method removed")
+			continue
+		}
+		if from, to := c.typeString(old[methodName].Type()), c.typeString(newMethod.Type()); from != to {
+			c.add(KindIncompatible, name+"."+methodName, "method signature changed from %s to %s", from, to)
+		}
+	}
+}
+
+// methodSet returns the methods of *T by name, including promoted ones
+func methodSet(t types.Type) map[string]*types.Func {
+	methods := make(map[string]*types.Func)
+	set := types.NewMethodSet(types.NewPointer(t))
+	for i := range set.Len() {
+		if fn, ok := set.At(i).Obj().(*types.Func); ok {
+			methods[fn.Name()] = fn
+		}
+	}
+	return methods
+}
+
+// interfaceMethodMap returns the methods of an interface by name, including embedded ones
+func interfaceMethodMap(iface *types.Interface) map[string]*types.Func {
+	methods := make(map[string]*types.Func)
+	for i := range iface.NumMethods() {
+		methods[iface.Method(i).Name()] = iface.Method(i)
+	}
+	return methods
+}
+
+// exportedFields returns the exported fields of a struct by name (embedded fields by their type name)
+func exportedFields(s *types.Struct) map[string]*types.Var {
+	fields := make(map[string]*types.Var)
+	for i := range s.NumFields() {
+		if field := s.Field(i); field.Exported() {
+			fields[field.Name()] = field
+		}
+	}
+	return fields
+}
+
+// objectKind names the kind of a package-level object
+func objectKind(obj types.Object) string {
+	switch obj.(type) {
+	case *types.Func:
+		return "func"
+	case *types.Const:
+		return "const"
+	case *types.Var:
+		return "var"
+	case *types.TypeName:
+		return "type"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,19 @@
+package apidiff
+
+// SectionTitle is the prompt section listing exported API changes
+const SectionTitle = "API Compatibility"
+
+// SectionIntroFormat explains the API compatibility section to the model (base and head revisions)
+const SectionIntroFormat = "Exported API changes of the changed Go packages from %s to %s, type-checked at both revisions. Incompatible changes break code that imports these packages: flag any that look unintended and suggest a compatible alternative.\n\n"
+
+// SummaryFormat summarizes a report (incompatible and compatible change counts)
+const SummaryFormat = "API changes: %d incompatible, %d compatible"
+
+// Index is the base revision that compares the files staged in the index
+const Index = "index"
+
+// WorkingTree labels the head revision when the working tree is compared
+const WorkingTree = "working tree"
+
+// shortRevLength is the length revision hashes are shortened to in messages
+const shortRevLength = 12
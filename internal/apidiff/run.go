@@ -0,0 +1,65 @@
+package apidiff
+
+import (
+	"fmt"
+	"go/types"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/git"
+	"github.com/trankhanh040147/revcli/internal/goref"
+)
+
+// Run compares the exported API of the Go packages a diff changes, between baseRev and the head
+// - baseRev is type-checked in a temporary git worktree (Index type-checks the staged files)
+// - headRev "" compares the working tree at rootDir, overlaid with fileContents (repo-relative path -> content);
+// otherwise headRev is checked out in a second worktree
+// Returns nil when the diff changes no Go packages
+func Run(rootDir, baseRev, headRev, diff string, fileContents map[string]string) (*Report, error) {
+	dirs := changedPackageDirs(diff)
+	if len(dirs) == 0 {
+		return nil, nil
+	}
+
+	base, err := loadRevision(baseRev, dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	if headRev == "" {
+		head := goref.LoadPackages(rootDir, fileContents, dirs)
+		return &Report{Base: baseRev, Head: WorkingTree, Changes: Compare(base, head)}, nil
+	}
+
+	head, err := loadRevision(headRev, dirs)
+	if err != nil {
+		return nil, err
+	}
+	return &Report{Base: baseRev, Head: headRev, Changes: Compare(base, head)}, nil
+}
+
+// loadRevision type-checks the packages in dirs at a revision, checked out in a temporary worktree
+func loadRevision(rev string, dirs []string) (map[string]*types.Package, error) {
+	checkout := git.AddWorktree
+	if rev == Index {
+		checkout = func(string) (string, func(), error) { return git.ExportIndex() }
+	}
+	worktree, remove, err := checkout(rev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check out %s: %w", rev, err)
+	}
+	defer remove()
+
+	return goref.LoadPackages(worktree, nil, dirs), nil
+}
+
+// changedPackageDirs returns the directories of the non-test Go files a diff changes, sorted
+func changedPackageDirs(diff string) []string {
+	paths := lo.FilterMap(git.SplitDiff(diff), func(f git.FileDiff, _ int) (string, bool) {
+		return path.Dir(f.Path), strings.HasSuffix(f.Path, ".go") && !strings.HasSuffix(f.Path, "_test.go")
+	})
+	return slices.Sorted(slices.Values(lo.Uniq(paths)))
+}
@@ -25,12 +25,15 @@ import (
 	"github.com/trankhanh040147/revcli/internal/config"
 	"github.com/trankhanh040147/revcli/internal/csync"
 	"github.com/trankhanh040147/revcli/internal/db"
+	"github.com/trankhanh040147/revcli/internal/finding"
 	"github.com/trankhanh040147/revcli/internal/format"
 	"github.com/trankhanh040147/revcli/internal/history"
+	"github.com/trankhanh040147/revcli/internal/intent"
 	"github.com/trankhanh040147/revcli/internal/log"
 	"github.com/trankhanh040147/revcli/internal/lsp"
 	"github.com/trankhanh040147/revcli/internal/message"
 	"github.com/trankhanh040147/revcli/internal/permission"
+	"github.com/trankhanh040147/revcli/internal/prune"
 	"github.com/trankhanh040147/revcli/internal/pubsub"
 	"github.com/trankhanh040147/revcli/internal/session"
 	"github.com/trankhanh040147/revcli/internal/shell"
@@ -44,6 +47,9 @@ type App struct {
 	Sessions    session.Service
 	Messages    message.Service
 	History     history.Service
+	Findings    finding.Service
+	Summaries   prune.Service
+	Intents     intent.Service
 	Permissions permission.Service
 
 	AgentCoordinator agent.Coordinator
@@ -78,6 +84,9 @@ func New(ctx context.Context, conn *sql.DB, cfg *config.Config) (*App, error) {
 		Sessions:    sessions,
 		Messages:    messages,
 		History:     files,
+		Findings:    finding.NewService(q, conn),
+		Summaries:   prune.NewService(q),
+		Intents:     intent.NewService(q),
 		Permissions: permission.NewPermissionService(cfg.WorkingDir(), skipPermissionsRequests, allowedTools),
 		LSPClients:  csync.NewMap[string, *lsp.Client](),
 
@@ -0,0 +1,118 @@
+// Package autofix applies low-risk verified patches from a review and keeps
+// only those that still build and pass the tests of the packages they touch.
+package autofix
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/gotool"
+	"github.com/trankhanh040147/revcli/internal/patch"
+)
+
+// Result is the outcome for one suggested patch
+type Result struct {
+	Patch   *patch.Patch
+	Applied bool
+	// Reason explains why the patch was skipped or rolled back
+	Reason string
+}
+
+// Applier writes patches to disk and undoes them (patch.Applier)
+type Applier interface {
+	Apply(ctx context.Context, p *patch.Patch) (*patch.Applied, error)
+	Undo(ctx context.Context, applied *patch.Applied) error
+}
+
+// Runner builds a module and runs its tests, from the module's root (gotool.Runner)
+type Runner interface {
+	Build(ctx context.Context) (string, error)
+	Test(ctx context.Context, pkgs []string, flags ...string) (string, error)
+}
+
+// Fixer applies patches one at a time, verifying build and tests after each
+type Fixer struct {
+	applier Applier
+	rootDir string
+	// runner returns the runner for a module's root directory
+	runner func(dir string) Runner
+}
+
+// NewFixer creates a fixer for the repository at rootDir; runner returns the runner for a module's root directory
+func NewFixer(applier Applier, rootDir string, runner func(dir string) Runner) *Fixer {
+	return &Fixer{applier: applier, rootDir: rootDir, runner: runner}
+}
+
+// IsLowRisk reports whether a patch category is safe to apply without review
+func IsLowRisk(p *patch.Patch) bool {
+	return lo.Contains(LowRiskCategories, p.Category)
+}
+
+// Run applies every low-risk, verified patch and rolls back any that breaks the build or tests
+// Returns an error only when auto-fix can't run at all (e.g. the build is already broken)
+func (f *Fixer) Run(ctx context.Context, patches []*patch.Patch) ([]Result, error) {
+	files := lo.FlatMap(patches, func(p *patch.Patch, _ int) []string { return p.Files })
+	for _, module := range gotool.ModulesForFiles(f.rootDir, files) {
+		if output, err := f.build(ctx, module); err != nil {
+			return nil, fmt.Errorf("build is failing before auto-fix; nothing applied: %w\n%s", err, gotool.TrimOutput(output))
+		}
+	}
+
+	results := make([]Result, 0, len(patches))
+	for _, p := range patches {
+		results = append(results, f.fix(ctx, p))
+	}
+	return results, nil
+}
+
+// fix applies a single patch and checks it
+func (f *Fixer) fix(ctx context.Context, p *patch.Patch) Result {
+	switch {
+	case !IsLowRisk(p):
+		return Result{Patch: p, Reason: fmt.Sprintf("category %q is not low-risk", lo.CoalesceOrEmpty(p.Category, "none"))}
+	case !p.Usable():
+		return Result{Patch: p, Reason: "failed verification"}
+	}
+
+	applied, err := f.applier.Apply(ctx, p)
+	if err != nil {
+		return Result{Patch: p, Reason: err.Error()}
+	}
+
+	reason, ok := f.check(ctx, p)
+	if ok {
+		return Result{Patch: p, Applied: true}
+	}
+
+	if err := f.applier.Undo(ctx, applied); err != nil {
+		return Result{Patch: p, Reason: fmt.Sprintf("%s; rollback failed: %v", reason, err)}
+	}
+	return Result{Patch: p, Reason: reason + " (rolled back)"}
+}
+
+// check builds the modules and tests the packages touched by the patch
+func (f *Fixer) check(ctx context.Context, p *patch.Patch) (string, bool) {
+	for _, module := range gotool.ModulesForFiles(f.rootDir, p.Files) {
+		if output, err := f.build(ctx, module); err != nil {
+			return "breaks the build:\n" + gotool.TrimOutput(output), false
+		}
+
+		testCtx, cancel := context.WithTimeout(ctx, gotool.DefaultTimeout)
+		output, err := f.runner(filepath.Join(f.rootDir, module.Dir)).Test(testCtx, module.Packages)
+		cancel()
+		if err != nil {
+			return "breaks tests:\n" + gotool.TrimOutput(output), false
+		}
+	}
+	return "", true
+}
+
+// build runs go build ./... in a module with the default timeout
+func (f *Fixer) build(ctx context.Context, module gotool.Module) (string, error) {
+	buildCtx, cancel := context.WithTimeout(ctx, gotool.DefaultTimeout)
+	defer cancel()
+	return f.runner(filepath.Join(f.rootDir, module.Dir)).Build(buildCtx)
+}
@@ -0,0 +1,121 @@
+package autofix
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trankhanh040147/revcli/internal/patch"
+)
+
+func TestFixSkipsRiskyAndUnverifiedPatches(t *testing.T) {
+	t.Parallel()
+
+	f := NewFixer(nil, "", nil)
+
+	risky := f.fix(t.Context(), &patch.Patch{Category: "logic", Status: patch.StatusValid})
+	require.False(t, risky.Applied)
+	require.Equal(t, `category "logic" is not low-risk`, risky.Reason)
+
+	invalid := f.fix(t.Context(), &patch.Patch{Category: "typo", Status: patch.StatusInvalid})
+	require.False(t, invalid.Applied)
+	require.Equal(t, "failed verification", invalid.Reason)
+}
+
+// stubApplier records applied and undone patches without touching the disk
+type stubApplier struct {
+	applied, undone []*patch.Patch
+}
+
+func (a *stubApplier) Apply(_ context.Context, p *patch.Patch) (*patch.Applied, error) {
+	a.applied = append(a.applied, p)
+	return &patch.Applied{Patch: p}, nil
+}
+
+func (a *stubApplier) Undo(_ context.Context, applied *patch.Applied) error {
+	a.undone = append(a.undone, applied.Patch)
+	return nil
+}
+
+// stubRunner fails the build or the tests while the named file is applied
+type stubRunner struct {
+	applier                 *stubApplier
+	breaksBuild, breaksTest string
+}
+
+func (r *stubRunner) applied(file string) bool {
+	for _, p := range r.applier.applied {
+		if p.Files[0] == file && !lo.Contains(r.applier.undone, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *stubRunner) forDir(string) Runner {
+	return r
+}
+
+func (r *stubRunner) Build(context.Context) (string, error) {
+	if r.applied(r.breaksBuild) {
+		return "undefined: x", errors.New("exit status 1")
+	}
+	return "", nil
+}
+
+func (r *stubRunner) Test(_ context.Context, pkgs []string, _ ...string) (string, error) {
+	if r.applied(r.breaksTest) {
+		return "--- FAIL: TestX", errors.New("exit status 1")
+	}
+	return "ok", nil
+}
+
+func TestRunKeepsPassingFixesAndRollsBackBreakingOnes(t *testing.T) {
+	t.Parallel()
+
+	applier := &stubApplier{}
+	runner := &stubRunner{applier: applier, breaksBuild: "build/build.go", breaksTest: "test/test.go"}
+	passing := &patch.Patch{Category: "typo", Status: patch.StatusValid, Files: []string{"ok/ok.go"}}
+	breaksBuild := &patch.Patch{Category: "lint", Status: patch.StatusValid, Files: []string{"build/build.go"}}
+	breaksTest := &patch.Patch{Category: "error-wrapping", Status: patch.StatusRepaired, Files: []string{"test/test.go"}}
+
+	results, err := NewFixer(applier, t.TempDir(), runner.forDir).Run(t.Context(), []*patch.Patch{passing, breaksBuild, breaksTest})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	require.True(t, results[0].Applied)
+	require.False(t, results[1].Applied)
+	require.Equal(t, "breaks the build:\nundefined: x (rolled back)", results[1].Reason)
+	require.False(t, results[2].Applied)
+	require.Equal(t, "breaks tests:\n--- FAIL: TestX (rolled back)", results[2].Reason)
+	require.Equal(t, []*patch.Patch{breaksBuild, breaksTest}, applier.undone)
+}
+
+func TestRunRefusesWhenBuildAlreadyFails(t *testing.T) {
+	t.Parallel()
+
+	applier := &stubApplier{}
+	// The broken file is "applied" before auto-fix starts
+	applier.applied = []*patch.Patch{{Files: []string{"broken.go"}}}
+	runner := &stubRunner{applier: applier, breaksBuild: "broken.go"}
+
+	results, err := NewFixer(applier, t.TempDir(), runner.forDir).Run(t.Context(), []*patch.Patch{{Category: "typo", Status: patch.StatusValid, Files: []string{"a.go"}}})
+	require.ErrorContains(t, err, "build is failing before auto-fix")
+	require.Nil(t, results)
+	require.Len(t, applier.applied, 1)
+}
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+
+	summary := Summary([]Result{
+		{Patch: &patch.Patch{Category: "typo", Files: []string{"a.go"}}, Applied: true},
+		{Patch: &patch.Patch{Files: []string{"b.go"}}, Reason: "failed verification"},
+	})
+	require.Contains(t, summary, "Auto-fix: 1 applied, 1 skipped")
+	require.Contains(t, summary, "✓ [typo] a.go")
+	require.Contains(t, summary, "✗ [none] b.go — failed verification")
+}
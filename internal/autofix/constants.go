@@ -0,0 +1,5 @@
+package autofix
+
+// LowRiskCategories are patch categories applied by --auto-fix
+// Matches the categories requested in prompt.PatchFormatInstructions
+var LowRiskCategories = []string{"typo", "error-wrapping", "lint"}
@@ -0,0 +1,26 @@
+package autofix
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// Summary renders applied versus skipped fixes as a plain-text report
+func Summary(results []Result) string {
+	applied, skipped := lo.FilterReject(results, func(r Result, _ int) bool { return r.Applied })
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Auto-fix: %d applied, %d skipped\n", len(applied), len(skipped)))
+
+	for _, r := range applied {
+		builder.WriteString(fmt.Sprintf("  ✓ [%s] %s\n", r.Patch.Category, strings.Join(r.Patch.Files, ", ")))
+	}
+	for _, r := range skipped {
+		files := strings.Join(r.Patch.Files, ", ")
+		reason := strings.ReplaceAll(r.Reason, "\n", "\n      ")
+		builder.WriteString(fmt.Sprintf("  ✗ [%s] %s — %s\n", lo.CoalesceOrEmpty(r.Patch.Category, "none"), files, reason))
+	}
+	return builder.String()
+}
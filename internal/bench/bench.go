@@ -0,0 +1,157 @@
+// Package bench runs the benchmarks of the changed Go packages at two revisions
+// and compares them in the spirit of golang.org/x/perf/cmd/benchstat
+package bench
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/prompt"
+)
+
+// Verdict classifies a benchmark comparison
+type Verdict string
+
+// Comparison compares one benchmark metric between the base and head revisions
+type Comparison struct {
+	Key
+	// Base and Head are the medians of the runs
+	Base float64 `json:"base"`
+	Head float64 `json:"head"`
+	// Delta is the change of the median in percent
+	Delta float64 `json:"delta_percent"`
+	// P is the p-value of the difference
+	P       float64 `json:"p"`
+	Runs    [2]int  `json:"runs"`
+	Verdict Verdict `json:"verdict"`
+}
+
+// Report is the benchmark comparison of the changed packages
+type Report struct {
+	// Pattern is the -bench regexp
+	Pattern string `json:"pattern"`
+	// Base and Head are the compared revisions
+	Base        string       `json:"base"`
+	Head        string       `json:"head"`
+	Comparisons []Comparison `json:"comparisons"`
+}
+
+// Compare compares the metrics measured at both revisions, ordered by package, benchmark and unit
+// Metrics measured at only one revision are skipped
+func Compare(base, head map[Key][]float64) []Comparison {
+	keys := slices.SortedFunc(maps.Keys(head), func(a, b Key) int {
+		return cmp.Or(cmp.Compare(a.Package, b.Package), cmp.Compare(a.Name, b.Name), cmp.Compare(a.Unit, b.Unit))
+	})
+
+	var comparisons []Comparison
+	for _, key := range keys {
+		old, ok := base[key]
+		if !ok {
+			continue
+		}
+		c := Comparison{
+			Key:     key,
+			Base:    median(old),
+			Head:    median(head[key]),
+			P:       mannWhitneyP(old, head[key]),
+			Runs:    [2]int{len(old), len(head[key])},
+			Verdict: VerdictUnchanged,
+		}
+		if c.Base != 0 {
+			c.Delta = (c.Head - c.Base) / c.Base * 100
+		}
+		if c.P < Alpha && c.Head != c.Base {
+			// Throughput units (e.g. MB/s) are better when higher, everything else when lower
+			worse := c.Head > c.Base
+			if strings.HasSuffix(key.Unit, "/s") {
+				worse = !worse
+			}
+			c.Verdict = lo.Ternary(worse, VerdictRegression, VerdictImprovement)
+		}
+		comparisons = append(comparisons, c)
+	}
+	return comparisons
+}
+
+// Regressions returns the significant regressions
+func (r *Report) Regressions() []Comparison {
+	return r.withVerdict(VerdictRegression)
+}
+
+// Improvements returns the significant improvements
+func (r *Report) Improvements() []Comparison {
+	return r.withVerdict(VerdictImprovement)
+}
+
+// Summary returns the comparison counts
+func (r *Report) Summary() string {
+	return fmt.Sprintf(SummaryFormat, len(r.Regressions()), len(r.Improvements()), len(r.withVerdict(VerdictUnchanged)))
+}
+
+// Sections renders the regressions and improvements as a prompt section
+// Returns nil for a nil report or one without comparisons
+func (r *Report) Sections() []prompt.Section {
+	if r == nil || len(r.Comparisons) == 0 {
+		return nil
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf(SectionIntroFormat, r.Pattern, shortRev(r.Base), shortRev(r.Head), Count))
+	for _, group := range []struct {
+		title       string
+		comparisons []Comparison
+	}{
+		{"Regressions", r.Regressions()},
+		{"Improvements", r.Improvements()},
+	} {
+		if len(group.comparisons) == 0 {
+			continue
+		}
+		builder.WriteString(group.title + ":\n")
+		for _, c := range group.comparisons {
+			builder.WriteString("- " + c.String() + "\n")
+		}
+	}
+	if unchanged := len(r.withVerdict(VerdictUnchanged)); unchanged > 0 {
+		builder.WriteString(fmt.Sprintf("No significant change: %d metric(s)\n", unchanged))
+	}
+	return []prompt.Section{{Title: SectionTitle, Body: builder.String()}}
+}
+
+// withVerdict returns the comparisons with a verdict
+func (r *Report) withVerdict(verdict Verdict) []Comparison {
+	if r == nil {
+		return nil
+	}
+	return lo.Filter(r.Comparisons, func(c Comparison, _ int) bool { return c.Verdict == verdict })
+}
+
+// String formats a comparison like a benchstat row, e.g.
+// "`example.com/app/git` BenchmarkParse ns/op: 1200 → 1500 (+25.00%, p=0.002 n=6+6)"
+func (c Comparison) String() string {
+	return fmt.Sprintf("`%s` %s %s: %s → %s (%+.2f%%, p=%.3f n=%d+%d)",
+		c.Package, c.Name, c.Unit, formatValue(c.Base), formatValue(c.Head), c.Delta, c.P, c.Runs[0], c.Runs[1])
+}
+
+// formatValue formats a median with 4 significant digits, without an exponent for large values
+func formatValue(value float64) string {
+	if math.Abs(value) >= 1e4 {
+		return strconv.FormatFloat(value, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(value, 'g', 4, 64)
+}
+
+// shortRev shortens a commit hash for messages
+func shortRev(rev string) string {
+	if len(rev) > shortRevLength && strings.Trim(rev, "0123456789abcdef") == "" {
+		return rev[:shortRevLength]
+	}
+	return rev
+}
@@ -0,0 +1,130 @@
+package bench
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	output := `goos: linux
+goarch: amd64
+pkg: example.com/app/git
+cpu: AMD EPYC
+BenchmarkParse-8   	  100000	      1200 ns/op	     512 B/op	       4 allocs/op
+BenchmarkParse-8   	  100000	      1300 ns/op	     512 B/op	       4 allocs/op
+BenchmarkCopy/small-8   	  5000	       100.5 ns/op	  95.20 MB/s
+--- FAIL: BenchmarkBroken-8
+PASS
+ok  	example.com/app/git	3.2s
+pkg: example.com/app/lint
+BenchmarkRun   	      10	 150000000 ns/op
+`
+	require.Equal(t, map[Key][]float64{
+		{Package: "example.com/app/git", Name: "BenchmarkParse", Unit: "ns/op"}:      {1200, 1300},
+		{Package: "example.com/app/git", Name: "BenchmarkParse", Unit: "B/op"}:       {512, 512},
+		{Package: "example.com/app/git", Name: "BenchmarkParse", Unit: "allocs/op"}:  {4, 4},
+		{Package: "example.com/app/git", Name: "BenchmarkCopy/small", Unit: "ns/op"}: {100.5},
+		{Package: "example.com/app/git", Name: "BenchmarkCopy/small", Unit: "MB/s"}:  {95.2},
+		{Package: "example.com/app/lint", Name: "BenchmarkRun", Unit: "ns/op"}:       {150000000},
+	}, Parse(output))
+}
+
+func TestMannWhitneyP(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b []float64
+		want float64
+	}{
+		// One of the 924 orderings of 6+6 samples has every a below every b, in each direction
+		{"separated", []float64{1, 2, 3, 4, 5, 6}, []float64{7, 8, 9, 10, 11, 12}, 2.0 / 924},
+		{"reversed", []float64{7, 8, 9, 10, 11, 12}, []float64{1, 2, 3, 4, 5, 6}, 2.0 / 924},
+		{"interleaved", []float64{1, 3, 5, 7}, []float64{2, 4, 6, 8}, 0.686},
+		{"identical", []float64{5, 5, 5, 5}, []float64{5, 5, 5, 5}, 1},
+		{"too few runs", []float64{1}, []float64{2}, 1},
+		{"empty", nil, []float64{2}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.InDelta(t, tt.want, mannWhitneyP(tt.a, tt.b), 0.001)
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	key := func(name, unit string) Key { return Key{Package: "example.com/app", Name: name, Unit: unit} }
+	base := map[Key][]float64{
+		key("BenchmarkSlow", "ns/op"):     {100, 101, 99, 100, 102, 98},
+		key("BenchmarkFast", "ns/op"):     {200, 201, 199, 200, 202, 198},
+		key("BenchmarkCopy", "MB/s"):      {50, 51, 49, 50, 52, 48},
+		key("BenchmarkSame", "ns/op"):     {10, 11, 10, 9, 10, 11},
+		key("BenchmarkGone", "ns/op"):     {1, 1, 1, 1, 1, 1},
+		key("BenchmarkSlow", "allocs/op"): {2, 2, 2, 2, 2, 2},
+	}
+	head := map[Key][]float64{
+		key("BenchmarkSlow", "ns/op"):     {150, 151, 149, 150, 152, 148},
+		key("BenchmarkFast", "ns/op"):     {100, 101, 99, 100, 102, 98},
+		key("BenchmarkCopy", "MB/s"):      {25, 26, 24, 25, 27, 23},
+		key("BenchmarkSame", "ns/op"):     {10, 9, 11, 10, 10, 11},
+		key("BenchmarkNew", "ns/op"):      {1, 1, 1, 1, 1, 1},
+		key("BenchmarkSlow", "allocs/op"): {2, 2, 2, 2, 2, 2},
+	}
+
+	comparisons := Compare(base, head)
+	verdicts := make(map[string]Verdict)
+	for _, c := range comparisons {
+		verdicts[c.Name+" "+c.Unit] = c.Verdict
+	}
+	require.Equal(t, map[string]Verdict{
+		"BenchmarkCopy MB/s":      VerdictRegression,
+		"BenchmarkFast ns/op":     VerdictImprovement,
+		"BenchmarkSame ns/op":     VerdictUnchanged,
+		"BenchmarkSlow allocs/op": VerdictUnchanged,
+		"BenchmarkSlow ns/op":     VerdictRegression,
+	}, verdicts)
+
+	slow := comparisons[len(comparisons)-1]
+	require.Equal(t, key("BenchmarkSlow", "ns/op"), slow.Key)
+	require.InDelta(t, 100.0, slow.Base, 0.001)
+	require.InDelta(t, 150.0, slow.Head, 0.001)
+	require.InDelta(t, 50.0, slow.Delta, 0.001)
+	require.Equal(t, [2]int{6, 6}, slow.Runs)
+	require.Equal(t, "`example.com/app` BenchmarkSlow ns/op: 100 → 150 (+50.00%, p=0.002 n=6+6)", slow.String())
+	require.Equal(t, "280.7", formatValue(280.66))
+	require.Equal(t, "1067804", formatValue(1067803.7))
+}
+
+func TestSections(t *testing.T) {
+	t.Parallel()
+
+	var nilReport *Report
+	require.Nil(t, nilReport.Sections())
+	require.Nil(t, (&Report{Pattern: "."}).Sections())
+
+	report := &Report{
+		Pattern: "Parse",
+		Base:    "0123456789abcdef0123",
+		Head:    WorkingTree,
+		Comparisons: []Comparison{
+			{Key: Key{Package: "example.com/app", Name: "BenchmarkParse", Unit: "ns/op"}, Base: 100, Head: 150, Delta: 50, P: 0.002, Runs: [2]int{6, 6}, Verdict: VerdictRegression},
+			{Key: Key{Package: "example.com/app", Name: "BenchmarkParse", Unit: "B/op"}, Base: 64, Head: 64, P: 1, Runs: [2]int{6, 6}, Verdict: VerdictUnchanged},
+		},
+	}
+	require.Equal(t, "Benchmarks: 1 regressions, 0 improvements, 1 unchanged", report.Summary())
+
+	sections := report.Sections()
+	require.Len(t, sections, 1)
+	require.Equal(t, SectionTitle, sections[0].Title)
+	require.Contains(t, sections[0].Body, "run at 0123456789ab and working tree")
+	require.Contains(t, sections[0].Body, "Regressions:\n- `example.com/app` BenchmarkParse ns/op: 100 → 150 (+50.00%, p=0.002 n=6+6)\n")
+	require.NotContains(t, sections[0].Body, "Improvements:")
+	require.Contains(t, sections[0].Body, "No significant change: 1 metric(s)")
+}
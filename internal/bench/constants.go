@@ -0,0 +1,36 @@
+package bench
+
+import "time"
+
+// Count is how many times each benchmark runs per revision (benchstat needs at least 4 runs for p < 0.05)
+const Count = 6
+
+// Alpha is the significance level below which a difference is reported
+const Alpha = 0.05
+
+// Timeout bounds the benchmark run of one revision
+const Timeout = 10 * time.Minute
+
+// Verdicts of a comparison
+const (
+	VerdictRegression  Verdict = "regression"
+	VerdictImprovement Verdict = "improvement"
+	// VerdictUnchanged differences aren't significant
+	VerdictUnchanged Verdict = "unchanged"
+)
+
+// SectionTitle is the prompt section with the benchmark comparison
+const SectionTitle = "Benchmark Comparison"
+
+// SectionIntroFormat explains the benchmark comparison to the model (pattern, base and head revisions, run count)
+const SectionIntroFormat = "Benchmarks matching `%s` in the changed Go packages, run at %s and %s (%d runs each) and compared like benchstat " +
+	"(medians, Mann-Whitney U test, p < 0.05). Tie each regression to the change that likely causes it and suggest a fix; don't speculate about performance beyond these measurements.\n\n"
+
+// SummaryFormat summarizes a report (regression, improvement and unchanged counts)
+const SummaryFormat = "Benchmarks: %d regressions, %d improvements, %d unchanged"
+
+// WorkingTree labels the head revision when the working tree is benchmarked
+const WorkingTree = "working tree"
+
+// shortRevLength is the length revision hashes are shortened to in messages
+const shortRevLength = 12
@@ -0,0 +1,51 @@
+package bench
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// procsSuffixRe matches the GOMAXPROCS suffix of a benchmark name, e.g. "-8"
+var procsSuffixRe = regexp.MustCompile(`-\d+$`)
+
+// Key identifies a measured metric of a benchmark
+type Key struct {
+	// Package is the import path of the benchmark's package
+	Package string `json:"package"`
+	// Name is the benchmark name without its GOMAXPROCS suffix
+	Name string `json:"name"`
+	// Unit is the metric, e.g. ns/op, B/op or allocs/op
+	Unit string `json:"unit"`
+}
+
+// Parse parses go test -bench output into the measured values of each benchmark metric
+// Benchmarks are attributed to the package of the last "pkg:" line
+func Parse(output string) map[Key][]float64 {
+	samples := make(map[Key][]float64)
+	pkg := ""
+	for _, line := range strings.Split(output, "\n") {
+		if rest, ok := strings.CutPrefix(line, "pkg: "); ok {
+			pkg = strings.TrimSpace(rest)
+			continue
+		}
+		fields := strings.Fields(line)
+		// Format: BenchmarkName-8 iterations value unit [value unit ...]
+		if len(fields) < 4 || len(fields)%2 != 0 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+		name := procsSuffixRe.ReplaceAllString(fields[0], "")
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				continue
+			}
+			key := Key{Package: pkg, Name: name, Unit: fields[i+1]}
+			samples[key] = append(samples[key], value)
+		}
+	}
+	return samples
+}
@@ -0,0 +1,94 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/git"
+	"github.com/trankhanh040147/revcli/internal/gotool"
+)
+
+// Run runs the benchmarks matching pattern in the Go packages a diff touches, at baseRev and at the head, then compares them
+// Each package runs from the root of its module (the nearest go.mod)
+// - baseRev is checked out in a temporary git worktree
+// - headRev "" benchmarks the working tree at rootDir; otherwise headRev is checked out in a second worktree
+// The revisions run one after the other so they don't compete for the CPU
+// Returns nil when the diff touches no Go packages
+func Run(rootDir, baseRev, headRev, diff, pattern string) (*Report, error) {
+	paths := lo.Map(git.SplitDiff(diff), func(f git.FileDiff, _ int) string { return f.Path })
+	modules := gotool.ModulesForFiles(rootDir, paths)
+	if len(modules) == 0 {
+		return nil, nil
+	}
+
+	base, err := runRevision(baseRev, modules, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var head map[Key][]float64
+	if headRev == "" {
+		head, err = runBenchmarks(rootDir, modules, pattern)
+	} else {
+		head, err = runRevision(headRev, modules, pattern)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Report{
+		Pattern:     pattern,
+		Base:        baseRev,
+		Head:        lo.Ternary(headRev == "", WorkingTree, headRev),
+		Comparisons: Compare(base, head),
+	}, nil
+}
+
+// runRevision runs the benchmarks at a revision, checked out in a temporary worktree
+func runRevision(rev string, modules []gotool.Module, pattern string) (map[Key][]float64, error) {
+	worktree, remove, err := git.AddWorktree(rev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check out %s: %w", rev, err)
+	}
+	defer remove()
+
+	samples, err := runBenchmarks(worktree, modules, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("benchmarks failed at %s: %w", shortRev(rev), err)
+	}
+	return samples, nil
+}
+
+// runBenchmarks runs go test -bench on the packages that exist in dir, without the tests, module by module
+func runBenchmarks(dir string, modules []gotool.Module, pattern string) (map[Key][]float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	samples := make(map[Key][]float64)
+	for _, module := range modules {
+		moduleDir := filepath.Join(dir, module.Dir)
+		// Packages added or deleted by the diff only exist at one revision
+		pkgs := lo.Filter(module.Packages, func(pkg string, _ int) bool {
+			info, err := os.Stat(filepath.Join(moduleDir, pkg))
+			return err == nil && info.IsDir()
+		})
+		if len(pkgs) == 0 {
+			continue
+		}
+
+		// A failing benchmark fails the run, so a failed run only counts when nothing was measured
+		output, runErr := gotool.NewRunner(moduleDir).Test(ctx, pkgs, "-run=^$", "-bench="+pattern, "-benchmem", "-count="+strconv.Itoa(Count))
+		measured := Parse(output)
+		if runErr != nil && len(measured) == 0 {
+			return nil, fmt.Errorf("%w\n%s", runErr, gotool.TrimOutput(output))
+		}
+		maps.Copy(samples, measured)
+	}
+	return samples, nil
+}
@@ -0,0 +1,83 @@
+package bench
+
+import (
+	"math"
+	"slices"
+)
+
+// median returns the median of values (0 for none)
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Sorted(slices.Values(values))
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
+
+// mannWhitneyP returns the two-sided p-value of the Mann-Whitney U test of two samples, like benchstat
+// The exact distribution without ties is used; ties count half, which is slightly conservative
+func mannWhitneyP(a, b []float64) float64 {
+	m, n := len(a), len(b)
+	if m == 0 || n == 0 {
+		return 1
+	}
+
+	u := 0.0
+	for _, x := range a {
+		for _, y := range b {
+			switch {
+			case x < y:
+				u++
+			case x == y:
+				u += 0.5
+			}
+		}
+	}
+
+	// counts[k] is the number of orderings of the pooled samples whose U statistic is k
+	counts := uDistribution(m, n)
+	total := 0.0
+	for _, c := range counts {
+		total += c
+	}
+	lower, upper := 0.0, 0.0
+	for k, c := range counts {
+		if float64(k) <= u {
+			lower += c
+		}
+		if float64(k) >= u {
+			upper += c
+		}
+	}
+	return math.Min(1, 2*math.Min(lower, upper)/total)
+}
+
+// uDistribution returns the number of arrangements of m and n items with each U statistic 0..m*n
+func uDistribution(m, n int) []float64 {
+	// dist[i][j][k] counts arrangements of i and j items with U = k, built up one item at a time
+	dist := make([][][]float64, m+1)
+	for i := range dist {
+		dist[i] = make([][]float64, n+1)
+		for j := range dist[i] {
+			dist[i][j] = make([]float64, i*j+1)
+			if i == 0 || j == 0 {
+				dist[i][j][0] = 1
+				continue
+			}
+			for k := range dist[i][j] {
+				// The largest item is from the first sample (it beats all j others) or from the second
+				if k >= j {
+					dist[i][j][k] += dist[i-1][j][k-j]
+				}
+				if k < len(dist[i][j-1]) {
+					dist[i][j][k] += dist[i][j-1][k]
+				}
+			}
+		}
+	}
+	return dist[m][n]
+}
@@ -0,0 +1,124 @@
+// Package chunk reviews changes that don't fit the context window in chunks and merges the results
+package chunk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"charm.land/fantasy"
+	"github.com/samber/lo"
+	"golang.org/x/sync/errgroup"
+
+	appcontext "github.com/trankhanh040147/revcli/internal/context"
+	"github.com/trankhanh040147/revcli/internal/finding"
+	"github.com/trankhanh040147/revcli/internal/prompt"
+)
+
+// Status is the review state of a chunk
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusReviewing Status = "reviewing"
+	StatusDone      Status = "done"
+	StatusFailed    Status = "failed"
+)
+
+// Progress reports a status change of one chunk
+type Progress struct {
+	Chunk  *appcontext.Chunk
+	Status Status
+	Err    error
+}
+
+// ReviewFunc reviews one chunk and returns the review response
+// It is called again when the provider rate-limits the chunk, so each call should start a fresh session
+type ReviewFunc func(ctx context.Context, c *appcontext.Chunk) (string, error)
+
+// Result is the outcome of one chunk review
+type Result struct {
+	Chunk    *appcontext.Chunk
+	Response string
+	Err      error
+}
+
+// Review reviews chunks concurrently and reports every status change to progress (may be nil)
+// - at most Concurrency chunks run at once; rate-limited chunks are retried with backoff
+// - a failed chunk doesn't stop the others; Review fails only when every chunk failed or ctx is done
+func Review(ctx context.Context, chunks []*appcontext.Chunk, review ReviewFunc, progress func(Progress)) ([]Result, error) {
+	report := func(c *appcontext.Chunk, status Status, err error) {
+		if progress != nil {
+			progress(Progress{Chunk: c, Status: status, Err: err})
+		}
+	}
+
+	results := make([]Result, len(chunks))
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(Concurrency)
+	for i, c := range chunks {
+		g.Go(func() error {
+			report(c, StatusReviewing, nil)
+			response, err := reviewWithBackoff(gCtx, c, review)
+			results[i] = Result{Chunk: c, Response: response, Err: err}
+			report(c, lo.Ternary(err == nil, StatusDone, StatusFailed), err)
+			// A failed chunk is merged as not reviewed; only cancellation stops the review
+			return gCtx.Err()
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	if lo.EveryBy(results, func(r Result) bool { return r.Err != nil }) {
+		return nil, fmt.Errorf("every chunk failed: %w", errors.Join(lo.Map(results, func(r Result, _ int) error { return r.Err })...))
+	}
+	return results, nil
+}
+
+// reviewWithBackoff reviews a chunk, waiting and retrying while the provider rate-limits it
+func reviewWithBackoff(ctx context.Context, c *appcontext.Chunk, review ReviewFunc) (string, error) {
+	delay := RateLimitBackoff
+	for attempt := 0; ; attempt++ {
+		response, err := review(ctx, c)
+		if err == nil || !isRateLimited(err) || attempt == RateLimitRetries {
+			return response, err
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+// isRateLimited reports whether a review failed because the provider rate-limited it
+func isRateLimited(err error) bool {
+	var providerErr *fantasy.ProviderError
+	return errors.As(err, &providerErr) && providerErr.StatusCode == http.StatusTooManyRequests
+}
+
+// MergePrompt dedupes findings across the chunk reviews and builds the prompt of the merge step
+func MergePrompt(results []Result, fileContents map[string]string) string {
+	responses := lo.Map(results, func(r Result, _ int) string { return r.Response })
+	deduped, duplicates := finding.Dedupe(responses, fileContents)
+
+	reviews := lo.Map(results, func(r Result, i int) prompt.Section {
+		body := deduped[i]
+		if r.Err != nil {
+			body = fmt.Sprintf(FailedChunkFormat, r.Err)
+		}
+		return prompt.Section{Title: Title(r.Chunk), Body: body}
+	})
+	return prompt.BuildMergePrompt(reviews, duplicates)
+}
+
+// Title names a chunk and its files
+func Title(c *appcontext.Chunk) string {
+	return fmt.Sprintf(TitleFormat, c.Index, c.Total, strings.Join(c.Paths, ", "))
+}
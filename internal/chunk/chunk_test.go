@@ -0,0 +1,110 @@
+package chunk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/stretchr/testify/require"
+
+	appcontext "github.com/trankhanh040147/revcli/internal/context"
+	"github.com/trankhanh040147/revcli/internal/tokens"
+)
+
+// fileDiff returns a diff that adds lines to path
+func fileDiff(path string, lines int) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n@@ -0,0 +1,%d @@\n", path, path, path, path, lines))
+	for i := range lines {
+		builder.WriteString(fmt.Sprintf("+line %d of %s\n", i, path))
+	}
+	return builder.String()
+}
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	diff := fileDiff("a.go", 300) + fileDiff("b.go", 300) + fileDiff("c.go", 20) + fileDiff("d.go", 20)
+	tokenizer := tokens.NewTokenizer(tokens.FamilyGeneric)
+	// Room for one large file plus both small ones
+	budget := tokens.Budget{
+		ContextWindow: tokenizer.Count(fileDiff("a.go", 300)+fileDiff("c.go", 20)+fileDiff("d.go", 20)) + 400,
+		Tokenizer:     tokenizer,
+	}
+	reviewCtx := &appcontext.ReviewContext{
+		RawDiff:      diff,
+		FileContents: map[string]string{},
+		TokenPlan:    &tokens.Plan{Budget: budget},
+	}
+
+	chunks, err := reviewCtx.Split()
+	require.NoError(t, err)
+	require.Len(t, chunks, 2)
+	require.Equal(t, []string{"a.go", "c.go", "d.go"}, chunks[0].Paths)
+	require.Equal(t, []string{"b.go"}, chunks[1].Paths)
+
+	second := chunks[1]
+	require.Equal(t, 2, second.Index)
+	require.Equal(t, 2, second.Total)
+	require.Contains(t, second.Prompt, "+line 0 of b.go")
+	require.NotContains(t, second.Prompt, "of a.go")
+	require.Contains(t, second.Prompt, "### Chunk 2 of 2")
+	require.Contains(t, second.Prompt, "- `a.go`\n- `c.go`\n- `d.go`\n")
+}
+
+func TestReview(t *testing.T) {
+	t.Parallel()
+
+	chunks := []*appcontext.Chunk{
+		{Index: 1, Total: 3, Paths: []string{"a.go"}},
+		{Index: 2, Total: 3, Paths: []string{"b.go"}},
+		{Index: 3, Total: 3, Paths: []string{"c.go"}},
+	}
+	review := func(_ context.Context, c *appcontext.Chunk) (string, error) {
+		if c.Index == 2 {
+			return "", errors.New("boom")
+		}
+		return "### 🟠 Warnings\n- [logic] Shared helper in util.go:1 is wrong.\n", nil
+	}
+
+	var mu sync.Mutex
+	var statuses []Status
+	results, err := Review(t.Context(), chunks, review, func(p Progress) {
+		mu.Lock()
+		defer mu.Unlock()
+		statuses = append(statuses, p.Status)
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.Error(t, results[1].Err)
+	require.ElementsMatch(t, []Status{StatusReviewing, StatusReviewing, StatusReviewing, StatusDone, StatusDone, StatusFailed}, statuses)
+
+	merge := MergePrompt(results, nil)
+	require.Contains(t, merge, "split into 3 chunks")
+	require.Contains(t, merge, "1 findings raised by several chunks were already removed")
+	require.Equal(t, 1, strings.Count(merge, "Shared helper"))
+	require.Contains(t, merge, "### Chunk 2 of 3 (b.go)\n\nNot reviewed: boom")
+}
+
+func TestReviewAllFailed(t *testing.T) {
+	t.Parallel()
+
+	chunks := []*appcontext.Chunk{{Index: 1, Total: 1}}
+	_, err := Review(t.Context(), chunks, func(context.Context, *appcontext.Chunk) (string, error) {
+		return "", errors.New("boom")
+	}, nil)
+	require.ErrorContains(t, err, "every chunk failed: boom")
+}
+
+func TestIsRateLimited(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, isRateLimited(&fantasy.ProviderError{StatusCode: 429}))
+	require.True(t, isRateLimited(fmt.Errorf("run: %w", &fantasy.RetryError{Errors: []error{&fantasy.ProviderError{StatusCode: 429}}})))
+	require.False(t, isRateLimited(&fantasy.ProviderError{StatusCode: 500}))
+	require.False(t, isRateLimited(errors.New("boom")))
+}
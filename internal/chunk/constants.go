@@ -0,0 +1,18 @@
+package chunk
+
+import "time"
+
+// Concurrency is the number of chunks reviewed at once, kept low to stay under provider rate limits
+const Concurrency = 3
+
+// RateLimitRetries is how often a rate-limited chunk is retried after the provider's own retries
+const RateLimitRetries = 3
+
+// RateLimitBackoff is the first wait before retrying a rate-limited chunk; it doubles on each retry
+const RateLimitBackoff = 10 * time.Second
+
+// FailedChunkFormat replaces the review of a chunk that failed in the merge prompt
+const FailedChunkFormat = "Not reviewed: %v"
+
+// TitleFormat names a chunk and its files
+const TitleFormat = "Chunk %d of %d (%s)"
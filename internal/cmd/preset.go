@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
+	appcontext "github.com/trankhanh040147/revcli/internal/context"
 	"github.com/trankhanh040147/revcli/internal/preset"
 	"github.com/trankhanh040147/revcli/internal/prompt"
 	"github.com/trankhanh040147/revcli/internal/ui"
@@ -23,6 +24,9 @@ var (
 	presetDescription string
 	presetPrompt      string
 	presetUnsetFlag   bool
+	renderStaged      bool
+	renderBase        string
+	renderSystem      bool
 )
 
 // presetCmd represents the preset command
@@ -121,6 +125,23 @@ Examples:
 	RunE: runPresetDefault,
 }
 
+// presetRenderCmd previews the review prompt a preset renders for the current changes
+var presetRenderCmd = &cobra.Command{
+	Use:   "render [name]",
+	Short: "Preview the review prompt for the current changes",
+	Long: `Render the review's user prompt for the current changes without calling the model.
+
+The prompt uses the preset's template, then the repository's .revcli/prompt.tmpl,
+then the built-in layout. Uses the default preset when no name is given.
+
+Examples:
+  revcli preset render                 # Uncommitted changes, default preset
+  revcli preset render strict --base main
+  revcli preset render --system        # System prompt with language rules`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPresetRender,
+}
+
 // presetSystemCmd manages the system prompt
 var presetSystemCmd = &cobra.Command{
 	Use:   "system",
@@ -168,6 +189,7 @@ func init() {
 	presetCmd.AddCommand(presetOpenCmd)
 	presetCmd.AddCommand(presetPathCmd)
 	presetCmd.AddCommand(presetDefaultCmd)
+	presetCmd.AddCommand(presetRenderCmd)
 	presetCmd.AddCommand(presetSystemCmd)
 
 	presetSystemCmd.AddCommand(presetSystemShowCmd)
@@ -178,6 +200,9 @@ func init() {
 	presetCreateCmd.Flags().StringVarP(&presetDescription, "description", "d", "", "Preset description")
 	presetCreateCmd.Flags().StringVarP(&presetPrompt, "prompt", "p", "", "Preset prompt text")
 	presetDefaultCmd.Flags().BoolVar(&presetUnsetFlag, "unset", false, "Clear the default preset")
+	presetRenderCmd.Flags().BoolVarP(&renderStaged, "staged", "s", false, "Render for staged changes only")
+	presetRenderCmd.Flags().StringVarP(&renderBase, "base", "b", "", "Render for the changes against a base branch/commit")
+	presetRenderCmd.Flags().BoolVar(&renderSystem, "system", false, "Render the system prompt, with the language rules of the changes")
 }
 
 // editMultilineText opens the current text in an external editor and returns the edited content
@@ -430,6 +455,40 @@ func runPresetShow(cmd *cobra.Command, args []string) error {
 	fmt.Println(ui.RenderSubtitle("Prompt:"))
 	fmt.Println(p.Prompt)
 
+	if p.Template != "" {
+		fmt.Println()
+		fmt.Println(ui.RenderSubtitle("Template:"))
+		fmt.Println(p.Template)
+	}
+
+	return nil
+}
+
+func runPresetRender(cmd *cobra.Command, args []string) error {
+	if renderStaged && renderBase != "" {
+		return fmt.Errorf("cannot use --staged and --base together. Choose one")
+	}
+
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	p, err := loadActivePreset(name, false)
+	if err != nil {
+		return err
+	}
+
+	// Nothing is sent anywhere, so secrets don't stop the preview
+	reviewCtx, err := appcontext.NewBuilder(renderStaged, true, renderBase).WithPreset(p).Build()
+	if err != nil {
+		return fmt.Errorf("failed to render the review prompt: %w", err)
+	}
+
+	if renderSystem {
+		fmt.Println(reviewCtx.SystemPrompt)
+		return nil
+	}
+	fmt.Print(reviewCtx.UserPrompt)
 	return nil
 }
 
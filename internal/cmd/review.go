@@ -4,22 +4,45 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
 
+	"github.com/trankhanh040147/revcli/internal/agent/tools"
+	"github.com/trankhanh040147/revcli/internal/config"
 	appcontext "github.com/trankhanh040147/revcli/internal/context"
+	"github.com/trankhanh040147/revcli/internal/prune"
 	"github.com/trankhanh040147/revcli/internal/ui"
 )
 
 var (
-	staged        bool
-	model         string
-	force         bool
-	interactive   bool
-	baseBranch    string
-	presetName    string
-	presetReplace bool
+	staged            bool
+	model             string
+	force             bool
+	interactive       bool
+	baseBranch        string
+	presetName        string
+	presetReplace     bool
+	autoFix           bool
+	compareLast       bool
+	verify            bool
+	verifyModel       string
+	chunked           bool
+	autoPrune         bool
+	checkIntent       bool
+	intentFile        string
+	intentInstruction string
+	intentFocus       []string
+	intentIgnore      []string
+	outputFormat      string
+	failBreaking      bool
+	apiDiff           bool
+	callers           bool
+	runTests          bool
+	coverage          bool
+	benchPattern      string
+	toolBudget        int
 )
 
 // reviewCmd represents the review command
@@ -48,7 +71,45 @@ Examples:
 
   # Use preset with replace mode (replaces base prompt)
   revcli review --preset quick --preset-replace
-  revcli review -p quick -R`,
+  revcli review -p quick -R
+
+  # Apply low-risk fixes (typos, error wrapping, lint) that build and pass tests
+  revcli review --auto-fix
+
+  # Double-check every finding against its code with the small model and drop unsupported ones
+  revcli review --verify
+  revcli review --verify --verify-model large
+
+  # Show which findings are new, persisting or resolved since the last review of this branch
+  revcli review --no-interactive --compare-last
+
+  # Review a large change in chunks that each fit the context window, then merge the results
+  revcli review --base main --chunked
+
+  # Set the review intent without the form (e.g. in CI); the last intent of a branch prefills the form
+  revcli review --no-interactive --focus security,logic --ignore "naming,docs" --instruction "Check the retry logic"
+  revcli review --no-interactive --intent-file intent.yaml
+
+  # Check that the branch implements what its commits and linked issues describe, and report gaps
+  revcli review --base main --check-intent
+
+  # Summarize the least relevant files with the small model when the change doesn't fit the context window
+  revcli review --base main --auto-prune
+
+  # Run the tests of the changed packages and show failures to the reviewer
+  revcli review --base main --run-tests
+
+  # List the changed lines no test runs, so the reviewer can suggest missing test cases
+  revcli review --base main --coverage
+
+  # Compare the changed packages' benchmarks with the base branch and report significant regressions
+  revcli review --base main --preset performance --bench .
+
+  # Let the reviewer make up to 40 read-only tool calls (view, grep, glob, ls) to check callers
+  revcli review --base main --tool-budget 40
+
+  # Print the review as JSON and exit non-zero on incompatible exported API changes (e.g. in CI)
+  revcli review --base main --output json --fail-on-breaking`,
 	RunE: runReview,
 }
 
@@ -63,6 +124,25 @@ func init() {
 	reviewCmd.Flags().BoolP("no-interactive", "I", false, "Disable interactive chat mode")
 	reviewCmd.Flags().StringVarP(&presetName, "preset", "p", "", "Review preset (quick, strict, security, performance, logic, style, typo, naming)")
 	reviewCmd.Flags().BoolVarP(&presetReplace, "preset-replace", "R", false, "Replace base prompt with preset prompt instead of appending")
+	reviewCmd.Flags().BoolVar(&autoFix, "auto-fix", false, "Apply verified low-risk fixes, keeping only those that build and pass tests (non-interactive)")
+	reviewCmd.Flags().BoolVar(&verify, "verify", false, "Confirm, downgrade or drop each finding with a second model call that sees only the finding and its code")
+	reviewCmd.Flags().StringVar(&verifyModel, "verify-model", string(config.SelectedModelTypeSmall), "Model used by --verify (small or large)")
+	reviewCmd.Flags().BoolVar(&chunked, "chunked", false, "Review in chunks that each fit the model's context window and merge the results (automatic when the change doesn't fit)")
+	reviewCmd.Flags().BoolVar(&autoPrune, "auto-prune", false, "When the change doesn't fit the model's context window, replace the least relevant files with a summary from the small model")
+	reviewCmd.Flags().StringVar(&intentInstruction, "instruction", "", "Custom review instruction (sets the intent without the form, e.g. in CI)")
+	reviewCmd.Flags().StringSliceVar(&intentFocus, "focus", nil, "Focus areas: security, performance, logic, style, typo, naming (comma-separated)")
+	reviewCmd.Flags().StringSliceVar(&intentIgnore, "ignore", nil, "What the reviewer should ignore (comma-separated, e.g. \"naming,docs\")")
+	reviewCmd.Flags().StringVar(&intentFile, "intent-file", "", "YAML file with the review intent (instruction, focus, ignore, check_intent); flags override it")
+	reviewCmd.Flags().BoolVar(&checkIntent, "check-intent", false, "Ask the reviewer whether the diff implements the stated intent (instructions, branch, commits, linked issues) and report gaps")
+	reviewCmd.Flags().BoolVar(&compareLast, "compare-last", false, "Report new, persisting and resolved findings since the last review of this branch (the TUI always shows badges)")
+	reviewCmd.Flags().StringVar(&outputFormat, "output", outputText, "Output format: text, or json (non-interactive; the JSON report goes to stdout, progress to stderr)")
+	reviewCmd.Flags().BoolVar(&callers, "callers", true, "List the callers of changed exported Go symbols (--callers=false skips type-checking the whole module)")
+	reviewCmd.Flags().BoolVar(&runTests, "run-tests", false, "Run go test on the changed Go packages and add failing tests and their output to the review")
+	reviewCmd.Flags().BoolVar(&coverage, "coverage", false, "Run the changed packages' tests with a coverage profile and report the changed lines they don't cover (implies --run-tests)")
+	reviewCmd.Flags().StringVar(&benchPattern, "bench", "", "Run the changed packages' benchmarks matching this regexp at the base revision and at head, and report significant regressions")
+	reviewCmd.Flags().IntVar(&toolBudget, "tool-budget", tools.DefaultToolBudget, "Read-only tool calls (view, grep, glob, ls, lsp_references) the reviewer may make per prompt to explore the repository (0 disables them)")
+	reviewCmd.Flags().BoolVar(&apiDiff, "api-diff", false, "Compare the exported API of the changed Go packages with the base revision (implied by --fail-on-breaking and --output json)")
+	reviewCmd.Flags().BoolVar(&failBreaking, "fail-on-breaking", false, "Exit with an error when the exported Go API has incompatible changes (or can't be compared)")
 }
 
 func runReview(cmd *cobra.Command, args []string) error {
@@ -71,6 +151,21 @@ func runReview(cmd *cobra.Command, args []string) error {
 		interactive = false
 	}
 
+	// Auto-fix runs unattended after the review
+	if autoFix {
+		interactive = false
+	}
+
+	// The JSON report owns stdout, so everything else goes to stderr
+	if outputFormat != outputText && outputFormat != outputJSON {
+		return fmt.Errorf("invalid --output %q: must be text or json", outputFormat)
+	}
+	var out io.Writer = os.Stdout
+	if outputFormat == outputJSON {
+		interactive = false
+		out = os.Stderr
+	}
+
 	// Create context
 	ctx := context.Background()
 
@@ -78,6 +173,10 @@ func runReview(cmd *cobra.Command, args []string) error {
 	if staged && baseBranch != "" {
 		return fmt.Errorf("cannot use --staged and --base together. Choose one")
 	}
+	verifyModelType := config.SelectedModelType(verifyModel)
+	if verifyModelType != config.SelectedModelTypeSmall && verifyModelType != config.SelectedModelTypeLarge {
+		return fmt.Errorf("invalid --verify-model %q: must be small or large", verifyModel)
+	}
 
 	// Setup app instance
 	appInstance, err := setupApp(cmd)
@@ -97,31 +196,45 @@ func runReview(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Step 0: Collect intent (if interactive)
-	var intent *appcontext.Intent
-	if interactive {
-		fmt.Println(ui.RenderTitle("🔍 Code Review"))
-		fmt.Println()
-		fmt.Println("Configure your review intent (press Ctrl+C to skip)...")
-		fmt.Println()
-		var err error
-		intent, err = ui.CollectIntent(interactive)
-		if err != nil {
-			return fmt.Errorf("failed to collect intent: %w", err)
+	// Step 0: Collect intent from the flags and intent file, then the form (if interactive)
+	intent, err := resolveIntent(ctx, cmd, appInstance, out)
+	if err != nil {
+		return err
+	}
+	if checkIntent {
+		if intent == nil {
+			intent = &appcontext.Intent{}
 		}
-		fmt.Println()
+		intent.CheckIntent = true
 	}
 
 	// Step 1: Build the review context
-	printReviewHeader(os.Stdout, activePreset, baseBranch, staged)
+	printReviewHeader(out, activePreset, baseBranch, staged)
 
-	builder := appcontext.NewBuilder(staged, force, baseBranch)
+	builder := appcontext.NewBuilder(staged, force, baseBranch).
+		WithBudget(reviewBudget(appInstance)).
+		WithPreset(activePreset).
+		WithConventionPaths(appInstance.Config().Options.ReviewContextPaths).
+		WithAnalyzers(appInstance.Config().Options.Analyzers).
+		WithLSPClients(appInstance.LSPClients).
+		WithAPIDiff(apiDiff || failBreaking || outputFormat == outputJSON).
+		WithCallers(callers).
+		WithTests(runTests).
+		WithCoverage(coverage).
+		WithBenchmarks(benchPattern).
+		WithToolBudget(toolBudget)
+	if runTests || coverage {
+		fmt.Fprintln(out, "🧪 Running go test on the changed packages...")
+	}
+	if benchPattern != "" {
+		fmt.Fprintf(out, "⏱️  Running benchmarks matching %q at the base revision and at head...\n", benchPattern)
+	}
 	reviewCtx, err := buildReviewContext(builder, intent)
 	if err != nil {
 		// Check if it's a secrets error using errors.Is/As
 		var secretsErr appcontext.SecretsError
 		if errors.As(err, &secretsErr) {
-			if printErr := printSecretsWarning(os.Stdout, secretsErr.Matches); printErr != nil {
+			if printErr := printSecretsWarning(out, secretsErr.Matches); printErr != nil {
 				return printErr
 			}
 			return ErrSecretsDetected
@@ -131,12 +244,39 @@ func runReview(cmd *cobra.Command, args []string) error {
 
 	// Check if there are changes to review
 	if !reviewCtx.HasChanges() {
-		fmt.Println(ui.RenderWarning("No changes detected. Make sure you have uncommitted changes."))
+		fmt.Fprintln(out, ui.RenderWarning("No changes detected. Make sure you have uncommitted changes."))
 		return nil
 	}
+	if reviewCtx.APIError != nil {
+		fmt.Fprintln(out, ui.RenderWarning(reviewCtx.APIError.Error()))
+	}
+
+	if verify {
+		reviewCtx.VerifyModel = verifyModelType
+	}
+	// Prune the least relevant files before falling back to chunks
+	if autoPrune && !reviewCtx.TokenPlan.Fits() {
+		pruned, err := prune.Auto(ctx, reviewCtx, ui.NewSummarizer(appInstance))
+		if err != nil {
+			return fmt.Errorf("failed to prune files: %w", err)
+		}
+		if len(pruned) > 0 {
+			fmt.Fprintln(out, ui.RenderSuccess(fmt.Sprintf("✂️  Pruned %d file(s) with the small model to fit the context window", len(pruned))))
+		}
+	}
+	// Split changes that don't fit the context window into chunks reviewed concurrently
+	if chunked || !reviewCtx.TokenPlan.Fits() {
+		chunks, err := reviewCtx.Split()
+		if err != nil {
+			return fmt.Errorf("failed to split the review into chunks: %w", err)
+		}
+		if len(chunks) > 1 {
+			reviewCtx.Chunks = chunks
+		}
+	}
 
 	// Print detailed summary with file list
-	printContextSummary(os.Stdout, reviewCtx)
+	printContextSummary(out, reviewCtx)
 
 	// Step 2: Create session
 	sessionTitle := "Code Review"
@@ -147,17 +287,57 @@ func runReview(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create session: %w", err)
 	}
-
-	// Build prompt and attachments
-	prompt := buildReviewPrompt(reviewCtx, activePreset)
-	_ = buildAttachments(reviewCtx) // Attachments are built in model_review.go
+	// Review with the reviewer's system prompt instead of the coding agent's
+	appInstance.AgentCoordinator.SetSystemPrompt(session.ID, reviewCtx.SystemPrompt)
+	// Explore the repository only through the read-only tools, within the tool-call budget
+	appInstance.AgentCoordinator.SetReadOnlyTools(session.ID, ui.ReadOnlyToolPolicy(toolBudget))
 
 	// Step 3: Run the review
 	if interactive {
 		// Interactive TUI mode
-		return ui.Run(reviewCtx, appInstance, session.ID, activePreset)
+		if err := ui.Run(reviewCtx, appInstance, session.ID, activePreset); err != nil {
+			return err
+		}
+		return checkBreaking(reviewCtx)
+	}
+
+	// Non-interactive mode - stream via coordinator so suggested patches can be verified
+	// Nobody is there to answer permission prompts; only the read-only tools run unattended
+	appInstance.Permissions.AutoApproveSessionTools(session.ID, tools.ReadOnlyToolNames...)
+	result, err := ui.RunSimple(ctx, out, reviewCtx, appInstance, session.ID, activePreset)
+	if err != nil {
+		return err
+	}
+
+	if compareLast {
+		if err := runCompareLast(ctx, out, appInstance, reviewCtx, session.ID, result.Findings); err != nil {
+			return err
+		}
+	}
+	if autoFix {
+		if err := runAutoFix(ctx, out, appInstance, session.ID, result.Patches); err != nil {
+			return err
+		}
+	}
+	if outputFormat == outputJSON {
+		if err := writeJSONReport(os.Stdout, reviewCtx, result); err != nil {
+			return err
+		}
 	}
+	return checkBreaking(reviewCtx)
+}
 
-	// Non-interactive mode - use app.RunNonInteractive
-	return appInstance.RunNonInteractive(ctx, os.Stdout, prompt, false)
+// checkBreaking fails the review with --fail-on-breaking when the exported API has incompatible changes
+func checkBreaking(reviewCtx *appcontext.ReviewContext) error {
+	if !failBreaking {
+		return nil
+	}
+	// The check can't pass when the comparison didn't run
+	if reviewCtx.APIError != nil {
+		return fmt.Errorf("--fail-on-breaking: %w", reviewCtx.APIError)
+	}
+	if !reviewCtx.APIReport.Breaking() {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrBreakingChanges, reviewCtx.APIReport.Summary())
 }
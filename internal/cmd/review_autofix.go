@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/trankhanh040147/revcli/internal/app"
+	"github.com/trankhanh040147/revcli/internal/autofix"
+	"github.com/trankhanh040147/revcli/internal/git"
+	"github.com/trankhanh040147/revcli/internal/gotool"
+	"github.com/trankhanh040147/revcli/internal/patch"
+	"github.com/trankhanh040147/revcli/internal/ui"
+)
+
+// runAutoFix applies low-risk verified patches and prints applied vs skipped fixes
+func runAutoFix(ctx context.Context, w io.Writer, appInstance *app.App, sessionID string, patches []*patch.Patch) error {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, ui.RenderTitle("🔧 Auto-fix"))
+
+	if len(patches) == 0 {
+		fmt.Fprintln(w, ui.RenderHelp("No suggested patches to apply."))
+		return nil
+	}
+
+	rootDir, err := git.GetGitRoot()
+	if err != nil {
+		return fmt.Errorf("auto-fix: %w", err)
+	}
+	applier, err := patch.NewApplier(appInstance.History, sessionID)
+	if err != nil {
+		return fmt.Errorf("auto-fix: %w", err)
+	}
+
+	fixer := autofix.NewFixer(applier, rootDir, func(dir string) autofix.Runner { return gotool.NewRunner(dir) })
+	results, err := fixer.Run(ctx, patches)
+	if err != nil {
+		return fmt.Errorf("auto-fix: %w", err)
+	}
+
+	fmt.Fprintln(w, autofix.Summary(results))
+	return nil
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/trankhanh040147/revcli/internal/app"
+	appcontext "github.com/trankhanh040147/revcli/internal/context"
+	"github.com/trankhanh040147/revcli/internal/finding"
+	"github.com/trankhanh040147/revcli/internal/ui"
+)
+
+// runCompareLast prints which findings are new, persisting or resolved since the previous run on the branch
+func runCompareLast(ctx context.Context, w io.Writer, appInstance *app.App, reviewCtx *appcontext.ReviewContext, sessionID string, findings []*finding.Finding) error {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, ui.RenderTitle("🔁 Compared With Last Run"))
+
+	comparison, err := ui.CompareLastRun(ctx, appInstance.Findings, reviewCtx, sessionID, findings)
+	if err != nil {
+		return fmt.Errorf("compare-last: %w", err)
+	}
+	if comparison == nil {
+		fmt.Fprintln(w, ui.RenderHelp(fmt.Sprintf("No previous review on branch %s to compare with.", reviewCtx.Branch)))
+		return nil
+	}
+
+	fmt.Fprint(w, comparison.Report())
+	return nil
+}
@@ -1,11 +1,13 @@
 package cmd
 
 import (
-	"path/filepath"
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
 
+	"github.com/trankhanh040147/revcli/internal/app"
+	"github.com/trankhanh040147/revcli/internal/config"
 	appcontext "github.com/trankhanh040147/revcli/internal/context"
-	"github.com/trankhanh040147/revcli/internal/message"
 	"github.com/trankhanh040147/revcli/internal/preset"
+	"github.com/trankhanh040147/revcli/internal/tokens"
 )
 
 // loadActivePreset loads the active preset based on presetName or default preset
@@ -45,27 +47,17 @@ func buildReviewContext(builder *appcontext.Builder, intent *appcontext.Intent)
 	return builder.Build()
 }
 
-// buildReviewPrompt builds the review prompt from context and preset
-// TODO: using preset later
-func buildReviewPrompt(reviewCtx *appcontext.ReviewContext, preset *preset.Preset) string {
-	// Start with the user prompt from context
-	prompt := reviewCtx.UserPrompt
+// reviewBudget returns the token budget of the review (large) model from its catwalk metadata
+func reviewBudget(appInstance *app.App) tokens.Budget {
+	model := appInstance.AgentCoordinator.Model()
 
-	// If there are pruned files, the prompt should already include them
-	// (handled by prompt.BuildReviewPromptWithPruning)
-	return prompt
-}
-
-// buildAttachments converts review context files to message attachments
-func buildAttachments(reviewCtx *appcontext.ReviewContext) []message.Attachment {
-	var attachments []message.Attachment
-	for filePath, content := range reviewCtx.FileContents {
-		attachments = append(attachments, message.Attachment{
-			FilePath: filePath,
-			FileName: filepath.Base(filePath),
-			MimeType: "text/plain",
-			Content:  []byte(content),
-		})
+	var providerType catwalk.Type
+	if providerCfg := appInstance.Config().GetProviderForModel(config.SelectedModelTypeLarge); providerCfg != nil {
+		providerType = providerCfg.Type
+	}
+	maxOutput := model.ModelCfg.MaxTokens
+	if maxOutput == 0 {
+		maxOutput = model.CatwalkCfg.DefaultMaxTokens
 	}
-	return attachments
+	return tokens.NewBudget(model.CatwalkCfg.ContextWindow, maxOutput, tokens.FamilyFor(providerType, model.ModelCfg.Model))
 }
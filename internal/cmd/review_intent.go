@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trankhanh040147/revcli/internal/app"
+	appcontext "github.com/trankhanh040147/revcli/internal/context"
+	"github.com/trankhanh040147/revcli/internal/git"
+	"github.com/trankhanh040147/revcli/internal/intent"
+	"github.com/trankhanh040147/revcli/internal/ui"
+)
+
+// resolveIntent returns the review intent: the last intent of the branch (interactive only),
+// replaced by --intent-file and overridden by --instruction, --focus and --ignore, then edited in the form
+// The result is saved as the branch's last intent; nil when no intent is set
+func resolveIntent(ctx context.Context, cmd *cobra.Command, appInstance *app.App, out io.Writer) (*appcontext.Intent, error) {
+	repoRoot, branch, ok := intentKey()
+
+	var resolved *appcontext.Intent
+	if interactive && ok {
+		last, err := appInstance.Intents.Last(ctx, repoRoot, branch)
+		if err != nil {
+			fmt.Fprintln(out, ui.RenderWarning(err.Error()))
+		}
+		resolved = last
+	}
+
+	if intentFile != "" {
+		fromFile, err := intent.LoadFile(intentFile)
+		if err != nil {
+			return nil, err
+		}
+		resolved = fromFile
+	}
+
+	overrides := intentOverrides(cmd)
+	if overrides.Instruction != nil || overrides.Focus != nil || overrides.Ignore != nil {
+		var err error
+		resolved, err = intent.Apply(resolved, overrides)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --focus: %w", err)
+		}
+	}
+
+	if interactive {
+		fmt.Println(ui.RenderTitle("🔍 Code Review"))
+		fmt.Println()
+		fmt.Println("Configure your review intent (press Ctrl+C to skip)...")
+		fmt.Println()
+		var err error
+		resolved, err = ui.CollectIntent(interactive, resolved)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect intent: %w", err)
+		}
+		fmt.Println()
+	}
+
+	if resolved != nil && ok {
+		if err := appInstance.Intents.Save(ctx, repoRoot, branch, resolved); err != nil {
+			fmt.Fprintln(out, ui.RenderWarning(err.Error()))
+		}
+	}
+	return resolved, nil
+}
+
+// intentOverrides returns the intent fields set on the command line
+func intentOverrides(cmd *cobra.Command) intent.Overrides {
+	var overrides intent.Overrides
+	if cmd.Flags().Changed("instruction") {
+		overrides.Instruction = &intentInstruction
+	}
+	if cmd.Flags().Changed("focus") {
+		overrides.Focus = append([]string{}, intentFocus...)
+	}
+	if cmd.Flags().Changed("ignore") {
+		overrides.Ignore = append([]string{}, intentIgnore...)
+	}
+	return overrides
+}
+
+// intentKey returns the repository root and branch the last intent is saved under
+// ok is false outside a repository and on a detached HEAD
+func intentKey() (repoRoot, branch string, ok bool) {
+	repoRoot, err := git.GetGitRoot()
+	if err != nil {
+		return "", "", false
+	}
+	branch, err = git.CurrentBranch()
+	if err != nil || branch == "HEAD" {
+		return "", "", false
+	}
+	return repoRoot, branch, true
+}
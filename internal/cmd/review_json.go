@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bytedance/sonic"
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/apidiff"
+	"github.com/trankhanh040147/revcli/internal/bench"
+	appcontext "github.com/trankhanh040147/revcli/internal/context"
+	"github.com/trankhanh040147/revcli/internal/finding"
+	"github.com/trankhanh040147/revcli/internal/lint"
+	"github.com/trankhanh040147/revcli/internal/testrun"
+	"github.com/trankhanh040147/revcli/internal/ui"
+)
+
+// Formats of --output
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// reviewReport is the document printed by --output json
+type reviewReport struct {
+	Branch   string          `json:"branch,omitempty"`
+	HeadSHA  string          `json:"head_sha,omitempty"`
+	Base     string          `json:"base,omitempty"`
+	Review   string          `json:"review"`
+	Findings []reportFinding `json:"findings"`
+	// API compares the exported API of the changed Go packages (omitted when none changed)
+	API *apidiff.Report `json:"api,omitempty"`
+	// Breaking is true when API has incompatible changes
+	Breaking bool `json:"breaking"`
+	// ToolFindings are the static analyzers' findings on the changed lines, tagged with their tool
+	ToolFindings []lint.Diagnostic `json:"tool_findings,omitempty"`
+	// ToolFailures are the analyzers that couldn't run
+	ToolFailures []lint.Failure `json:"tool_failures,omitempty"`
+	// Tests are the go test results of the changed packages (omitted without --run-tests)
+	Tests *testrun.Report `json:"tests,omitempty"`
+	// Coverage is the share of changed executable lines the tests ran, with the uncovered lines per file
+	// (omitted without --coverage)
+	Coverage *testrun.Coverage `json:"coverage,omitempty"`
+	// Benchmarks compares the changed packages' benchmarks with the base revision (omitted without --bench)
+	Benchmarks *bench.Report `json:"benchmarks,omitempty"`
+	// BenchmarkRegressions are the significant regressions among Benchmarks
+	BenchmarkRegressions []bench.Comparison `json:"benchmark_regressions,omitempty"`
+}
+
+// reportFinding is a finding in the JSON report
+type reportFinding struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"`
+	Category string `json:"category,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+	// Verdict is set when the finding went through --verify
+	Verdict string `json:"verdict,omitempty"`
+}
+
+// writeJSONReport prints the review result as JSON
+func writeJSONReport(w io.Writer, reviewCtx *appcontext.ReviewContext, result *ui.SimpleResult) error {
+	report := reviewReport{
+		Branch:               reviewCtx.Branch,
+		HeadSHA:              reviewCtx.HeadSHA,
+		Base:                 baseBranch,
+		Review:               result.Response,
+		Findings:             lo.Map(result.Findings, func(f *finding.Finding, _ int) reportFinding { return newReportFinding(f) }),
+		API:                  reviewCtx.APIReport,
+		Breaking:             reviewCtx.APIReport.Breaking(),
+		Tests:                reviewCtx.TestReport,
+		Benchmarks:           reviewCtx.BenchReport,
+		BenchmarkRegressions: reviewCtx.BenchReport.Regressions(),
+	}
+	if reviewCtx.TestReport != nil {
+		report.Coverage = reviewCtx.TestReport.Coverage
+	}
+	if reviewCtx.LintReport != nil {
+		report.ToolFindings = reviewCtx.LintReport.Diagnostics
+		report.ToolFailures = reviewCtx.LintReport.Failures
+	}
+
+	data, err := sonic.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON report: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// newReportFinding converts a finding for the JSON report
+func newReportFinding(f *finding.Finding) reportFinding {
+	rf := reportFinding{
+		ID:       f.ID,
+		Severity: f.Severity,
+		Category: f.Category,
+		Path:     f.Path,
+		Line:     f.Line,
+		Message:  f.Message,
+	}
+	if f.Verification != nil {
+		rf.Verdict = string(f.Verification.Verdict)
+	}
+	return rf
+}
@@ -13,6 +13,9 @@ import (
 // ErrSecretsDetected is returned when secrets are detected in the code
 var ErrSecretsDetected = fmt.Errorf("review aborted due to potential secrets")
 
+// ErrBreakingChanges is returned by --fail-on-breaking when the exported API has incompatible changes
+var ErrBreakingChanges = fmt.Errorf("incompatible API changes detected")
+
 // printReviewHeader prints the review header with preset and comparison info
 func printReviewHeader(w io.Writer, preset *preset.Preset, baseBranch string, staged bool) {
 	fmt.Fprintln(w, ui.RenderTitle("🔍 Code Review"))
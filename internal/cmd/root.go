@@ -20,9 +20,13 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/trankhanh040147/revcli/internal/app"
 	"github.com/trankhanh040147/revcli/internal/config"
+	"github.com/trankhanh040147/revcli/internal/conventions"
 	"github.com/trankhanh040147/revcli/internal/db"
 	"github.com/trankhanh040147/revcli/internal/event"
+	"github.com/trankhanh040147/revcli/internal/finding"
+	"github.com/trankhanh040147/revcli/internal/fsext"
 	"github.com/trankhanh040147/revcli/internal/projects"
+	"github.com/trankhanh040147/revcli/internal/prompt"
 	"github.com/trankhanh040147/revcli/internal/stringext"
 	"github.com/trankhanh040147/revcli/internal/version"
 )
@@ -252,6 +256,13 @@ func ResolveCwd(cmd *cobra.Command) (string, error) {
 	return cwd, nil
 }
 
+// sharedDataFiles are the files in the data directory that are committed with the repository
+var sharedDataFiles = []string{
+	finding.SuppressionsFileName,
+	filepath.Base(conventions.RepoFile),
+	filepath.Base(prompt.RepoTemplateFile),
+}
+
 func createDotRevcliDir(dir string) error {
 	if err := os.MkdirAll(dir, 0o700); err != nil {
 		return fmt.Errorf("failed to create data directory: %q %w", dir, err)
@@ -264,7 +275,8 @@ func createDotRevcliDir(dir string) error {
 		}
 	}
 
-	return nil
+	// Files shared with the team stay tracked, also in data directories created by older versions
+	return fsext.Unignore(dir, sharedDataFiles...)
 }
 
 func shouldQueryTerminalVersion(env uv.Environ) bool {
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// checkIgnored runs git check-ignore, which exits 0 when git ignores path
+func checkIgnored(t *testing.T, repo, path string) bool {
+	t.Helper()
+	cmd := exec.Command("git", "check-ignore", "-q", path)
+	cmd.Dir = repo
+	return cmd.Run() == nil
+}
+
+func TestCreateDotRevcliDir(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		// gitIgnore is an existing .gitignore ("" for a new data directory)
+		gitIgnore string
+	}{
+		{name: "new data directory"},
+		{name: "data directory of an older version", gitIgnore: "*\n!suppressions.yaml\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := t.TempDir()
+			require.NoError(t, exec.Command("git", "init", "-q", repo).Run())
+			dataDir := filepath.Join(repo, ".revcli")
+			if tt.gitIgnore != "" {
+				require.NoError(t, os.MkdirAll(dataDir, 0o700))
+				require.NoError(t, os.WriteFile(filepath.Join(dataDir, ".gitignore"), []byte(tt.gitIgnore), 0o644))
+			}
+
+			require.NoError(t, createDotRevcliDir(dataDir))
+			require.NoError(t, createDotRevcliDir(dataDir))
+			for _, shared := range sharedDataFiles {
+				require.False(t, checkIgnored(t, repo, filepath.Join(".revcli", shared)), shared)
+			}
+			require.False(t, checkIgnored(t, repo, ".revcli/REVIEW.md"))
+			require.False(t, checkIgnored(t, repo, ".revcli/prompt.tmpl"))
+			require.True(t, checkIgnored(t, repo, ".revcli/revcli.db"))
+
+			// Running again doesn't add the rules twice
+			gitIgnore, err := os.ReadFile(filepath.Join(dataDir, ".gitignore"))
+			require.NoError(t, err)
+			require.Equal(t, 1, strings.Count(string(gitIgnore), "!REVIEW.md\n"))
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trankhanh040147/revcli/internal/finding"
+	"github.com/trankhanh040147/revcli/internal/git"
+	"github.com/trankhanh040147/revcli/internal/ui"
+)
+
+var (
+	suppressScope  string
+	suppressReason string
+)
+
+// suppressCmd suppresses a finding by ID
+var suppressCmd = &cobra.Command{
+	Use:   "suppress <finding-id>",
+	Short: "Suppress a review finding",
+	Long: `Suppress a finding so future reviews hide it and the model stops raising it.
+
+Every finding in a review ends with its ID (e.g. ` + "`#3fa94c01b2`" + `). Findings are
+fingerprinted by category, code snippet and path, so the ID survives unrelated edits.
+
+Scopes:
+  global   applies in every repository (~/.config/revcli/suppressions.yaml)
+  repo     applies to this repository (.revcli/suppressions.yaml, meant to be committed)
+  branch   applies to the current branch only (.revcli/suppressions.yaml)`,
+	Example: `  revcli suppress 3fa94c01b2
+  revcli suppress 3fa94c01b2 --scope branch --reason "fixed in follow-up PR"
+  revcli suppress list
+  revcli suppress remove 3fa94c01b2`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSuppress,
+}
+
+// suppressListCmd lists active suppressions
+var suppressListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List suppressed findings",
+	Long:  `List the global and repository suppressions.`,
+	Args:  cobra.NoArgs,
+	RunE:  runSuppressList,
+}
+
+// suppressRemoveCmd deletes a suppression
+var suppressRemoveCmd = &cobra.Command{
+	Use:   "remove <finding-id>",
+	Short: "Remove a suppression",
+	Long:  `Remove every suppression of a finding, in all scopes.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSuppressRemove,
+}
+
+func init() {
+	rootCmd.AddCommand(suppressCmd)
+	suppressCmd.AddCommand(suppressListCmd)
+	suppressCmd.AddCommand(suppressRemoveCmd)
+
+	suppressCmd.Flags().StringVarP(&suppressScope, "scope", "s", string(finding.ScopeRepo), "Suppression scope: global, repo or branch")
+	suppressCmd.Flags().StringVarP(&suppressReason, "reason", "r", "", "Why the finding is suppressed")
+}
+
+func runSuppress(cmd *cobra.Command, args []string) error {
+	scope, err := finding.ParseScope(suppressScope)
+	if err != nil {
+		return err
+	}
+
+	rootDir, err := git.GetGitRoot()
+	if err != nil {
+		return err
+	}
+	branch := ""
+	if scope == finding.ScopeBranch {
+		branch, err = git.CurrentBranch()
+		if err != nil {
+			return err
+		}
+		if branch == "HEAD" {
+			return fmt.Errorf("cannot use branch scope with a detached HEAD")
+		}
+	}
+
+	appInstance, err := setupApp(cmd)
+	if err != nil {
+		return err
+	}
+	defer appInstance.Shutdown()
+
+	record, err := appInstance.Findings.GetByID(cmd.Context(), rootDir, args[0])
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("finding %s not found in this repository's review history", args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up finding: %w", err)
+	}
+
+	suppression := finding.Suppression{
+		ID:          record.Fingerprint[:finding.IDLength],
+		Fingerprint: record.Fingerprint,
+		Scope:       scope,
+		Branch:      branch,
+		Category:    record.Category,
+		Path:        record.Path,
+		Snippet:     record.Snippet,
+		Message:     record.Message,
+		Reason:      suppressReason,
+		CreatedAt:   time.Now(),
+	}
+	if err := finding.AddSuppression(rootDir, suppression); err != nil {
+		return err
+	}
+
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("✓ Suppressed finding %s (%s)", suppression.ID, scope)))
+	fmt.Printf("  [%s] %s\n", record.Category, record.Message)
+	return nil
+}
+
+func runSuppressList(cmd *cobra.Command, args []string) error {
+	rootDir, err := git.GetGitRoot()
+	if err != nil {
+		return err
+	}
+	suppressions, err := finding.ListSuppressions(rootDir)
+	if err != nil {
+		return err
+	}
+
+	if len(suppressions) == 0 {
+		fmt.Println(ui.RenderSubtitle("No suppressed findings."))
+		fmt.Println("Use 'revcli suppress <finding-id>' to suppress one.")
+		return nil
+	}
+
+	fmt.Println(ui.RenderTitle("🔇 Suppressed Findings"))
+	fmt.Println()
+	for _, s := range suppressions {
+		scope := string(s.Scope)
+		if s.Scope == finding.ScopeBranch {
+			scope += ":" + s.Branch
+		}
+		fmt.Printf("  • %s [%s] %s\n", ui.RenderSuccess(s.ID), s.Category, scope)
+		fmt.Printf("    %s\n", s.Message)
+		if s.Reason != "" {
+			fmt.Printf("    Reason: %s\n", s.Reason)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func runSuppressRemove(cmd *cobra.Command, args []string) error {
+	rootDir, err := git.GetGitRoot()
+	if err != nil {
+		return err
+	}
+	removed, err := finding.RemoveSuppression(rootDir, strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return fmt.Errorf("no suppression found for %s", args[0])
+	}
+
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("✓ Removed %d suppression(s) of %s", removed, args[0])))
+	return nil
+}
@@ -207,6 +207,15 @@ type LSPConfig struct {
 	Options     map[string]any    `json:"options,omitempty" jsonschema:"description=LSP server-specific settings passed during initialization"`
 }
 
+// Analyzer is a static analyzer run on the changed Go packages before a review
+type Analyzer struct {
+	Name     string   `json:"name" jsonschema:"required,description=Analyzer name; vet\, staticcheck and golangci-lint have a default command and format,example=vet,example=staticcheck,example=golangci-lint"`
+	Command  string   `json:"command,omitempty" jsonschema:"description=Command to run (defaults to the known analyzer's),example=staticcheck"`
+	Args     []string `json:"args,omitempty" jsonschema:"description=Arguments before the package patterns (default to the known analyzer's JSON output flags)"`
+	Format   string   `json:"format,omitempty" jsonschema:"description=Output format (defaults to the known analyzer's),enum=vet,enum=staticcheck,enum=golangci-lint,enum=sarif"`
+	Disabled bool     `json:"disabled,omitempty" jsonschema:"description=Whether the analyzer is disabled,default=false"`
+}
+
 type TUIOptions struct {
 	CompactMode bool   `json:"compact_mode,omitempty" jsonschema:"description=Enable compact mode for the TUI interface,default=false"`
 	DiffMode    string `json:"diff_mode,omitempty" jsonschema:"description=Diff mode for the TUI interface,enum=unified,enum=split"`
@@ -256,6 +265,8 @@ func (Attribution) JSONSchemaExtend(schema *jsonschema.Schema) {
 
 type Options struct {
 	ContextPaths              []string     `json:"context_paths,omitempty" jsonschema:"description=Paths to files containing context information for the AI,example=.cursorrules,example=CRUSH.md"`
+	ReviewContextPaths        []string     `json:"review_context_paths,omitempty" jsonschema:"description=Paths to repository convention files for the reviewer (defaults to .revcli/REVIEW.md\, CONTRIBUTING.md and AGENTS.md),example=.revcli/REVIEW.md,example=docs/STYLE.md"`
+	Analyzers                 []Analyzer   `json:"analyzers,omitempty" jsonschema:"description=Static analyzers run on the changed Go packages before a review; their findings on changed lines are sent to the reviewer"`
 	SkillsPaths               []string     `json:"skills_paths,omitempty" jsonschema:"description=Paths to directories containing Agent Skills (folders with SKILL.md files),example=~/.config/crush/skills,example=./skills"`
 	TUI                       *TUIOptions  `json:"tui,omitempty" jsonschema:"description=Terminal user interface options"`
 	Debug                     bool         `json:"debug,omitempty" jsonschema:"description=Enable debug logging,default=false"`
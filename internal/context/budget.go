@@ -0,0 +1,85 @@
+package context
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/prompt"
+	"github.com/trankhanh040147/revcli/internal/tokens"
+)
+
+// packedContext is review context fitted into a token budget
+type packedContext struct {
+	Plan *tokens.Plan
+	// Files are the files sent with the prompt, in full or pruned (dropped files are left out)
+	Files map[string]string
+	// Pruned maps files that didn't fit in full to their summary
+	Pruned map[string]string
+	// Attachments are the files sent as attachments instead of in the prompt
+	Attachments map[string]string
+	// Sections are the prompt sections that fit, pinned sections first
+	Sections []prompt.Section
+	Prompt   string
+}
+
+// packContext fits a diff, its changed files and sections into the budget and renders the prompt
+// Files in summaries are packed as their summary instead of their content; pinned sections are always sent, like the diff
+func packContext(budget tokens.Budget, renderer *promptRenderer, diff string, files, summaries map[string]string, sections []prompt.Section, pinned ...prompt.Section) (*packedContext, error) {
+	items := budgetItems(diff, files, summaries, sections, pinned)
+	plan := budget.Pack(items)
+
+	packed := &packedContext{
+		Plan:   plan,
+		Files:  lo.OmitBy(files, func(path, _ string) bool { return plan.Decision(path) == tokens.DecisionDropped }),
+		Pruned: make(map[string]string),
+		Sections: slices.Concat(pinned, lo.Reject(sections, func(s prompt.Section, _ int) bool {
+			return plan.Decision(s.Title) == tokens.DecisionDropped
+		})),
+	}
+	for _, item := range items {
+		if item.Priority != tokens.PriorityChangedFile {
+			continue
+		}
+		decision := plan.Decision(item.Name)
+		switch {
+		case decision == tokens.DecisionSummarized:
+			packed.Pruned[item.Name] = item.Summary
+		case decision == tokens.DecisionIncluded && summaries[item.Name] != "":
+			packed.Pruned[item.Name] = item.Content
+		}
+	}
+	packed.Attachments = attachedFiles(renderer.payloadType(), packed.Files, packed.Pruned)
+	userPrompt, err := renderer.render(diff, packed)
+	if err != nil {
+		return nil, err
+	}
+	packed.Prompt = userPrompt
+	return packed, nil
+}
+
+// budgetItems lists the review context in packing order: diff and pinned sections, changed files by path, then sections
+// A file with a summary is packed as the summary, and dropped when even that doesn't fit
+func budgetItems(diff string, files, summaries map[string]string, sections, pinned []prompt.Section) []tokens.Item {
+	items := []tokens.Item{{Name: "diff", Priority: tokens.PriorityDiff, Content: diff}}
+	for _, section := range pinned {
+		items = append(items, tokens.Item{Name: section.Title, Priority: tokens.PriorityDiff, Content: section.Body})
+	}
+	for _, path := range slices.Sorted(maps.Keys(files)) {
+		if summary := summaries[path]; summary != "" {
+			items = append(items, tokens.Item{Name: path, Priority: tokens.PriorityChangedFile, Content: summary})
+			continue
+		}
+		items = append(items, tokens.Item{
+			Name:     path,
+			Priority: tokens.PriorityChangedFile,
+			Content:  files[path],
+			Summary:  outlineFile(path, files[path]),
+		})
+	}
+	for _, section := range sections {
+		items = append(items, tokens.Item{Name: section.Title, Priority: tokens.PriorityRelated, Content: section.Body})
+	}
+	return items
+}
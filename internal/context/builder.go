@@ -3,10 +3,23 @@ package context
 import (
 	"fmt"
 
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/apidiff"
+	"github.com/trankhanh040147/revcli/internal/bench"
+	"github.com/trankhanh040147/revcli/internal/config"
+	"github.com/trankhanh040147/revcli/internal/conventions"
+	"github.com/trankhanh040147/revcli/internal/csync"
 	"github.com/trankhanh040147/revcli/internal/filter"
+	"github.com/trankhanh040147/revcli/internal/finding"
 	"github.com/trankhanh040147/revcli/internal/git"
+	"github.com/trankhanh040147/revcli/internal/goref"
+	"github.com/trankhanh040147/revcli/internal/lint"
+	"github.com/trankhanh040147/revcli/internal/lsp"
 	"github.com/trankhanh040147/revcli/internal/preset"
 	"github.com/trankhanh040147/revcli/internal/prompt"
+	"github.com/trankhanh040147/revcli/internal/testrun"
+	"github.com/trankhanh040147/revcli/internal/tokens"
 )
 
 // ReviewContext contains all the data needed for a code review
@@ -17,16 +30,69 @@ type ReviewContext struct {
 	FileContents map[string]string
 	// IgnoredFiles lists files that were filtered out
 	IgnoredFiles []string
+	// RelatedFiles lists unchanged files that call changed exported symbols
+	RelatedFiles []string
 	// SecretsFound contains any potential secrets detected
 	SecretsFound []filter.SecretMatch
+	// SystemPrompt is the reviewer's system prompt, with the preset, intent and language rules
+	SystemPrompt string
+	// RulePacks are the names of the language rule packs in the system prompt
+	RulePacks []string
+	// ConventionFiles are the repository's convention files in the system prompt
+	ConventionFiles []conventions.File
 	// UserPrompt is the assembled prompt for the LLM
 	UserPrompt string
-	// EstimatedTokens is the rough token count
+	// EstimatedTokens is the prompt's token count for the selected model's tokenizer
 	EstimatedTokens int
+	// TokenPlan records what fit the model's token budget and what was summarized or dropped
+	TokenPlan *tokens.Plan
 	// Intent is the user's review intent and focus areas
 	Intent *Intent
+	// Payload is how the files are sent to the model, picked from the model's provider
+	Payload Payload
+	// Attachments are the files sent as attachments instead of in the prompt (none for PayloadInline)
+	Attachments map[string]string
+	// DedupedTokens are the tokens saved by sending each attached file once, instead of both in the prompt and attached
+	DedupedTokens int
 	// PrunedFiles maps file paths to their summaries (for token optimization)
 	PrunedFiles map[string]string
+	// Summaries maps the files pruned with the summarizer model to their summary
+	Summaries map[string]string
+	// RepoRoot is the absolute repository root
+	RepoRoot string
+	// Branch is the checked-out branch ("HEAD" when detached, "" when unknown)
+	Branch string
+	// HeadSHA is the reviewed commit ("" when unknown)
+	HeadSHA string
+	// Suppressions are the suppressed findings active on Branch
+	Suppressions []finding.Suppression
+	// Sections are extra prompt sections (e.g. suppressed findings)
+	Sections []prompt.Section
+	// APIReport compares the exported API of the changed Go packages with the base revision (nil when none changed)
+	APIReport *apidiff.Report
+	// APIError is why the API comparison couldn't run; the review goes on without it
+	APIError error
+	// LintReport has the static analyzers' findings on the changed lines (nil when none ran)
+	LintReport *lint.Report
+	// LSPDiagnostics are the language servers' errors and warnings on or near the changed lines
+	LSPDiagnostics []lint.Diagnostic
+	// TestReport has the go test results of the changed packages and, with coverage, their changed-line coverage
+	// (nil when tests weren't run)
+	TestReport *testrun.Report
+	// ToolBudget is the number of read-only tool calls the reviewer may make (0 allows none)
+	ToolBudget int
+	// BenchReport compares the changed packages' benchmarks between the base revision and the head
+	// (nil without a benchmark pattern)
+	BenchReport *bench.Report
+	// Chunks split a change that doesn't fit the token budget for a map-reduce review (nil reviews it at once)
+	Chunks []*Chunk
+	// VerifyModel selects the model for the self-verification pass ("" disables it)
+	VerifyModel config.SelectedModelType
+
+	// renderer renders the prompt again for chunks and after pruning
+	renderer *promptRenderer
+	// sections are every candidate prompt section, to pack them again after pruning
+	sections []prompt.Section
 }
 
 // Builder constructs the review context from git changes
@@ -35,6 +101,26 @@ type Builder struct {
 	force      bool
 	baseBranch string
 	intent     *Intent
+	budget     tokens.Budget
+	preset     *preset.Preset
+	// conventionPaths are the configured convention files (empty uses conventions.DefaultPaths)
+	conventionPaths []string
+	// analyzers are the static analyzers run on the changed Go packages
+	analyzers []config.Analyzer
+	// lspClients are the running language servers asked about the changed files (nil asks none)
+	lspClients *csync.Map[string, *lsp.Client]
+	// apiDiff compares the exported API of the changed Go packages with the base revision
+	apiDiff bool
+	// skipCallers leaves out the callers of changed exported symbols, whose search type-checks the whole module
+	skipCallers bool
+	// runTests runs go test on the changed Go packages
+	runTests bool
+	// coverage runs the tests with a coverage profile and reports the uncovered changed lines
+	coverage bool
+	// benchPattern selects the benchmarks compared between base and head ("" compares none)
+	benchPattern string
+	// toolBudget is the number of read-only tool calls the reviewer may make (0 allows none)
+	toolBudget int
 }
 
 // NewBuilder creates a new context builder
@@ -44,6 +130,7 @@ func NewBuilder(staged, force bool, baseBranch string) *Builder {
 		force:      force,
 		baseBranch: baseBranch,
 		intent:     nil,
+		budget:     tokens.NewBudget(0, 0, tokens.FamilyGeneric),
 	}
 }
 
@@ -53,6 +140,73 @@ func (b *Builder) WithIntent(intent *Intent) *Builder {
 	return b
 }
 
+// WithPreset sets the review preset, which modifies the system prompt and may set the prompt template
+func (b *Builder) WithPreset(p *preset.Preset) *Builder {
+	b.preset = p
+	return b
+}
+
+// WithConventionPaths sets the repository convention files for the system prompt (empty uses the defaults)
+func (b *Builder) WithConventionPaths(paths []string) *Builder {
+	b.conventionPaths = paths
+	return b
+}
+
+// WithAnalyzers sets the static analyzers run on the changed Go packages
+func (b *Builder) WithAnalyzers(analyzers []config.Analyzer) *Builder {
+	b.analyzers = analyzers
+	return b
+}
+
+// WithLSPClients sets the language servers asked for diagnostics on the changed files
+func (b *Builder) WithLSPClients(clients *csync.Map[string, *lsp.Client]) *Builder {
+	b.lspClients = clients
+	return b
+}
+
+// WithAPIDiff sets whether the exported API of the changed Go packages is compared with the base revision
+func (b *Builder) WithAPIDiff(enabled bool) *Builder {
+	b.apiDiff = enabled
+	return b
+}
+
+// WithCallers sets whether the callers of changed exported symbols are added to the context
+func (b *Builder) WithCallers(enabled bool) *Builder {
+	b.skipCallers = !enabled
+	return b
+}
+
+// WithTests sets whether go test runs on the changed Go packages
+func (b *Builder) WithTests(run bool) *Builder {
+	b.runTests = run
+	return b
+}
+
+// WithCoverage sets whether the tests of the changed Go packages run with a coverage profile
+// mapped onto the changed lines (this runs the tests even without WithTests)
+func (b *Builder) WithCoverage(coverage bool) *Builder {
+	b.coverage = coverage
+	return b
+}
+
+// WithBenchmarks sets the -bench regexp of the benchmarks compared between the base revision and the head
+func (b *Builder) WithBenchmarks(pattern string) *Builder {
+	b.benchPattern = pattern
+	return b
+}
+
+// WithToolBudget sets the number of read-only tool calls the reviewer may make to explore the repository
+func (b *Builder) WithToolBudget(budget int) *Builder {
+	b.toolBudget = budget
+	return b
+}
+
+// WithBudget sets the token budget of the review model
+func (b *Builder) WithBudget(budget tokens.Budget) *Builder {
+	b.budget = budget
+	return b
+}
+
 // Build gathers git changes and assembles the review context
 func (b *Builder) Build() (*ReviewContext, error) {
 	// Step 1: Get git diff and file contents
@@ -72,30 +226,236 @@ func (b *Builder) Build() (*ReviewContext, error) {
 	// Step 4: Filter the diff to remove ignored files
 	filteredDiff := filter.FilterDiff(diffResult.RawDiff)
 
-	// Step 5: Build the prompt (with pruning support)
-	userPrompt := prompt.BuildReviewPromptWithPruning(filteredDiff, filterResult.FilteredFiles, nil)
+	// Step 5: Load suppressed findings for this branch
+	rootDir, err := git.GetGitRoot()
+	if err != nil {
+		return nil, err
+	}
+	// Branch and HEAD are unknown in a repository without commits
+	branch, _ := git.CurrentBranch()
+	headSHA, _ := git.HeadSHA()
+	suppressions, err := finding.LoadSuppressions(rootDir, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load suppressions: %w", err)
+	}
+	sections := finding.PromptSections(suppressions)
 
-	// Step 6: Estimate tokens
-	estimatedTokens := prompt.EstimateTokens(userPrompt)
+	// Step 5b: Derive the change's intent from the branch and its commits (--base reviews)
+	intent, commits, err := b.changeIntent(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 6: Compare the exported API of the changed Go packages with the base revision;
+	// a comparison that can't run (e.g. a shallow clone) doesn't stop the review
+	var apiReport *apidiff.Report
+	var apiErr error
+	if b.apiDiff && headSHA != "" {
+		apiReport, apiErr = b.compareAPI(rootDir, filteredDiff, filterResult.FilteredFiles)
+		if apiErr != nil {
+			apiErr = fmt.Errorf("failed to compare exported API: %w", apiErr)
+		}
+	}
+	sections = append(sections, apiReport.Sections()...)
+
+	// Step 6b: Run the static analyzers and keep their findings on the changed lines,
+	// so the model can focus on what they can't catch
+	lintReport := lint.Run(rootDir, filteredDiff, b.analyzers)
+	sections = append(sections, lintReport.Sections()...)
+
+	// Step 6c: Ask the language servers for compile errors and warnings near the changed lines
+	lspDiagnostics := lint.LSPDiagnostics(b.lspClients, rootDir, filteredDiff)
+	sections = append(sections, lint.LSPSections(lspDiagnostics)...)
+
+	// Step 6d: Run the tests of the changed packages, so findings can point at real failures,
+	// and with coverage list the changed lines no test runs
+	var testReport *testrun.Report
+	if b.runTests || b.coverage {
+		testReport, err = testrun.Run(rootDir, filteredDiff, b.coverage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to run tests: %w", err)
+		}
+	}
+	sections = append(sections, testReport.Sections()...)
+
+	// Step 6e: Compare the changed packages' benchmarks between the base revision and the head,
+	// so performance findings rest on measurements
+	var benchReport *bench.Report
+	if b.benchPattern != "" && headSHA != "" {
+		benchReport, err = b.compareBenchmarks(rootDir, filteredDiff)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare benchmarks: %w", err)
+		}
+	}
+	sections = append(sections, benchReport.Sections()...)
+
+	// Step 7: Add the declarations that changed Go code references from unchanged files,
+	// and the callers of changed exported symbols
+	var callers []goref.Caller
+	if !b.skipCallers {
+		callers = goref.Callers(rootDir, filteredDiff, filterResult.FilteredFiles)
+	}
+	sections = append(sections, goref.DeclarationSections(goref.Referenced(rootDir, filteredDiff, filterResult.FilteredFiles))...)
+	sections = append(sections, goref.CallerSections(callers)...)
+
+	// Step 8: Fit the diff, changed files and sections into the model's token budget,
+	// then render the prompt template (with pruning support)
+	renderer, err := b.promptRenderer(rootDir, branch, intent, commits)
+	if err != nil {
+		return nil, err
+	}
+	packed, err := packContext(b.budget, renderer, filteredDiff, filterResult.FilteredFiles, nil, sections)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 9: Build the system prompt with the rule packs of the changed files' languages
+	// and the repository's conventions that apply to the changed files
+	rulePacks, err := LanguageRules(prompt.DetectLanguages(filterResult.FilteredFiles))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load language rules: %w", err)
+	}
+	changedPaths := lo.Map(git.SplitDiff(filteredDiff), func(f git.FileDiff, _ int) string { return f.Path })
+	conventionFiles, err := conventions.Load(rootDir, b.conventionPaths, changedPaths)
+	if err != nil {
+		return nil, err
+	}
 
 	return &ReviewContext{
 		RawDiff:         filteredDiff,
 		FileContents:    filterResult.FilteredFiles,
 		IgnoredFiles:    filterResult.IgnoredFiles,
+		RelatedFiles:    goref.RelatedFiles(callers),
 		SecretsFound:    filterResult.SecretsFound,
-		UserPrompt:      userPrompt,
-		EstimatedTokens: estimatedTokens,
-		Intent:          b.intent,
-		PrunedFiles:     make(map[string]string),
+		SystemPrompt:    b.systemPrompt(intent, rulePacks, conventionFiles),
+		RulePacks:       lo.Map(rulePacks, func(p prompt.RulePack, _ int) string { return p.Name }),
+		ConventionFiles: conventionFiles,
+		UserPrompt:      packed.Prompt,
+		EstimatedTokens: estimateTokens(b.budget.Tokenizer, packed),
+		TokenPlan:       packed.Plan,
+		Intent:          intent,
+		Payload:         renderer.payload,
+		Attachments:     packed.Attachments,
+		DedupedTokens:   dedupedTokens(b.budget.Tokenizer, packed.Attachments),
+		PrunedFiles:     packed.Pruned,
+		Summaries:       make(map[string]string),
+		RepoRoot:        rootDir,
+		Branch:          branch,
+		HeadSHA:         headSHA,
+		Suppressions:    suppressions,
+		Sections:        packed.Sections,
+		APIReport:       apiReport,
+		APIError:        apiErr,
+		LintReport:      lintReport,
+		LSPDiagnostics:  lspDiagnostics,
+		TestReport:      testReport,
+		BenchReport:     benchReport,
+		ToolBudget:      b.toolBudget,
+		renderer:        renderer,
+		sections:        sections,
+	}, nil
+}
+
+// changeIntent adds the branch, commit messages and linked issues of a --base review to the user's intent
+// Returns the intent (nil when there is none) and the commit messages, oldest first
+func (b *Builder) changeIntent(branch string) (*Intent, []string, error) {
+	if b.baseBranch == "" {
+		return b.intent, nil, nil
+	}
+	commits, err := git.CommitMessages(b.baseBranch)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read commit messages: %w", err)
+	}
+
+	intent := &Intent{}
+	if b.intent != nil {
+		*intent = *b.intent
+	}
+	if branch != "HEAD" {
+		intent.Branch = branch
+	}
+	intent.Commits = lo.Map(commits[max(len(commits)-MaxIntentCommits, 0):], func(c string, _ int) string {
+		if len(c) > MaxCommitMessageChars {
+			return c[:MaxCommitMessageChars] + "…"
+		}
+		return c
+	})
+	intent.IssueKeys = ParseIssueKeys(intent.Branch, commits)
+	return intent, commits, nil
+}
+
+// promptRenderer loads the prompt template for the review
+func (b *Builder) promptRenderer(rootDir, branch string, intent *Intent, commits []string) (*promptRenderer, error) {
+	presetTemplate := ""
+	if b.preset != nil {
+		presetTemplate = b.preset.Template
+	}
+	tmpl, err := prompt.LoadReviewTemplate(rootDir, presetTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &promptRenderer{
+		template:   tmpl,
+		intent:     intent,
+		branch:     branch,
+		baseBranch: b.baseBranch,
+		commits:    commits,
+		payload:    PayloadFor(b.budget.Tokenizer.Family()),
 	}, nil
 }
 
+// systemPrompt builds the reviewer's system prompt from the preset, the language rule packs,
+// the repository's conventions and the intent
+func (b *Builder) systemPrompt(intent *Intent, rulePacks []prompt.RulePack, conventionFiles []conventions.File) string {
+	presetPrompt, presetReplace := "", false
+	if b.preset != nil {
+		presetPrompt, presetReplace = b.preset.Prompt, b.preset.Replace
+	}
+	exploration := ""
+	if b.toolBudget > 0 {
+		exploration = fmt.Sprintf(prompt.ExplorationInstructionsFormat, b.toolBudget)
+	}
+	return GetSystemPromptWithIntent(intent, presetPrompt, presetReplace,
+		prompt.BuildLanguageRules(rulePacks), conventions.PromptSection(conventionFiles), exploration)
+}
+
+// compareAPI compares the exported API with the base revision, matching the diff:
+// the merge base with the base branch against HEAD, HEAD against the working tree for staged changes,
+// or the index against the working tree
+func (b *Builder) compareAPI(rootDir, diff string, files map[string]string) (*apidiff.Report, error) {
+	switch {
+	case b.baseBranch == "" && b.staged:
+		return apidiff.Run(rootDir, "HEAD", "", diff, files)
+	case b.baseBranch == "":
+		return apidiff.Run(rootDir, apidiff.Index, "", diff, files)
+	}
+	base, err := git.MergeBase(b.baseBranch, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	return apidiff.Run(rootDir, base, "HEAD", diff, files)
+}
+
+// compareBenchmarks compares the benchmarks with the base revision, matching the diff:
+// the merge base with the base branch against HEAD, or HEAD against the working tree
+func (b *Builder) compareBenchmarks(rootDir, diff string) (*bench.Report, error) {
+	if b.baseBranch == "" {
+		return bench.Run(rootDir, "HEAD", "", diff, b.benchPattern)
+	}
+	base, err := git.MergeBase(b.baseBranch, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	return bench.Run(rootDir, base, "HEAD", diff, b.benchPattern)
+}
+
 // BuildFromDiff creates a review context from an existing diff string
 func BuildFromDiff(rawDiff string, files map[string]string) *ReviewContext {
 	filterResult := filter.Filter(files, rawDiff)
 	filteredDiff := filter.FilterDiff(rawDiff)
 	userPrompt := prompt.BuildReviewPrompt(filteredDiff, filterResult.FilteredFiles)
-	estimatedTokens := prompt.EstimateTokens(userPrompt)
+	estimatedTokens := tokens.NewTokenizer(tokens.FamilyGeneric).Count(userPrompt)
 
 	return &ReviewContext{
 		RawDiff:         filteredDiff,
@@ -128,8 +488,9 @@ func GetSystemPromptWithPreset(presetPrompt string, replace bool) string {
 	return prompt.SystemPrompt + "\n\n---\n\n" + presetPrompt
 }
 
-// GetSystemPromptWithIntent returns the system prompt incorporating intent and preset
-func GetSystemPromptWithIntent(intent *Intent, presetPrompt string, presetReplace bool) string {
+// GetSystemPromptWithIntent returns the system prompt incorporating intent, preset and extra sections
+// sections (e.g. language rules, "" ones are skipped) follow the base prompt or preset, even when the preset replaces the base prompt
+func GetSystemPromptWithIntent(intent *Intent, presetPrompt string, presetReplace bool, sections ...string) string {
 	// Start with base prompt or preset
 	var basePrompt string
 	if presetReplace && presetPrompt != "" {
@@ -140,6 +501,11 @@ func GetSystemPromptWithIntent(intent *Intent, presetPrompt string, presetReplac
 			basePrompt = basePrompt + "\n\n---\n\n" + presetPrompt
 		}
 	}
+	for _, section := range sections {
+		if section != "" {
+			basePrompt = basePrompt + "\n\n---\n\n" + section
+		}
+	}
 
 	// If no intent, return base prompt
 	if intent == nil {
@@ -156,6 +522,15 @@ func GetSystemPromptWithIntent(intent *Intent, presetPrompt string, presetReplac
 	return BuildSystemPromptWithIntent(basePrompt, intent, focusPresets)
 }
 
+// ContextFiles returns the files sent with the prompt, in full or pruned to a summary
+// Files dropped to fit the token budget are left out
+func (rc *ReviewContext) ContextFiles() map[string]string {
+	if rc.TokenPlan == nil {
+		return rc.FileContents
+	}
+	return lo.OmitBy(rc.FileContents, func(path, _ string) bool { return rc.TokenPlan.Decision(path) == tokens.DecisionDropped })
+}
+
 // HasChanges returns true if there are changes to review
 func (rc *ReviewContext) HasChanges() bool {
 	return len(rc.FileContents) > 0 || rc.RawDiff != ""
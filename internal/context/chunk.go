@@ -0,0 +1,120 @@
+package context
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/git"
+	"github.com/trankhanh040147/revcli/internal/prompt"
+	"github.com/trankhanh040147/revcli/internal/tokens"
+)
+
+// Chunk is a group of changed files reviewed on its own in chunked mode
+type Chunk struct {
+	// Index is the 1-based position of the chunk; Total is the number of chunks
+	Index int
+	Total int
+	// Paths are the changed files in the chunk, sorted
+	Paths []string
+	// Files are the files sent with the prompt, in full or pruned
+	Files map[string]string
+	// PrunedFiles maps files that didn't fit in full to their summary
+	PrunedFiles map[string]string
+	// Attachments are the files sent as attachments instead of in the prompt
+	Attachments map[string]string
+	Prompt      string
+	TokenPlan   *tokens.Plan
+}
+
+// chunkFile is one changed file with its packing cost
+type chunkFile struct {
+	git.FileDiff
+	cost int
+}
+
+// Split partitions the changed files into chunks that each fit the token budget
+// Files are placed largest first into the first chunk with room (first-fit decreasing),
+// so a file too large for any chunk gets a chunk of its own and is packed like a normal review
+func (rc *ReviewContext) Split() ([]*Chunk, error) {
+	budget := rc.TokenPlan.Budget
+	fileDiffs := git.SplitDiff(rc.RawDiff)
+
+	// Every chunk sends the sections and the chunk note listing the other files
+	reserved := budget.Tokenizer.Count(chunkNote(len(fileDiffs), lo.Map(fileDiffs, func(f git.FileDiff, _ int) string { return f.Path }))) + tokens.ItemOverhead
+	for _, section := range rc.Sections {
+		reserved += budget.Tokenizer.Count(section.Body) + tokens.ItemOverhead
+	}
+	capacity := budget.Available() - reserved
+
+	files := lo.Map(fileDiffs, func(f git.FileDiff, _ int) chunkFile {
+		content, ok := rc.Summaries[f.Path]
+		if !ok {
+			content = rc.FileContents[f.Path]
+		}
+		cost := budget.Tokenizer.Count(f.Diff) + budget.Tokenizer.Count(content) + 2*tokens.ItemOverhead
+		return chunkFile{FileDiff: f, cost: cost}
+	})
+	slices.SortStableFunc(files, func(a, b chunkFile) int { return cmp.Compare(b.cost, a.cost) })
+
+	var groups [][]chunkFile
+	var used []int
+	for _, file := range files {
+		i := slices.IndexFunc(used, func(u int) bool { return u+file.cost <= capacity })
+		if i < 0 {
+			groups = append(groups, nil)
+			used = append(used, 0)
+			i = len(groups) - 1
+		}
+		groups[i] = append(groups[i], file)
+		used[i] += file.cost
+	}
+
+	// Order files by path within a chunk and chunks by their first path
+	for _, group := range groups {
+		slices.SortFunc(group, func(a, b chunkFile) int { return cmp.Compare(a.Path, b.Path) })
+	}
+	slices.SortFunc(groups, func(a, b []chunkFile) int { return cmp.Compare(a[0].Path, b[0].Path) })
+
+	allPaths := lo.Map(files, func(f chunkFile, _ int) string { return f.Path })
+	chunks := make([]*Chunk, 0, len(groups))
+	for i, group := range groups {
+		paths := lo.Map(group, func(f chunkFile, _ int) string { return f.Path })
+		diff := strings.Join(lo.Map(group, func(f chunkFile, _ int) string { return f.Diff }), "")
+		contents := lo.PickByKeys(rc.FileContents, paths)
+		summaries := lo.PickByKeys(rc.Summaries, paths)
+
+		note := prompt.Section{
+			Title: fmt.Sprintf(ChunkSectionTitleFormat, i+1, len(groups)),
+			Body:  chunkNote(len(groups), lo.Without(allPaths, paths...)),
+		}
+		packed, err := packContext(budget, rc.renderer, diff, contents, summaries, rc.Sections, note)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, &Chunk{
+			Index:       i + 1,
+			Total:       len(groups),
+			Paths:       paths,
+			Files:       packed.Files,
+			PrunedFiles: packed.Pruned,
+			Attachments: packed.Attachments,
+			Prompt:      packed.Prompt,
+			TokenPlan:   packed.Plan,
+		})
+	}
+	return chunks, nil
+}
+
+// chunkNote tells the model it reviews one of total chunks and lists the files reviewed elsewhere
+func chunkNote(total int, otherPaths []string) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf(ChunkSectionIntroFormat, total))
+	for _, path := range slices.Sorted(slices.Values(otherPaths)) {
+		builder.WriteString(fmt.Sprintf("- `%s`\n", path))
+	}
+	return builder.String()
+}
@@ -0,0 +1,27 @@
+package context
+
+// OutlinePrefix introduces the declaration outline that replaces a file over the token budget
+const OutlinePrefix = "File over the token budget; declarations only: "
+
+// ChunkSectionTitleFormat titles the prompt section that tells the model which chunk it reviews
+const ChunkSectionTitleFormat = "Chunk %d of %d"
+
+// ChunkSectionIntroFormat explains chunked review to the model
+const ChunkSectionIntroFormat = "This change is too large for one review, so it is split into %d chunks reviewed separately. Review only the files in this chunk; the other chunks are merged with this review afterwards.\n\nOther changed files (reviewed in other chunks):\n"
+
+// MaxIntentCommits caps the commit messages added to the change intent (the latest are kept)
+const MaxIntentCommits = 30
+
+// MaxCommitMessageChars caps each commit message in the change intent
+const MaxCommitMessageChars = 2000
+
+// IntentCheckInstructions asks the model to compare the diff with the stated intent
+const IntentCheckInstructions = `## Intent Check
+
+Check whether the diff actually implements the stated intent above (branch, linked issues, commit messages and custom instructions). After the findings, add a section:
+
+### 🎯 Intent Gaps
+- What the intent promises that the diff doesn't implement, or implements only partly.
+- Changes in the diff that the intent doesn't explain.
+
+Write "None" under the heading when the diff matches its intent.`
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/trankhanh040147/revcli/internal/prompt"
+	"github.com/trankhanh040147/revcli/internal/conventions"
+	"github.com/trankhanh040147/revcli/internal/lint"
+	"github.com/trankhanh040147/revcli/internal/testrun"
 )
 
 // Summary returns a summary of what will be reviewed
@@ -17,11 +19,54 @@ func (rc *ReviewContext) Summary() string {
 	if ignoredCount > 0 {
 		summary += fmt.Sprintf("   • Files ignored: %d\n", ignoredCount)
 	}
+	if relatedCount := len(rc.RelatedFiles); relatedCount > 0 {
+		summary += fmt.Sprintf("   • Related files (callers): %d\n", relatedCount)
+	}
+	if rc.APIReport != nil && len(rc.APIReport.Changes) > 0 {
+		summary += fmt.Sprintf("   • %s\n", rc.APIReport.Summary())
+	}
+	if rc.LintReport != nil {
+		summary += fmt.Sprintf("   • %s\n", rc.LintReport.Summary())
+		for _, failure := range rc.LintReport.Failures {
+			summary += fmt.Sprintf("   ⚠️  %s didn't run: %s\n", failure.Tool, firstLine(failure.Error))
+		}
+	}
+	if len(rc.LSPDiagnostics) > 0 {
+		summary += fmt.Sprintf("   • %s\n", rc.lspSummary())
+	}
+	if rc.TestReport != nil {
+		summary += fmt.Sprintf("   • %s\n", rc.TestReport.Summary())
+		if rc.TestReport.Coverage != nil {
+			summary += fmt.Sprintf("   • %s\n", rc.TestReport.Coverage.Summary())
+		}
+	}
+	if rc.BenchReport != nil {
+		summary += fmt.Sprintf("   • %s\n", rc.BenchReport.Summary())
+	}
+	if rc.Intent != nil && (rc.Intent.Branch != "" || len(rc.Intent.Commits) > 0) {
+		summary += fmt.Sprintf("   • Intent: %s\n", rc.Intent.Describe())
+	}
+	if len(rc.RulePacks) > 0 {
+		summary += fmt.Sprintf("   • Language rules: %s\n", strings.Join(rc.RulePacks, ", "))
+	}
+	if len(rc.ConventionFiles) > 0 {
+		summary += fmt.Sprintf("   • Convention files: %s\n", strings.Join(conventions.Paths(rc.ConventionFiles), ", "))
+	}
 	summary += fmt.Sprintf("   • Estimated tokens: ~%d\n", rc.EstimatedTokens)
-
-	// Token warning
-	if warning := prompt.MaxTokenWarning(rc.UserPrompt, 100000); warning != "" {
-		summary += fmt.Sprintf("   ⚠️  %s\n", warning)
+	if rc.DedupedTokens > 0 {
+		summary += fmt.Sprintf("   • File payload: %s (each file sent once, ~%d tokens saved)\n", rc.Payload, rc.DedupedTokens)
+	}
+	if rc.TokenPlan != nil {
+		if summarized := len(rc.TokenPlan.Summarized()); summarized > 0 {
+			summary += fmt.Sprintf("   • Summarized to fit the budget: %d\n", summarized)
+		}
+		if dropped := len(rc.TokenPlan.Dropped()); dropped > 0 {
+			summary += fmt.Sprintf("   • Dropped to fit the budget: %d\n", dropped)
+		}
+		// Token warning
+		if warning := rc.TokenPlan.Warning(); warning != "" {
+			summary += fmt.Sprintf("   ⚠️  %s\n", warning)
+		}
 	}
 
 	return summary
@@ -43,7 +88,7 @@ func (rc *ReviewContext) DetailedSummary() string {
 		for path, content := range rc.FileContents {
 			size := len(content)
 			totalSize += size
-			sb.WriteString(fmt.Sprintf("   • %s (%s)\n", path, formatBytes(size)))
+			sb.WriteString(fmt.Sprintf("   • %s (%s)%s\n", path, formatBytes(size), rc.fileDiagnostics(path)))
 		}
 		sb.WriteString(fmt.Sprintf("\n   Total: %d files, %s\n", len(rc.FileContents), formatBytes(totalSize)))
 	}
@@ -56,12 +101,118 @@ func (rc *ReviewContext) DetailedSummary() string {
 		}
 	}
 
+	// Related files
+	if len(rc.RelatedFiles) > 0 {
+		sb.WriteString("\n🔗 Related files (callers of changed exported symbols):\n")
+		for _, path := range rc.RelatedFiles {
+			sb.WriteString(fmt.Sprintf("   • %s\n", path))
+		}
+	}
+
+	// Exported API changes
+	if rc.APIReport != nil && len(rc.APIReport.Changes) > 0 {
+		sb.WriteString(fmt.Sprintf("\n🧬 %s\n", rc.APIReport.Summary()))
+		for _, change := range rc.APIReport.Incompatible() {
+			sb.WriteString(fmt.Sprintf("   • %s\n", change))
+		}
+	}
+
+	// Static analyzer findings on the changed lines
+	if rc.LintReport != nil {
+		sb.WriteString(fmt.Sprintf("\n🧹 %s\n", rc.LintReport.Summary()))
+		for _, failure := range rc.LintReport.Failures {
+			sb.WriteString(fmt.Sprintf("   ⚠️  %s didn't run: %s\n", failure.Tool, firstLine(failure.Error)))
+		}
+	}
+
+	// Language server errors and warnings near the changed lines
+	if len(rc.LSPDiagnostics) > 0 {
+		sb.WriteString(fmt.Sprintf("\n🩺 %s\n", rc.lspSummary()))
+		for _, d := range rc.LSPDiagnostics {
+			if d.Severity == lint.SeverityError {
+				sb.WriteString(fmt.Sprintf("   • %s\n", d))
+			}
+		}
+	}
+
+	// Test results of the changed packages
+	if rc.TestReport != nil {
+		sb.WriteString(fmt.Sprintf("\n🧪 %s\n", rc.TestReport.Summary()))
+		if rc.TestReport.TimedOut {
+			sb.WriteString("   ⚠️  go test timed out\n")
+		}
+		for _, pkg := range rc.TestReport.Failed() {
+			sb.WriteString(fmt.Sprintf("   ✗ %s", pkg.Name))
+			if len(pkg.FailedTests) > 0 {
+				sb.WriteString(fmt.Sprintf(": %s", strings.Join(pkg.FailedTests, ", ")))
+			}
+			sb.WriteString("\n")
+		}
+		if coverage := rc.TestReport.Coverage; coverage != nil {
+			sb.WriteString(fmt.Sprintf("   • %s\n", coverage.Summary()))
+			for _, file := range coverage.Files {
+				if len(file.Uncovered) > 0 {
+					sb.WriteString(fmt.Sprintf("     %s: uncovered lines %s\n", file.Path, testrun.LineRanges(file.Uncovered)))
+				}
+			}
+		}
+	}
+
+	// Benchmark regressions against the base revision
+	if rc.BenchReport != nil {
+		sb.WriteString(fmt.Sprintf("\n⏱️  %s\n", rc.BenchReport.Summary()))
+		for _, c := range rc.BenchReport.Regressions() {
+			sb.WriteString(fmt.Sprintf("   • %s\n", c))
+		}
+	}
+
+	// Change intent from the branch and its commits
+	if rc.Intent != nil && (rc.Intent.Branch != "" || len(rc.Intent.Commits) > 0) {
+		sb.WriteString(fmt.Sprintf("\n🎯 Intent: %s\n", rc.Intent.Describe()))
+		if rc.Intent.CheckIntent {
+			sb.WriteString("   • The reviewer reports gaps between the diff and its intent\n")
+		}
+	}
+
+	// Language rule packs
+	if len(rc.RulePacks) > 0 {
+		sb.WriteString(fmt.Sprintf("\n📐 Language rules: %s\n", strings.Join(rc.RulePacks, ", ")))
+	}
+
+	// Repository convention files
+	if len(rc.ConventionFiles) > 0 {
+		sb.WriteString("\n📜 Convention files:\n")
+		for _, file := range rc.ConventionFiles {
+			if file.Skipped > 0 {
+				sb.WriteString(fmt.Sprintf("   • %s (%d scoped sections don't apply)\n", file.Path, file.Skipped))
+			} else {
+				sb.WriteString(fmt.Sprintf("   • %s\n", file.Path))
+			}
+		}
+	}
+
+	// Chunks
+	if len(rc.Chunks) > 0 {
+		sb.WriteString(fmt.Sprintf("\n🧩 Chunks: %d (reviewed separately, then merged)\n", len(rc.Chunks)))
+		for _, c := range rc.Chunks {
+			sb.WriteString(fmt.Sprintf("   • %d: %s\n", c.Index, strings.Join(c.Paths, ", ")))
+		}
+	}
+
 	// Token estimate
 	sb.WriteString(fmt.Sprintf("\n📊 Token Estimate: ~%d tokens\n", rc.EstimatedTokens))
+	if rc.DedupedTokens > 0 {
+		sb.WriteString(fmt.Sprintf("   • File payload: %s, each file sent once (~%d tokens saved)\n", rc.Payload, rc.DedupedTokens))
+	}
 
-	// Token warning
-	if warning := prompt.MaxTokenWarning(rc.UserPrompt, 100000); warning != "" {
-		sb.WriteString(fmt.Sprintf("⚠️  %s\n", warning))
+	if rc.TokenPlan != nil {
+		for _, line := range strings.Split(strings.TrimRight(rc.TokenPlan.Report(), "\n"), "\n") {
+			sb.WriteString(fmt.Sprintf("   • %s\n", line))
+		}
+		// Token warning
+		if warning := rc.TokenPlan.Warning(); warning != "" {
+			sb.WriteString(fmt.Sprintf("⚠️  %s\n", warning))
+		}
 	}
 
 	return sb.String()
@@ -81,3 +232,23 @@ func formatBytes(bytes int) string {
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
+// firstLine returns the first line of a multi-line message
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// lspSummary counts the language server errors and warnings near the changed lines
+func (rc *ReviewContext) lspSummary() string {
+	errors, warnings := lint.CountSeverity(rc.LSPDiagnostics)
+	return fmt.Sprintf("Compiler diagnostics: %d error(s), %d warning(s)", errors, warnings)
+}
+
+// fileDiagnostics describes a changed file's language server errors and warnings ("" when it has none)
+func (rc *ReviewContext) fileDiagnostics(path string) string {
+	errors, warnings := lint.CountSeverity(lint.ForFile(rc.LSPDiagnostics, path))
+	if errors == 0 && warnings == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" — %d error(s), %d warning(s)", errors, warnings)
+}
@@ -2,8 +2,12 @@ package context
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/samber/lo"
+
 	"github.com/trankhanh040147/revcli/internal/preset"
 )
 
@@ -17,6 +21,64 @@ type Intent struct {
 	NegativeConstraints []string
 	// WebSearchEnabled controls whether web search is enabled for Gemini requests (default: true)
 	WebSearchEnabled bool
+	// Branch is the reviewed branch of a --base review
+	Branch string
+	// Commits are the messages of the reviewed commits of a --base review, oldest first
+	Commits []string
+	// IssueKeys are the issues the branch name and commit messages link to (e.g. PROJ-123, #42)
+	IssueKeys []string
+	// CheckIntent asks the model to check that the diff implements the stated intent and to report gaps
+	CheckIntent bool
+}
+
+// issueKeyRe matches Jira-style keys (PROJ-123) and GitHub/GitLab references (#42)
+var issueKeyRe = regexp.MustCompile(`\b[A-Z][A-Z0-9]{1,9}-[0-9]+\b|(?:^|[\s(,])(#[0-9]+)\b`)
+
+// nonIssuePrefixes are uppercase prefixes of keys that name standards and encodings, not issues (UTF-8, SHA-256)
+var nonIssuePrefixes = []string{"AES", "CVE", "HTTP", "ISO", "RFC", "SHA", "TLS", "UTF"}
+
+// branchIssueKeyRe matches an issue key at the start of a branch name segment, in any case (feature/proj-123-login)
+var branchIssueKeyRe = regexp.MustCompile(`(?:^|/)([a-zA-Z][a-zA-Z0-9]{1,9}-[0-9]+)(?:[-_/]|$)`)
+
+// ParseIssueKeys returns the issue keys a branch name and commit messages link to, in order of appearance
+func ParseIssueKeys(branch string, commits []string) []string {
+	var keys []string
+	for _, match := range branchIssueKeyRe.FindAllStringSubmatch(branch, -1) {
+		keys = append(keys, strings.ToUpper(match[1]))
+	}
+	for _, commit := range commits {
+		for _, match := range issueKeyRe.FindAllStringSubmatch(commit, -1) {
+			key := match[0]
+			if match[1] != "" {
+				key = match[1]
+			}
+			keys = append(keys, key)
+		}
+	}
+	return lo.Uniq(lo.Reject(keys, func(key string, _ int) bool {
+		prefix, _, _ := strings.Cut(key, "-")
+		return slices.Contains(nonIssuePrefixes, prefix)
+	}))
+}
+
+// Describe summarizes the change intent for the context preview, e.g. "branch feature/x, 3 commit(s), issues PROJ-1"
+func (i *Intent) Describe() string {
+	var parts []string
+	if i.Branch != "" {
+		parts = append(parts, "branch "+i.Branch)
+	}
+	if len(i.Commits) > 0 {
+		parts = append(parts, fmt.Sprintf("%d commit(s)", len(i.Commits)))
+	}
+	if len(i.IssueKeys) > 0 {
+		parts = append(parts, "issues "+strings.Join(i.IssueKeys, ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// hasStatedIntent reports whether the intent says what the change should do
+func (i *Intent) hasStatedIntent() bool {
+	return i.CustomInstruction != "" || i.Branch != "" || len(i.Commits) > 0 || len(i.IssueKeys) > 0
 }
 
 // BuildSystemPromptWithIntent builds the system prompt incorporating intent
@@ -46,6 +108,24 @@ func BuildSystemPromptWithIntent(basePrompt string, intent *Intent, presets map[
 		parts = append(parts, "\n")
 	}
 
+	// Add the change's stated intent: the branch, linked issues and commit messages
+	if intent.Branch != "" || len(intent.Commits) > 0 {
+		parts = append(parts, "\n\n---\n\n## Change Intent\n")
+		parts = append(parts, "The author describes the change as follows.\n")
+		if intent.Branch != "" {
+			parts = append(parts, fmt.Sprintf("\n**Branch:** %s\n", intent.Branch))
+		}
+		if len(intent.IssueKeys) > 0 {
+			parts = append(parts, fmt.Sprintf("**Linked issues:** %s\n", strings.Join(intent.IssueKeys, ", ")))
+		}
+		if len(intent.Commits) > 0 {
+			parts = append(parts, "\n### Commit Messages\n")
+			for _, commit := range intent.Commits {
+				parts = append(parts, fmt.Sprintf("\n%s\n", commit))
+			}
+		}
+	}
+
 	// Add negative constraints
 	if len(intent.NegativeConstraints) > 0 {
 		parts = append(parts, "\n\n---\n\n## Negative Constraints\n")
@@ -55,6 +135,11 @@ func BuildSystemPromptWithIntent(basePrompt string, intent *Intent, presets map[
 		}
 	}
 
+	// Ask for a gap report when there is an intent to check against
+	if intent.CheckIntent && intent.hasStatedIntent() {
+		parts = append(parts, "\n\n---\n\n", IntentCheckInstructions, "\n")
+	}
+
 	return strings.Join(parts, "")
 }
 
@@ -0,0 +1,64 @@
+package context
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIssueKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		branch  string
+		commits []string
+		want    []string
+	}{
+		{
+			name:    "branch and commits",
+			branch:  "feature/proj-123-login",
+			commits: []string{"PROJ-123: Add login\n\nFixes #42 and relates to OPS-7.", "Follow up (#42)"},
+			want:    []string{"PROJ-123", "#42", "OPS-7"},
+		},
+		{
+			name:    "standards are not issues",
+			branch:  "main",
+			commits: []string{"Decode UTF-8 names and hash with SHA-256 per RFC-4648"},
+			want:    []string{},
+		},
+		{
+			name:    "anchors and words are not issues",
+			branch:  "release-notes",
+			commits: []string{"See README#install and v1-2 notes"},
+			want:    []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, ParseIssueKeys(tt.branch, tt.commits))
+		})
+	}
+}
+
+func TestBuildSystemPromptWithIntent(t *testing.T) {
+	t.Parallel()
+
+	intent := &Intent{
+		Branch:      "feature/proj-1",
+		Commits:     []string{"Add login\n\nUses OAuth."},
+		IssueKeys:   []string{"PROJ-1"},
+		CheckIntent: true,
+	}
+	out := BuildSystemPromptWithIntent("base", intent, nil)
+	require.Contains(t, out, "## Change Intent")
+	require.Contains(t, out, "**Branch:** feature/proj-1\n**Linked issues:** PROJ-1\n")
+	require.Contains(t, out, "Add login\n\nUses OAuth.")
+	require.Contains(t, out, "### 🎯 Intent Gaps")
+	require.Equal(t, "branch feature/proj-1, 1 commit(s), issues PROJ-1", intent.Describe())
+
+	// Nothing to check the diff against
+	out = BuildSystemPromptWithIntent("base", &Intent{CheckIntent: true}, nil)
+	require.NotContains(t, out, "Intent Gaps")
+}
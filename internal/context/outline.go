@@ -0,0 +1,72 @@
+package context
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strings"
+)
+
+// outlineFile summarizes a file that doesn't fit the token budget
+// Go files are reduced to their declarations; other files have no summary ("")
+func outlineFile(path, content string) string {
+	if !strings.HasSuffix(path, ".go") {
+		return ""
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, content, parser.SkipObjectResolution)
+	if err != nil {
+		return ""
+	}
+
+	var decls []string
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			d.Body = nil
+			d.Doc = nil
+			decls = append(decls, nodeString(fset, d))
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					decls = append(decls, "type "+s.Name.Name+" "+typeKind(fset, s.Type))
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						decls = append(decls, d.Tok.String()+" "+name.Name)
+					}
+				}
+			}
+		}
+	}
+	if len(decls) == 0 {
+		return ""
+	}
+	return OutlinePrefix + "package " + file.Name.Name + "; " + strings.Join(decls, "; ")
+}
+
+// typeKind names the kind of a type declaration without its body
+func typeKind(fset *token.FileSet, expr ast.Expr) string {
+	switch expr.(type) {
+	case *ast.StructType:
+		return "struct"
+	case *ast.InterfaceType:
+		return "interface"
+	case *ast.FuncType:
+		return "func"
+	default:
+		return nodeString(fset, expr)
+	}
+}
+
+// nodeString prints an AST node on one line
+func nodeString(fset *token.FileSet, node any) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
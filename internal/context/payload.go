@@ -0,0 +1,55 @@
+package context
+
+import (
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/tokens"
+)
+
+// Payload is how the changed files are sent to the model; either way every file is sent once
+type Payload string
+
+const (
+	// PayloadInline renders the files in the prompt, in markdown code fences
+	PayloadInline Payload = "inline"
+	// PayloadAttachments lists the files in the prompt and sends their content as attachments,
+	// which reach the model as <file path='...'> blocks after the prompt
+	PayloadAttachments Payload = "attachments"
+)
+
+// PayloadFor picks the payload for a tokenizer family (which follows the provider and model)
+// Claude is tuned for long documents in XML tags, so it gets attachments; other models read inline fences
+func PayloadFor(family tokens.Family) Payload {
+	if family == tokens.FamilyAnthropic {
+		return PayloadAttachments
+	}
+	return PayloadInline
+}
+
+// attachedFiles returns the files sent as attachments: those packed in full (none for inline payloads)
+func attachedFiles(payload Payload, files, pruned map[string]string) map[string]string {
+	if payload != PayloadAttachments {
+		return map[string]string{}
+	}
+	return lo.OmitBy(files, func(path, _ string) bool {
+		_, ok := pruned[path]
+		return ok
+	})
+}
+
+// dedupedTokens counts the tokens saved by sending each attached file once, instead of both inline and as an attachment
+// attached is the final attachment set after pruning, so a pruned file only counts while it's attached in full
+func dedupedTokens(tokenizer *tokens.Tokenizer, attached map[string]string) int {
+	return lo.SumBy(lo.Values(attached), func(content string) int {
+		return tokenizer.Count(content) + tokens.ItemOverhead
+	})
+}
+
+// estimateTokens counts the prompt and its attachments
+func estimateTokens(tokenizer *tokens.Tokenizer, packed *packedContext) int {
+	estimate := tokenizer.Count(packed.Prompt)
+	for _, content := range packed.Attachments {
+		estimate += tokenizer.Count(content) + tokens.ItemOverhead
+	}
+	return estimate
+}
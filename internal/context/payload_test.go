@@ -0,0 +1,132 @@
+package context
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trankhanh040147/revcli/internal/tokens"
+)
+
+func TestPayloadFor(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, PayloadAttachments, PayloadFor(tokens.FamilyAnthropic))
+	require.Equal(t, PayloadInline, PayloadFor(tokens.FamilyOpenAI))
+	require.Equal(t, PayloadInline, PayloadFor(tokens.FamilyGeneric))
+}
+
+func TestPackContextPayload(t *testing.T) {
+	t.Parallel()
+
+	diff := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-package a\n+package a // changed\n"
+	files := map[string]string{
+		"a.go": "package a // changed\n\nfunc FullBody() {}\n",
+		"b.go": "package b\n\nfunc PrunedBody() {}\n",
+	}
+	summaries := map[string]string{"b.go": "Package b declares PrunedBody."}
+	tokenizer := tokens.NewTokenizer(tokens.FamilyGeneric)
+	budget := tokens.Budget{ContextWindow: 100000, Tokenizer: tokenizer}
+
+	tests := []struct {
+		name            string
+		payload         Payload
+		wantAttachments map[string]string
+		wantInline      bool
+		wantDeduped     int
+	}{
+		{
+			name:            "anthropic attaches the files packed in full",
+			payload:         PayloadFor(tokens.FamilyAnthropic),
+			wantAttachments: map[string]string{"a.go": files["a.go"]},
+			wantDeduped:     tokenizer.Count(files["a.go"]) + tokens.ItemOverhead,
+		},
+		{
+			name:            "other providers inline the files",
+			payload:         PayloadFor(tokens.FamilyOpenAI),
+			wantAttachments: map[string]string{},
+			wantInline:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			packed, err := packContext(budget, &promptRenderer{payload: tt.payload}, diff, files, summaries, nil)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantAttachments, packed.Attachments)
+
+			// The full file is sent once: attached or in the prompt, never both
+			if tt.wantInline {
+				require.Contains(t, packed.Prompt, "func FullBody() {}")
+			} else {
+				require.NotContains(t, packed.Prompt, "func FullBody() {}")
+				require.Contains(t, packed.Prompt, "#### File: `a.go` (attached below)")
+			}
+
+			// A pruned file only sends its summary
+			require.Contains(t, packed.Prompt, "Package b declares PrunedBody.")
+			require.NotContains(t, packed.Prompt, "func PrunedBody() {}")
+			require.NotContains(t, packed.Attachments, "b.go")
+
+			// Every attached file is counted once, pruned and inlined files aren't
+			require.Equal(t, tt.wantDeduped, dedupedTokens(tokenizer, packed.Attachments))
+		})
+	}
+}
+
+func TestAttachedFiles(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{"a.go": "package a\n", "b.go": "package b\n"}
+	pruned := map[string]string{"b.go": "Package b."}
+
+	require.Equal(t, map[string]string{"a.go": "package a\n"}, attachedFiles(PayloadAttachments, files, pruned))
+	require.Empty(t, attachedFiles(PayloadInline, files, pruned))
+	require.Empty(t, attachedFiles(PayloadAttachments, files, files))
+	require.Zero(t, dedupedTokens(tokens.NewTokenizer(tokens.FamilyGeneric), attachedFiles(PayloadAttachments, files, files)))
+}
+
+func TestPackContextIsStable(t *testing.T) {
+	t.Parallel()
+
+	files := make(map[string]string)
+	for _, name := range []string{"e.go", "c.go", "a.go", "d.go", "b.go"} {
+		files[name] = "package " + name[:1] + "\n"
+	}
+	budget := tokens.Budget{ContextWindow: 100000, Tokenizer: tokens.NewTokenizer(tokens.FamilyGeneric)}
+	renderer := &promptRenderer{payload: PayloadInline}
+
+	// Map iteration order differs between runs; the prompt must not, so Claude models can cache it
+	first, err := packContext(budget, renderer, "diff", files, nil, nil)
+	require.NoError(t, err)
+	for range 10 {
+		packed, err := packContext(budget, renderer, "diff", files, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, first.Prompt, packed.Prompt)
+	}
+}
+
+func TestPruneRecountsDedupedTokens(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{"a.go": "package a\n\nfunc A() {}\n", "b.go": "package b\n\nfunc B() {}\n"}
+	tokenizer := tokens.NewTokenizer(tokens.FamilyGeneric)
+	budget := tokens.Budget{ContextWindow: 100000, Tokenizer: tokenizer}
+	renderer := &promptRenderer{payload: PayloadAttachments}
+	packed, err := packContext(budget, renderer, "diff", files, nil, nil)
+	require.NoError(t, err)
+	rc := &ReviewContext{
+		RawDiff:       "diff",
+		FileContents:  files,
+		TokenPlan:     packed.Plan,
+		DedupedTokens: dedupedTokens(tokenizer, packed.Attachments),
+		renderer:      renderer,
+	}
+	require.Equal(t, 2*(tokenizer.Count(files["a.go"])+tokens.ItemOverhead), rc.DedupedTokens)
+
+	// A pruned file is no longer attached, so it no longer counts
+	require.NoError(t, rc.Prune(map[string]string{"b.go": "Package b declares B."}))
+	require.Equal(t, map[string]string{"a.go": files["a.go"]}, rc.Attachments)
+	require.Equal(t, tokenizer.Count(files["a.go"])+tokens.ItemOverhead, rc.DedupedTokens)
+}
@@ -0,0 +1,68 @@
+package context
+
+import (
+	"text/template"
+
+	"github.com/trankhanh040147/revcli/internal/prompt"
+	"github.com/trankhanh040147/revcli/internal/tokens"
+)
+
+// promptRenderer renders review prompts with the review's template and metadata
+// A nil renderer uses the default template without metadata
+type promptRenderer struct {
+	// template is the user prompt template (nil uses the default)
+	template   *template.Template
+	intent     *Intent
+	branch     string
+	baseBranch string
+	commits    []string
+	payload    Payload
+}
+
+// payloadType returns how the renderer's prompts send files (inline for a nil renderer)
+func (r *promptRenderer) payloadType() Payload {
+	if r == nil || r.payload == "" {
+		return PayloadInline
+	}
+	return r.payload
+}
+
+// render renders a packed context into the review prompt
+func (r *promptRenderer) render(diff string, packed *packedContext) (string, error) {
+	data := prompt.NewReviewData(diff, packed.Files, packed.Pruned, packed.Sections)
+	for i, file := range data.Files {
+		_, data.Files[i].Attached = packed.Attachments[file.Path]
+	}
+	data.Tokens = tokenStats(packed.Plan)
+	if r == nil {
+		return prompt.RenderReview(nil, data)
+	}
+
+	data.Branch = r.branch
+	data.BaseBranch = r.baseBranch
+	data.Commits = r.commits
+	if r.intent != nil {
+		data.Intent = &prompt.TemplateIntent{
+			CustomInstruction:   r.intent.CustomInstruction,
+			FocusAreas:          r.intent.FocusAreas,
+			NegativeConstraints: r.intent.NegativeConstraints,
+			IssueKeys:           r.intent.IssueKeys,
+		}
+	}
+	return prompt.RenderReview(r.template, data)
+}
+
+// tokenStats describes a token plan for prompt templates
+func tokenStats(plan *tokens.Plan) prompt.TokenStats {
+	if plan == nil {
+		return prompt.TokenStats{}
+	}
+	return prompt.TokenStats{
+		Used:          plan.Used,
+		Available:     plan.Budget.Available(),
+		ContextWindow: plan.Budget.ContextWindow,
+		Tokenizer:     string(plan.Budget.Tokenizer.Family()),
+		Summarized:    len(plan.Summarized()),
+		Dropped:       len(plan.Dropped()),
+	}
+}
@@ -0,0 +1,43 @@
+package context
+
+import (
+	"fmt"
+	"maps"
+)
+
+// Prune replaces files with their summary and packs the context into the token budget again,
+// so the prompt, the token plan and the chunks reflect the pruned files
+func (rc *ReviewContext) Prune(summaries map[string]string) error {
+	if rc.Summaries == nil {
+		rc.Summaries = make(map[string]string)
+	}
+	maps.Copy(rc.Summaries, summaries)
+	if rc.TokenPlan == nil {
+		return nil
+	}
+
+	packed, err := packContext(rc.TokenPlan.Budget, rc.renderer, rc.RawDiff, rc.FileContents, rc.Summaries, rc.sections)
+	if err != nil {
+		return err
+	}
+	tokenizer := rc.TokenPlan.Budget.Tokenizer
+	rc.UserPrompt = packed.Prompt
+	rc.EstimatedTokens = estimateTokens(tokenizer, packed)
+	rc.TokenPlan = packed.Plan
+	rc.Attachments = packed.Attachments
+	rc.DedupedTokens = dedupedTokens(tokenizer, packed.Attachments)
+	rc.PrunedFiles = packed.Pruned
+	rc.Sections = packed.Sections
+
+	if len(rc.Chunks) > 0 {
+		chunks, err := rc.Split()
+		if err != nil {
+			return fmt.Errorf("failed to split the review into chunks: %w", err)
+		}
+		rc.Chunks = nil
+		if len(chunks) > 1 {
+			rc.Chunks = chunks
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package context
+
+import (
+	"github.com/trankhanh040147/revcli/internal/preset"
+	"github.com/trankhanh040147/revcli/internal/prompt"
+)
+
+// LanguageRules returns the rule packs for the detected languages, with the overrides from the
+// preset directory's rules/ applied: an override extends the built-in pack of the same name,
+// replaces it when replace is set, and adds a pack for a language without a built-in one
+func LanguageRules(languages []prompt.Language) ([]prompt.RulePack, error) {
+	var packs []prompt.RulePack
+	for _, name := range prompt.RulePackNames(languages) {
+		pack, builtIn := prompt.BuiltInRulePack(name)
+		override, err := preset.LoadRules(name)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case override == nil && !builtIn:
+			continue
+		case override == nil:
+		case !builtIn:
+			pack = prompt.RulePack{Name: name, Title: override.Name, Rules: override.Prompt}
+		case override.Replace:
+			pack.Rules = override.Prompt
+		default:
+			pack.Rules += "\n" + override.Prompt
+		}
+		packs = append(packs, pack)
+	}
+	return packs, nil
+}
@@ -0,0 +1,30 @@
+package conventions
+
+// RepoFile is the repository's own review conventions file, relative to the repository root
+const RepoFile = ".revcli/REVIEW.md"
+
+// DefaultPaths are the convention files loaded when no paths are configured, relative to the repository root
+var DefaultPaths = []string{
+	RepoFile,
+	"CONTRIBUTING.md",
+	".github/CONTRIBUTING.md",
+	"AGENTS.md",
+}
+
+// MaxFileChars caps what one convention file adds to the system prompt
+const MaxFileChars = 20000
+
+// TruncatedNote is appended to a convention file cut at MaxFileChars
+const TruncatedNote = "\n\n... (truncated) ...\n"
+
+// scopePrefix starts the comment that scopes a section to paths, e.g. <!-- revcli:paths internal/db/ migrations/*.sql -->
+const scopePrefix = "<!-- revcli:paths"
+
+// SectionTitle is the system prompt heading of the convention files
+const SectionTitle = "Repository Conventions"
+
+// SectionIntro explains the convention files to the model
+const SectionIntro = "Review against this repository's own conventions. They take precedence over the general guidance above. Sections marked with a path scope apply only to files under those paths.\n"
+
+// ScopeNoteFormat marks a scoped section in the system prompt
+const ScopeNoteFormat = "*(applies to %s)*\n"
@@ -0,0 +1,238 @@
+// Package conventions loads a repository's review guidance files (REVIEW.md, CONTRIBUTING.md, AGENTS.md, ...)
+// for the reviewer's system prompt
+package conventions
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/home"
+)
+
+// File is a convention file with the sections that apply to the change
+type File struct {
+	// Path is repo-relative, or absolute for files outside the repository
+	Path    string
+	Content string
+	// Skipped counts the scoped sections left out because no changed file is in their scope
+	Skipped int
+}
+
+// section is a markdown section of a convention file
+type section struct {
+	// level is the heading level (0 for text before the first heading)
+	level int
+	// scope are the path patterns the section applies to (empty applies everywhere)
+	scope []string
+	lines []string
+}
+
+// Load reads the convention files at paths (DefaultPaths when empty) and keeps the sections
+// that apply to the changed files
+// Paths are relative to rootDir unless absolute (~ is expanded); a directory loads every file under it,
+// and missing files are skipped
+func Load(rootDir string, paths []string, changedFiles []string) ([]File, error) {
+	if len(paths) == 0 {
+		paths = DefaultPaths
+	}
+
+	var files []File
+	seen := make(map[string]bool)
+	for _, p := range paths {
+		fullPath := home.Long(p)
+		if !filepath.IsAbs(fullPath) {
+			fullPath = filepath.Join(rootDir, fullPath)
+		}
+		sources, err := sourceFiles(fullPath)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, source := range sources {
+			if seen[source] {
+				continue
+			}
+			seen[source] = true
+
+			data, err := os.ReadFile(source)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read convention file %s: %w", source, err)
+			}
+			file := filter(string(data), changedFiles)
+			if strings.TrimSpace(file.Content) == "" {
+				continue
+			}
+			file.Path = displayPath(rootDir, source)
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// PromptSection renders the convention files for the system prompt ("" when there are none)
+func PromptSection(files []File) string {
+	if len(files) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("## %s\n\n%s", SectionTitle, SectionIntro))
+	for _, file := range files {
+		content := file.Content
+		if len(content) > MaxFileChars {
+			// Back off to a rune boundary so a multi-byte rune isn't split
+			cut := MaxFileChars
+			for cut > 0 && !utf8.RuneStart(content[cut]) {
+				cut--
+			}
+			content = content[:cut] + TruncatedNote
+		}
+		builder.WriteString(fmt.Sprintf("\n### %s\n\n", file.Path))
+		builder.WriteString(strings.TrimRight(content, "\n"))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// Paths returns the paths of the convention files
+func Paths(files []File) []string {
+	return lo.Map(files, func(f File, _ int) string { return f.Path })
+}
+
+// sourceFiles returns the file at fullPath, or the files under it when it's a directory (none when it's missing)
+func sourceFiles(fullPath string) ([]string, error) {
+	info, err := os.Stat(fullPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read convention file %s: %w", fullPath, err)
+	}
+	if !info.IsDir() {
+		return []string{fullPath}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(fullPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read convention directory %s: %w", fullPath, err)
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// filter keeps the sections of a convention file that are unscoped or in scope of a changed file
+// A section's scope covers its subsections, up to the next heading of the same or a higher level
+func filter(content string, changedFiles []string) File {
+	var file File
+	var kept []string
+	// skipLevel is the heading level of the out-of-scope section being skipped (0 when none is)
+	skipLevel := 0
+	for _, s := range parseSections(content) {
+		if skipLevel > 0 && s.level > skipLevel {
+			continue
+		}
+		skipLevel = 0
+
+		if len(s.scope) > 0 && !lo.SomeBy(changedFiles, func(f string) bool { return inScope(s.scope, f) }) {
+			file.Skipped++
+			skipLevel = s.level
+			continue
+		}
+		kept = append(kept, s.lines...)
+	}
+	file.Content = strings.Join(kept, "\n")
+	return file
+}
+
+// parseSections splits markdown into sections at its headings, ignoring headings in code blocks
+// A scope comment on the line after a heading scopes the section and is replaced by a note for the model
+func parseSections(content string) []section {
+	current := &section{}
+	sections := []*section{current}
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+
+		if level := headingLevel(line); level > 0 && !inFence {
+			current = &section{level: level}
+			sections = append(sections, current)
+		} else if scope, ok := parseScope(trimmed); ok && len(current.lines) == 1 && current.level > 0 {
+			current.scope = scope
+			line = fmt.Sprintf(ScopeNoteFormat, strings.Join(scope, ", "))
+		}
+		current.lines = append(current.lines, line)
+	}
+	return lo.Map(sections, func(s *section, _ int) section { return *s })
+}
+
+// headingLevel returns the level of a markdown ATX heading (0 when the line isn't one)
+func headingLevel(line string) int {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 || (len(line) > level && line[level] != ' ') {
+		return 0
+	}
+	return level
+}
+
+// parseScope parses a scope comment, e.g. <!-- revcli:paths internal/db/ migrations/*.sql -->
+func parseScope(line string) ([]string, bool) {
+	rest, ok := strings.CutPrefix(line, scopePrefix)
+	if !ok {
+		return nil, false
+	}
+	rest, ok = strings.CutSuffix(strings.TrimSpace(rest), "-->")
+	if !ok {
+		return nil, false
+	}
+	scope := strings.FieldsFunc(rest, func(r rune) bool { return r == ' ' || r == ',' })
+	return scope, len(scope) > 0
+}
+
+// inScope reports whether a repo-relative file matches one of the scope patterns:
+// a directory prefix (internal/db/ or internal/db/**) or a glob (migrations/*.sql, *.proto)
+func inScope(scope []string, file string) bool {
+	return lo.SomeBy(scope, func(pattern string) bool {
+		pattern = strings.TrimPrefix(pattern, "./")
+		if dir, ok := strings.CutSuffix(pattern, "**"); ok {
+			pattern = dir
+		}
+		if strings.HasSuffix(pattern, "/") {
+			return strings.HasPrefix(file, pattern)
+		}
+		if matched, _ := path.Match(pattern, file); matched {
+			return true
+		}
+		// A pattern without a directory matches the file name anywhere
+		matched, _ := path.Match(pattern, path.Base(file))
+		return matched && !strings.Contains(pattern, "/")
+	})
+}
+
+// displayPath returns a file's repo-relative path, or its absolute path when it's outside the repository
+func displayPath(rootDir, file string) string {
+	rel, err := filepath.Rel(rootDir, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return home.Short(file)
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,97 @@
+package conventions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+const reviewMD = `# Review Guide
+
+Wrap every error.
+
+## Database
+<!-- revcli:paths internal/db/ migrations/*.sql -->
+
+Use transactions for multi-statement writes.
+
+### Queries
+
+No SELECT *.
+
+## Frontend
+<!-- revcli:paths web/** -->
+
+Use the design system.
+
+## Testing
+
+` + "```md\n# not a heading\n```" + `
+Table tests only.
+`
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".revcli"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".revcli", "REVIEW.md"), []byte(reviewMD), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "AGENTS.md"), []byte("Run make lint.\n"), 0o644))
+
+	files, err := Load(dir, nil, []string{"internal/db/user.go"})
+	require.NoError(t, err)
+	require.Equal(t, []string{".revcli/REVIEW.md", "AGENTS.md"}, Paths(files))
+
+	review := files[0]
+	require.Equal(t, 1, review.Skipped)
+	require.Contains(t, review.Content, "Wrap every error.")
+	require.Contains(t, review.Content, "*(applies to internal/db/, migrations/*.sql)*")
+	require.Contains(t, review.Content, "No SELECT *.")
+	require.NotContains(t, review.Content, "design system")
+	require.NotContains(t, review.Content, "revcli:paths")
+	require.Contains(t, review.Content, "# not a heading\n```\nTable tests only.")
+
+	section := PromptSection(files)
+	require.Contains(t, section, "## "+SectionTitle)
+	require.Contains(t, section, "### .revcli/REVIEW.md\n\n# Review Guide")
+	require.Contains(t, section, "### AGENTS.md\n\nRun make lint.")
+	require.Empty(t, PromptSection(nil))
+
+	// A multi-byte rune straddling the cap is dropped whole instead of split
+	long := File{Path: "CONTRIBUTING.md", Content: strings.Repeat("a", MaxFileChars-1) + "é"}
+	truncated := PromptSection([]File{long})
+	require.True(t, utf8.ValidString(truncated))
+	require.Contains(t, truncated, strings.Repeat("a", MaxFileChars-1)+strings.TrimRight(TruncatedNote, "\n"))
+
+	// Configured paths replace the defaults
+	files, err = Load(dir, []string{"AGENTS.md", "missing.md"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"AGENTS.md"}, Paths(files))
+}
+
+func TestInScope(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		file    string
+		want    bool
+	}{
+		{"internal/db/", "internal/db/user.go", true},
+		{"internal/db/**", "internal/db/sql/query.go", true},
+		{"internal/db/", "internal/dbx/user.go", false},
+		{"migrations/*.sql", "migrations/001.sql", true},
+		{"*.proto", "api/v1/user.proto", true},
+		{"api/*.proto", "api/v1/user.proto", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.file, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, inScope([]string{tt.pattern}, tt.file))
+		})
+	}
+}
@@ -27,9 +27,15 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.createFileStmt, err = db.PrepareContext(ctx, createFile); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateFile: %w", err)
 	}
+	if q.createFindingStmt, err = db.PrepareContext(ctx, createFinding); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateFinding: %w", err)
+	}
 	if q.createMessageStmt, err = db.PrepareContext(ctx, createMessage); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateMessage: %w", err)
 	}
+	if q.createReviewRunStmt, err = db.PrepareContext(ctx, createReviewRun); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateReviewRun: %w", err)
+	}
 	if q.createSessionStmt, err = db.PrepareContext(ctx, createSession); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateSession: %w", err)
 	}
@@ -54,9 +60,21 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getFileByPathAndSessionStmt, err = db.PrepareContext(ctx, getFileByPathAndSession); err != nil {
 		return nil, fmt.Errorf("error preparing query GetFileByPathAndSession: %w", err)
 	}
+	if q.getFileSummaryStmt, err = db.PrepareContext(ctx, getFileSummary); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFileSummary: %w", err)
+	}
+	if q.getLatestFindingByFingerprintStmt, err = db.PrepareContext(ctx, getLatestFindingByFingerprint); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLatestFindingByFingerprint: %w", err)
+	}
 	if q.getMessageStmt, err = db.PrepareContext(ctx, getMessage); err != nil {
 		return nil, fmt.Errorf("error preparing query GetMessage: %w", err)
 	}
+	if q.getPreviousReviewRunStmt, err = db.PrepareContext(ctx, getPreviousReviewRun); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPreviousReviewRun: %w", err)
+	}
+	if q.getReviewIntentStmt, err = db.PrepareContext(ctx, getReviewIntent); err != nil {
+		return nil, fmt.Errorf("error preparing query GetReviewIntent: %w", err)
+	}
 	if q.getSessionByIDStmt, err = db.PrepareContext(ctx, getSessionByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetSessionByID: %w", err)
 	}
@@ -66,6 +84,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.listFilesBySessionStmt, err = db.PrepareContext(ctx, listFilesBySession); err != nil {
 		return nil, fmt.Errorf("error preparing query ListFilesBySession: %w", err)
 	}
+	if q.listFindingsBySessionStmt, err = db.PrepareContext(ctx, listFindingsBySession); err != nil {
+		return nil, fmt.Errorf("error preparing query ListFindingsBySession: %w", err)
+	}
 	if q.listLatestSessionFilesStmt, err = db.PrepareContext(ctx, listLatestSessionFiles); err != nil {
 		return nil, fmt.Errorf("error preparing query ListLatestSessionFiles: %w", err)
 	}
@@ -87,6 +108,12 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.updateSessionTitleAndUsageStmt, err = db.PrepareContext(ctx, updateSessionTitleAndUsage); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateSessionTitleAndUsage: %w", err)
 	}
+	if q.upsertFileSummaryStmt, err = db.PrepareContext(ctx, upsertFileSummary); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertFileSummary: %w", err)
+	}
+	if q.upsertReviewIntentStmt, err = db.PrepareContext(ctx, upsertReviewIntent); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertReviewIntent: %w", err)
+	}
 	return &q, nil
 }
 
@@ -97,11 +124,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createFileStmt: %w", cerr)
 		}
 	}
+	if q.createFindingStmt != nil {
+		if cerr := q.createFindingStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createFindingStmt: %w", cerr)
+		}
+	}
 	if q.createMessageStmt != nil {
 		if cerr := q.createMessageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createMessageStmt: %w", cerr)
 		}
 	}
+	if q.createReviewRunStmt != nil {
+		if cerr := q.createReviewRunStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createReviewRunStmt: %w", cerr)
+		}
+	}
 	if q.createSessionStmt != nil {
 		if cerr := q.createSessionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createSessionStmt: %w", cerr)
@@ -142,11 +179,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getFileByPathAndSessionStmt: %w", cerr)
 		}
 	}
+	if q.getFileSummaryStmt != nil {
+		if cerr := q.getFileSummaryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFileSummaryStmt: %w", cerr)
+		}
+	}
+	if q.getLatestFindingByFingerprintStmt != nil {
+		if cerr := q.getLatestFindingByFingerprintStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLatestFindingByFingerprintStmt: %w", cerr)
+		}
+	}
 	if q.getMessageStmt != nil {
 		if cerr := q.getMessageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getMessageStmt: %w", cerr)
 		}
 	}
+	if q.getPreviousReviewRunStmt != nil {
+		if cerr := q.getPreviousReviewRunStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPreviousReviewRunStmt: %w", cerr)
+		}
+	}
+	if q.getReviewIntentStmt != nil {
+		if cerr := q.getReviewIntentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getReviewIntentStmt: %w", cerr)
+		}
+	}
 	if q.getSessionByIDStmt != nil {
 		if cerr := q.getSessionByIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getSessionByIDStmt: %w", cerr)
@@ -162,6 +219,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listFilesBySessionStmt: %w", cerr)
 		}
 	}
+	if q.listFindingsBySessionStmt != nil {
+		if cerr := q.listFindingsBySessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listFindingsBySessionStmt: %w", cerr)
+		}
+	}
 	if q.listLatestSessionFilesStmt != nil {
 		if cerr := q.listLatestSessionFilesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listLatestSessionFilesStmt: %w", cerr)
@@ -197,6 +259,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateSessionTitleAndUsageStmt: %w", cerr)
 		}
 	}
+	if q.upsertFileSummaryStmt != nil {
+		if cerr := q.upsertFileSummaryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertFileSummaryStmt: %w", cerr)
+		}
+	}
+	if q.upsertReviewIntentStmt != nil {
+		if cerr := q.upsertReviewIntentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertReviewIntentStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -234,55 +306,73 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                             DBTX
-	tx                             *sql.Tx
-	createFileStmt                 *sql.Stmt
-	createMessageStmt              *sql.Stmt
-	createSessionStmt              *sql.Stmt
-	deleteFileStmt                 *sql.Stmt
-	deleteMessageStmt              *sql.Stmt
-	deleteSessionStmt              *sql.Stmt
-	deleteSessionFilesStmt         *sql.Stmt
-	deleteSessionMessagesStmt      *sql.Stmt
-	getFileStmt                    *sql.Stmt
-	getFileByPathAndSessionStmt    *sql.Stmt
-	getMessageStmt                 *sql.Stmt
-	getSessionByIDStmt             *sql.Stmt
-	listFilesByPathStmt            *sql.Stmt
-	listFilesBySessionStmt         *sql.Stmt
-	listLatestSessionFilesStmt     *sql.Stmt
-	listMessagesBySessionStmt      *sql.Stmt
-	listNewFilesStmt               *sql.Stmt
-	listSessionsStmt               *sql.Stmt
-	updateMessageStmt              *sql.Stmt
-	updateSessionStmt              *sql.Stmt
-	updateSessionTitleAndUsageStmt *sql.Stmt
+	db                                DBTX
+	tx                                *sql.Tx
+	createFileStmt                    *sql.Stmt
+	createFindingStmt                 *sql.Stmt
+	createMessageStmt                 *sql.Stmt
+	createReviewRunStmt               *sql.Stmt
+	createSessionStmt                 *sql.Stmt
+	deleteFileStmt                    *sql.Stmt
+	deleteMessageStmt                 *sql.Stmt
+	deleteSessionStmt                 *sql.Stmt
+	deleteSessionFilesStmt            *sql.Stmt
+	deleteSessionMessagesStmt         *sql.Stmt
+	getFileStmt                       *sql.Stmt
+	getFileByPathAndSessionStmt       *sql.Stmt
+	getFileSummaryStmt                *sql.Stmt
+	getLatestFindingByFingerprintStmt *sql.Stmt
+	getMessageStmt                    *sql.Stmt
+	getPreviousReviewRunStmt          *sql.Stmt
+	getReviewIntentStmt               *sql.Stmt
+	getSessionByIDStmt                *sql.Stmt
+	listFilesByPathStmt               *sql.Stmt
+	listFilesBySessionStmt            *sql.Stmt
+	listFindingsBySessionStmt         *sql.Stmt
+	listLatestSessionFilesStmt        *sql.Stmt
+	listMessagesBySessionStmt         *sql.Stmt
+	listNewFilesStmt                  *sql.Stmt
+	listSessionsStmt                  *sql.Stmt
+	updateMessageStmt                 *sql.Stmt
+	updateSessionStmt                 *sql.Stmt
+	updateSessionTitleAndUsageStmt    *sql.Stmt
+	upsertFileSummaryStmt             *sql.Stmt
+	upsertReviewIntentStmt            *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                             tx,
-		tx:                             tx,
-		createFileStmt:                 q.createFileStmt,
-		createMessageStmt:              q.createMessageStmt,
-		createSessionStmt:              q.createSessionStmt,
-		deleteFileStmt:                 q.deleteFileStmt,
-		deleteMessageStmt:              q.deleteMessageStmt,
-		deleteSessionStmt:              q.deleteSessionStmt,
-		deleteSessionFilesStmt:         q.deleteSessionFilesStmt,
-		deleteSessionMessagesStmt:      q.deleteSessionMessagesStmt,
-		getFileStmt:                    q.getFileStmt,
-		getFileByPathAndSessionStmt:    q.getFileByPathAndSessionStmt,
-		getMessageStmt:                 q.getMessageStmt,
-		getSessionByIDStmt:             q.getSessionByIDStmt,
-		listFilesByPathStmt:            q.listFilesByPathStmt,
-		listFilesBySessionStmt:         q.listFilesBySessionStmt,
-		listLatestSessionFilesStmt:     q.listLatestSessionFilesStmt,
-		listMessagesBySessionStmt:      q.listMessagesBySessionStmt,
-		listNewFilesStmt:               q.listNewFilesStmt,
-		listSessionsStmt:               q.listSessionsStmt,
-		updateMessageStmt:              q.updateMessageStmt,
-		updateSessionStmt:              q.updateSessionStmt,
-		updateSessionTitleAndUsageStmt: q.updateSessionTitleAndUsageStmt,
+		db:                                tx,
+		tx:                                tx,
+		createFileStmt:                    q.createFileStmt,
+		createFindingStmt:                 q.createFindingStmt,
+		createMessageStmt:                 q.createMessageStmt,
+		createReviewRunStmt:               q.createReviewRunStmt,
+		createSessionStmt:                 q.createSessionStmt,
+		deleteFileStmt:                    q.deleteFileStmt,
+		deleteMessageStmt:                 q.deleteMessageStmt,
+		deleteSessionStmt:                 q.deleteSessionStmt,
+		deleteSessionFilesStmt:            q.deleteSessionFilesStmt,
+		deleteSessionMessagesStmt:         q.deleteSessionMessagesStmt,
+		getFileStmt:                       q.getFileStmt,
+		getFileByPathAndSessionStmt:       q.getFileByPathAndSessionStmt,
+		getFileSummaryStmt:                q.getFileSummaryStmt,
+		getLatestFindingByFingerprintStmt: q.getLatestFindingByFingerprintStmt,
+		getMessageStmt:                    q.getMessageStmt,
+		getPreviousReviewRunStmt:          q.getPreviousReviewRunStmt,
+		getReviewIntentStmt:               q.getReviewIntentStmt,
+		getSessionByIDStmt:                q.getSessionByIDStmt,
+		listFilesByPathStmt:               q.listFilesByPathStmt,
+		listFilesBySessionStmt:            q.listFilesBySessionStmt,
+		listFindingsBySessionStmt:         q.listFindingsBySessionStmt,
+		listLatestSessionFilesStmt:        q.listLatestSessionFilesStmt,
+		listMessagesBySessionStmt:         q.listMessagesBySessionStmt,
+		listNewFilesStmt:                  q.listNewFilesStmt,
+		listSessionsStmt:                  q.listSessionsStmt,
+		updateMessageStmt:                 q.updateMessageStmt,
+		updateSessionStmt:                 q.updateSessionStmt,
+		updateSessionTitleAndUsageStmt:    q.updateSessionTitleAndUsageStmt,
+		upsertFileSummaryStmt:             q.upsertFileSummaryStmt,
+		upsertReviewIntentStmt:            q.upsertReviewIntentStmt,
 	}
 }
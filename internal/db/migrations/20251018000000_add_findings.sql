@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Review findings, persisted per run so they can be suppressed and compared across runs
+CREATE TABLE IF NOT EXISTS findings (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    repo TEXT NOT NULL,
+    branch TEXT NOT NULL,
+    head_sha TEXT NOT NULL,
+    severity TEXT NOT NULL,
+    category TEXT NOT NULL,
+    path TEXT NOT NULL,
+    line INTEGER NOT NULL DEFAULT 0,
+    snippet TEXT NOT NULL,
+    message TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_findings_run ON findings (repo, branch, head_sha);
+CREATE INDEX IF NOT EXISTS idx_findings_fingerprint ON findings (repo, fingerprint);
+CREATE INDEX IF NOT EXISTS idx_findings_session_id ON findings (session_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_findings_session_id;
+DROP INDEX IF EXISTS idx_findings_fingerprint;
+DROP INDEX IF EXISTS idx_findings_run;
+DROP TABLE IF EXISTS findings;
+-- +goose StatementEnd
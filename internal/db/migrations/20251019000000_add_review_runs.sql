@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One row per review, so a run without findings still counts as the previous run
+CREATE TABLE IF NOT EXISTS review_runs (
+    session_id TEXT PRIMARY KEY,
+    repo TEXT NOT NULL,
+    branch TEXT NOT NULL,
+    head_sha TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_runs_branch ON review_runs (repo, branch, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_review_runs_branch;
+DROP TABLE IF EXISTS review_runs;
+-- +goose StatementEnd
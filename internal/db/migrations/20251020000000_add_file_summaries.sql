@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Pruned file summaries, keyed by a hash of the file content so they are reused across reviews
+CREATE TABLE IF NOT EXISTS file_summaries (
+    content_hash TEXT PRIMARY KEY,
+    path TEXT NOT NULL,
+    summary TEXT NOT NULL,
+    created_at INTEGER NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS file_summaries;
+-- +goose StatementEnd
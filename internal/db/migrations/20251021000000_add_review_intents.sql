@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The last review intent per repository and branch, to prefill the intent form
+CREATE TABLE IF NOT EXISTS review_intents (
+    repo TEXT NOT NULL,
+    branch TEXT NOT NULL,
+    instruction TEXT NOT NULL DEFAULT '',
+    focus_areas TEXT NOT NULL DEFAULT '[]',
+    negative_constraints TEXT NOT NULL DEFAULT '[]',
+    check_intent INTEGER NOT NULL DEFAULT 0,
+    updated_at INTEGER NOT NULL,
+    PRIMARY KEY (repo, branch)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS review_intents;
+-- +goose StatementEnd
@@ -18,6 +18,29 @@ type File struct {
 	UpdatedAt int64  `json:"updated_at"`
 }
 
+type FileSummary struct {
+	ContentHash string `json:"content_hash"`
+	Path        string `json:"path"`
+	Summary     string `json:"summary"`
+	CreatedAt   int64  `json:"created_at"`
+}
+
+type Finding struct {
+	ID          string `json:"id"`
+	SessionID   string `json:"session_id"`
+	Fingerprint string `json:"fingerprint"`
+	Repo        string `json:"repo"`
+	Branch      string `json:"branch"`
+	HeadSha     string `json:"head_sha"`
+	Severity    string `json:"severity"`
+	Category    string `json:"category"`
+	Path        string `json:"path"`
+	Line        int64  `json:"line"`
+	Snippet     string `json:"snippet"`
+	Message     string `json:"message"`
+	CreatedAt   int64  `json:"created_at"`
+}
+
 type Message struct {
 	ID               string         `json:"id"`
 	SessionID        string         `json:"session_id"`
@@ -31,6 +54,24 @@ type Message struct {
 	IsSummaryMessage int64          `json:"is_summary_message"`
 }
 
+type ReviewIntent struct {
+	Repo                string `json:"repo"`
+	Branch              string `json:"branch"`
+	Instruction         string `json:"instruction"`
+	FocusAreas          string `json:"focus_areas"`
+	NegativeConstraints string `json:"negative_constraints"`
+	CheckIntent         int64  `json:"check_intent"`
+	UpdatedAt           int64  `json:"updated_at"`
+}
+
+type ReviewRun struct {
+	SessionID string `json:"session_id"`
+	Repo      string `json:"repo"`
+	Branch    string `json:"branch"`
+	HeadSha   string `json:"head_sha"`
+	CreatedAt int64  `json:"created_at"`
+}
+
 type Session struct {
 	ID               string         `json:"id"`
 	ParentSessionID  sql.NullString `json:"parent_session_id"`
@@ -10,7 +10,9 @@ import (
 
 type Querier interface {
 	CreateFile(ctx context.Context, arg CreateFileParams) (File, error)
+	CreateFinding(ctx context.Context, arg CreateFindingParams) (Finding, error)
 	CreateMessage(ctx context.Context, arg CreateMessageParams) (Message, error)
+	CreateReviewRun(ctx context.Context, arg CreateReviewRunParams) (ReviewRun, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	DeleteFile(ctx context.Context, id string) error
 	DeleteMessage(ctx context.Context, id string) error
@@ -19,10 +21,15 @@ type Querier interface {
 	DeleteSessionMessages(ctx context.Context, sessionID string) error
 	GetFile(ctx context.Context, id string) (File, error)
 	GetFileByPathAndSession(ctx context.Context, arg GetFileByPathAndSessionParams) (File, error)
+	GetFileSummary(ctx context.Context, contentHash string) (FileSummary, error)
+	GetLatestFindingByFingerprint(ctx context.Context, arg GetLatestFindingByFingerprintParams) (Finding, error)
 	GetMessage(ctx context.Context, id string) (Message, error)
+	GetPreviousReviewRun(ctx context.Context, arg GetPreviousReviewRunParams) (ReviewRun, error)
+	GetReviewIntent(ctx context.Context, arg GetReviewIntentParams) (ReviewIntent, error)
 	GetSessionByID(ctx context.Context, id string) (Session, error)
 	ListFilesByPath(ctx context.Context, path string) ([]File, error)
 	ListFilesBySession(ctx context.Context, sessionID string) ([]File, error)
+	ListFindingsBySession(ctx context.Context, sessionID string) ([]Finding, error)
 	ListLatestSessionFiles(ctx context.Context, sessionID string) ([]File, error)
 	ListMessagesBySession(ctx context.Context, sessionID string) ([]Message, error)
 	ListNewFiles(ctx context.Context) ([]File, error)
@@ -30,6 +37,8 @@ type Querier interface {
 	UpdateMessage(ctx context.Context, arg UpdateMessageParams) error
 	UpdateSession(ctx context.Context, arg UpdateSessionParams) (Session, error)
 	UpdateSessionTitleAndUsage(ctx context.Context, arg UpdateSessionTitleAndUsageParams) error
+	UpsertFileSummary(ctx context.Context, arg UpsertFileSummaryParams) error
+	UpsertReviewIntent(ctx context.Context, arg UpsertReviewIntentParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,18 @@
+-- name: GetFileSummary :one
+SELECT *
+FROM file_summaries
+WHERE content_hash = ? LIMIT 1;
+
+-- name: UpsertFileSummary :exec
+INSERT INTO file_summaries (
+    content_hash,
+    path,
+    summary,
+    created_at
+) VALUES (
+    ?, ?, ?, strftime('%s', 'now')
+)
+ON CONFLICT (content_hash) DO UPDATE SET
+    path = excluded.path,
+    summary = excluded.summary,
+    created_at = excluded.created_at;
@@ -0,0 +1,32 @@
+-- name: CreateFinding :one
+INSERT INTO findings (
+    id,
+    session_id,
+    fingerprint,
+    repo,
+    branch,
+    head_sha,
+    severity,
+    category,
+    path,
+    line,
+    snippet,
+    message,
+    created_at
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, strftime('%s', 'now')
+)
+RETURNING *;
+
+-- name: GetLatestFindingByFingerprint :one
+SELECT *
+FROM findings
+WHERE repo = ? AND fingerprint LIKE ?
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: ListFindingsBySession :many
+SELECT *
+FROM findings
+WHERE session_id = ?
+ORDER BY path ASC, line ASC;
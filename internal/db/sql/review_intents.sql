@@ -0,0 +1,23 @@
+-- name: GetReviewIntent :one
+SELECT *
+FROM review_intents
+WHERE repo = ? AND branch = ? LIMIT 1;
+
+-- name: UpsertReviewIntent :exec
+INSERT INTO review_intents (
+    repo,
+    branch,
+    instruction,
+    focus_areas,
+    negative_constraints,
+    check_intent,
+    updated_at
+) VALUES (
+    ?, ?, ?, ?, ?, ?, strftime('%s', 'now')
+)
+ON CONFLICT (repo, branch) DO UPDATE SET
+    instruction = excluded.instruction,
+    focus_areas = excluded.focus_areas,
+    negative_constraints = excluded.negative_constraints,
+    check_intent = excluded.check_intent,
+    updated_at = excluded.updated_at;
@@ -0,0 +1,18 @@
+-- name: CreateReviewRun :one
+INSERT INTO review_runs (
+    session_id,
+    repo,
+    branch,
+    head_sha,
+    created_at
+) VALUES (
+    ?, ?, ?, ?, strftime('%s', 'now')
+)
+RETURNING *;
+
+-- name: GetPreviousReviewRun :one
+SELECT *
+FROM review_runs
+WHERE repo = ? AND branch = ? AND session_id != ?
+ORDER BY created_at DESC, rowid DESC
+LIMIT 1;
@@ -65,7 +65,7 @@ func Filter(files map[string]string, rawDiff string) *FilterResult {
 
 	for path, content := range files {
 		// Check if file should be ignored
-		if shouldIgnore(path) {
+		if ShouldIgnore(path) {
 			result.IgnoredFiles = append(result.IgnoredFiles, path)
 			continue
 		}
@@ -84,8 +84,8 @@ func Filter(files map[string]string, rawDiff string) *FilterResult {
 	return result
 }
 
-// shouldIgnore checks if a file path matches any ignored pattern
-func shouldIgnore(path string) bool {
+// ShouldIgnore checks if a file path matches any ignored pattern
+func ShouldIgnore(path string) bool {
 	for _, pattern := range IgnoredPatterns {
 		// Check if pattern is a suffix match (for extensions)
 		if strings.HasSuffix(pattern, ".go") || strings.HasSuffix(pattern, ".sum") || strings.HasSuffix(pattern, ".mod") {
@@ -142,6 +142,14 @@ func maskSecret(secret string) string {
 	return prefix + "***" + suffix
 }
 
+// RedactSecrets masks every potential secret in content, like the matches reported by Filter
+func RedactSecrets(content string) string {
+	for _, pattern := range SecretPatterns {
+		content = pattern.ReplaceAllStringFunc(content, maskSecret)
+	}
+	return content
+}
+
 // HasSecrets returns true if any secrets were found
 func (r *FilterResult) HasSecrets() bool {
 	return len(r.SecretsFound) > 0
@@ -162,7 +170,7 @@ func FilterDiff(rawDiff string) string {
 				bPath := parts[3]
 				if strings.HasPrefix(bPath, "b/") {
 					currentFile = strings.TrimPrefix(bPath, "b/")
-					skipFile = shouldIgnore(currentFile)
+					skipFile = ShouldIgnore(currentFile)
 				}
 			}
 		}
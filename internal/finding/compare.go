@@ -0,0 +1,111 @@
+package finding
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// Status is a finding's state relative to the previous run on the same branch
+type Status string
+
+const (
+	StatusNew        Status = "new"
+	StatusPersisting Status = "persisting"
+	StatusResolved   Status = "resolved"
+)
+
+// Comparison is the difference between the current run and the previous one
+type Comparison struct {
+	Previous   Run
+	New        []*Finding
+	Persisting []*Finding
+	// Resolved are previous findings that are gone (findings suppressed since don't count)
+	Resolved []Record
+}
+
+// Compare matches current findings against the previous run by fingerprint
+func Compare(previous Run, previousFindings []Record, current []*Finding, suppressions []Suppression, branch string) *Comparison {
+	seen := lo.SliceToMap(previousFindings, func(r Record) (string, bool) { return r.Fingerprint, true })
+	persisting, added := lo.FilterReject(current, func(f *Finding, _ int) bool { return seen[f.Fingerprint] })
+
+	currentFingerprints := lo.SliceToMap(current, func(f *Finding) (string, bool) { return f.Fingerprint, true })
+	resolved := lo.Filter(previousFindings, func(r Record, _ int) bool {
+		suppressed := lo.ContainsBy(suppressions, func(s Suppression) bool {
+			return s.matchesFingerprint(r.Fingerprint, branch)
+		})
+		return !currentFingerprints[r.Fingerprint] && !suppressed
+	})
+	// A finding can be stored twice in one run when the model repeats itself
+	resolved = lo.UniqBy(resolved, func(r Record) string { return r.Fingerprint })
+
+	return &Comparison{Previous: previous, New: added, Persisting: persisting, Resolved: resolved}
+}
+
+// Summary returns a one-line count of new, persisting and resolved findings
+func (c *Comparison) Summary() string {
+	return fmt.Sprintf(ComparisonSummaryFormat,
+		shortSHA(c.Previous.HeadSHA), len(c.New), len(c.Persisting), len(c.Resolved))
+}
+
+// Report renders the comparison as a plain-text list of new, persisting and resolved findings
+func (c *Comparison) Report() string {
+	var builder strings.Builder
+	builder.WriteString(c.Summary())
+	builder.WriteString("\n")
+	for _, f := range c.New {
+		builder.WriteString(fmt.Sprintf("  🆕 #%s [%s] %s\n", f.ID, f.Category, firstLine(f.Message)))
+	}
+	for _, f := range c.Persisting {
+		builder.WriteString(fmt.Sprintf("  ↻ #%s [%s] %s\n", f.ID, f.Category, firstLine(f.Message)))
+	}
+	for _, r := range c.Resolved {
+		builder.WriteString(fmt.Sprintf("  ✓ #%s [%s] %s\n", r.Fingerprint[:IDLength], r.Category, firstLine(r.Message)))
+	}
+	return builder.String()
+}
+
+// Badge adds a status badge after each finding's ID tag and lists resolved findings at the end
+// The response must have been rewritten by Rewrite (so findings carry their ID tag)
+func Badge(response string, c *Comparison) string {
+	for _, f := range c.New {
+		response = addBadge(response, f.ID, BadgeNew)
+	}
+	for _, f := range c.Persisting {
+		response = addBadge(response, f.ID, BadgePersisting)
+	}
+	if len(c.Resolved) == 0 {
+		return response
+	}
+
+	var builder strings.Builder
+	builder.WriteString(strings.TrimRight(response, "\n"))
+	builder.WriteString("\n\n")
+	builder.WriteString(ResolvedSectionHeading)
+	builder.WriteString("\n\n")
+	for _, r := range c.Resolved {
+		builder.WriteString(fmt.Sprintf("- [%s] %s%s\n", r.Category, firstLine(r.Message), fmt.Sprintf(IDTagFormat, r.Fingerprint[:IDLength])))
+	}
+	return builder.String()
+}
+
+// addBadge appends a badge after the ID tag of a finding
+func addBadge(response, id, badge string) string {
+	tag := fmt.Sprintf(IDTagFormat, id)
+	return strings.ReplaceAll(response, tag, tag+" "+badge)
+}
+
+// firstLine returns the first line of a multi-line message
+func firstLine(message string) string {
+	line, _, _ := strings.Cut(message, "\n")
+	return line
+}
+
+// shortSHA abbreviates a commit hash for display
+func shortSHA(sha string) string {
+	if len(sha) > ShortSHALength {
+		return sha[:ShortSHALength]
+	}
+	return lo.Ternary(sha != "", sha, "unknown")
+}
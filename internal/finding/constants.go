@@ -0,0 +1,96 @@
+package finding
+
+// Severities, taken from the review response headings
+const (
+	SeverityCritical    = "critical"
+	SeverityWarning     = "warning"
+	SeverityRefactoring = "refactoring"
+	SeverityInfo        = "info"
+)
+
+// DefaultCategory is used when a finding has no [category] prefix
+const DefaultCategory = "general"
+
+// IDLength is the number of fingerprint hex characters shown as the finding ID
+const IDLength = 10
+
+// SuppressionsFileName is the suppressions file, both in ~/.config/revcli and in <repo>/.revcli
+const SuppressionsFileName = "suppressions.yaml"
+
+// RepoDirName is the per-repository directory meant to be committed
+const RepoDirName = ".revcli"
+
+// SuppressedSectionTitle is the prompt section listing suppressed findings
+const SuppressedSectionTitle = "Suppressed Findings (do not report)"
+
+// SuppressedSectionIntro explains the suppressed findings section to the model
+const SuppressedSectionIntro = "The user marked these findings as false positives or accepted risks. Do not report them again, even in different words:\n\n"
+
+// IDTagFormat is appended to every visible finding so it can be suppressed by ID
+const IDTagFormat = " `#%s`"
+
+// SuppressedNoteFormat is appended to a response that had suppressed findings removed
+const SuppressedNoteFormat = "> 🔇 %d suppressed finding(s) hidden. See `revcli suppress list`."
+
+// Comparison badges shown after a finding's ID tag
+const (
+	BadgeNew        = "🆕 new"
+	BadgePersisting = "↻ persisting"
+)
+
+// ResolvedSectionHeading is appended to a response listing findings fixed since the previous run
+const ResolvedSectionHeading = "### ✅ Resolved Since Last Run"
+
+// ComparisonSummaryFormat summarizes a comparison (args: previous HEAD, new, persisting, resolved)
+const ComparisonSummaryFormat = "Since last run (%s): %d new, %d persisting, %d resolved"
+
+// ShortSHALength is the number of commit hash characters shown
+const ShortSHALength = 7
+
+// Self-verification pass
+const (
+	// VerifyConcurrency is the number of verifier calls in flight
+	VerifyConcurrency = 4
+	// VerifyContextLines is the number of lines shown above and below the finding's line
+	VerifyContextLines = 8
+)
+
+// Verification tags appended after a finding's ID tag
+const (
+	ConfidenceTagFormat = " _(confidence %.2f)_"
+	DowngradedTagFormat = " _(downgraded to %s, confidence %.2f)_"
+)
+
+// DroppedNoteFormat is appended to a response that had findings dropped by the verifier
+const DroppedNoteFormat = "> 🔍 Self-verification dropped %d finding(s) as unsupported by the code."
+
+// VerificationSummaryFormat summarizes the self-verification pass (args: confirmed, downgraded, dropped)
+const VerificationSummaryFormat = "Verification: %d confirmed, %d downgraded, %d dropped"
+
+// VerifierSystemPrompt instructs the verifier model
+const VerifierSystemPrompt = `You verify findings from an automated code review. You see one finding and the exact code it points at, nothing else.
+Judge the code by the rules and idioms of its language, which is given with the code.
+Decide whether the code supports the finding:
+- confirm: the issue is real at this location
+- downgrade: the issue is real but less severe than claimed
+- drop: the code does not show the issue, the finding misreads the code, or it is speculation
+
+Answer with a single JSON object and nothing else:
+{"verdict": "confirm|downgrade|drop", "severity": "critical|warning|refactoring|info", "confidence": 0.0-1.0, "reason": "one sentence"}`
+
+// UnknownLanguage names the language of a file whose language isn't detected
+const UnknownLanguage = "unknown language"
+
+// VerifierPromptTemplate is the per-finding verifier prompt (args: severity, category, message, path, language, region)
+const VerifierPromptTemplate = `## Finding
+
+Severity: %s
+Category: %s
+
+%s
+
+## Code (%s, %s; the referenced line is marked with >)
+
+` + "```" + `
+%s` + "```" + `
+`
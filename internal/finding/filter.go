@@ -0,0 +1,101 @@
+package finding
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/prompt"
+)
+
+// Process parses a response, removes suppressed findings and tags the rest with their ID
+func Process(response string, fileContents map[string]string, suppressions []Suppression, branch string) (string, []*Finding, []*Finding) {
+	visible, suppressed := Filter(Parse(response, fileContents), suppressions, branch)
+	return Rewrite(response, visible, suppressed, nil), visible, suppressed
+}
+
+// Filter splits findings into visible and suppressed ones for the given branch
+func Filter(findings []*Finding, suppressions []Suppression, branch string) (visible, suppressed []*Finding) {
+	return lo.FilterReject(findings, func(f *Finding, _ int) bool {
+		return !lo.ContainsBy(suppressions, func(s Suppression) bool { return s.Matches(f, branch) })
+	})
+}
+
+// Rewrite tags visible findings with their ID and removes suppressed and dropped ones from a response
+// - dropped (may be nil) are findings rejected by the self-verification pass
+// - the findings must come from Parse on the same response
+func Rewrite(response string, visible, suppressed, dropped []*Finding) string {
+	if len(visible) == 0 && len(suppressed) == 0 && len(dropped) == 0 {
+		return response
+	}
+
+	lines := strings.Split(response, "\n")
+	for _, f := range visible {
+		lines[f.start] += fmt.Sprintf(IDTagFormat, f.ID) + verificationTag(f)
+	}
+
+	rewritten := strings.Join(removeFindings(lines, slices.Concat(suppressed, dropped)), "\n")
+	notes := make([]string, 0, 2)
+	if len(suppressed) > 0 {
+		notes = append(notes, fmt.Sprintf(SuppressedNoteFormat, len(suppressed)))
+	}
+	if len(dropped) > 0 {
+		notes = append(notes, fmt.Sprintf(DroppedNoteFormat, len(dropped)))
+	}
+	if len(notes) > 0 {
+		rewritten = strings.TrimRight(rewritten, "\n") + "\n\n" + strings.Join(notes, "\n>\n") + "\n"
+	}
+	return rewritten
+}
+
+// Dedupe removes findings already raised in an earlier response from later ones
+// Used to merge chunked reviews; returns the responses and the number of duplicates removed
+func Dedupe(responses []string, fileContents map[string]string) ([]string, int) {
+	seen := make(map[string]bool)
+	deduped := make([]string, len(responses))
+	duplicates := 0
+	for i, response := range responses {
+		repeated := lo.Filter(Parse(response, fileContents), func(f *Finding, _ int) bool {
+			if seen[f.Fingerprint] {
+				return true
+			}
+			seen[f.Fingerprint] = true
+			return false
+		})
+		duplicates += len(repeated)
+		deduped[i] = strings.Join(removeFindings(strings.Split(response, "\n"), repeated), "\n")
+	}
+	return deduped, duplicates
+}
+
+// removeFindings removes the response lines of findings
+func removeFindings(lines []string, findings []*Finding) []string {
+	hidden := make(map[int]bool)
+	for _, f := range findings {
+		for i := f.start; i <= f.end; i++ {
+			hidden[i] = true
+		}
+	}
+	return lo.Reject(lines, func(_ string, i int) bool { return hidden[i] })
+}
+
+// PromptSections lists suppressed findings so the model stops raising them
+// Returns nil when there are no suppressions
+func PromptSections(suppressions []Suppression) []prompt.Section {
+	if len(suppressions) == 0 {
+		return nil
+	}
+
+	var builder strings.Builder
+	builder.WriteString(SuppressedSectionIntro)
+	for _, s := range suppressions {
+		location := lo.Ternary(s.Path != "", " `"+s.Path+"`", "")
+		builder.WriteString(fmt.Sprintf("- [%s]%s: %s\n", s.Category, location, s.Message))
+		if s.Snippet != "" && s.Snippet != normalizeMessage(s.Message) {
+			builder.WriteString(fmt.Sprintf("  - code: `%s`\n", s.Snippet))
+		}
+	}
+	return []prompt.Section{{Title: SuppressedSectionTitle, Body: builder.String()}}
+}
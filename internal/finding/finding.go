@@ -0,0 +1,184 @@
+// Package finding fingerprints review findings so known false positives can be suppressed
+package finding
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Finding is one issue raised in a review response
+type Finding struct {
+	// ID is the short fingerprint shown to the user (see Fingerprint)
+	ID string
+	// Fingerprint identifies the finding across runs (category + normalized snippet + path)
+	Fingerprint string
+	Severity    string
+	Category    string
+	// Path is the first file referenced by the finding ("" when it references none)
+	Path string
+	// Line is the referenced line (0 when unknown); not part of the fingerprint
+	Line int
+	// Message is the finding text without its [category] prefix
+	Message string
+	// Snippet is the normalized source line at Path:Line, or the normalized message
+	Snippet string
+	// Verification is set by the self-verification pass (nil when not verified)
+	Verification *Verification
+
+	// start and end are the response line range of the bullet, inclusive
+	start int
+	end   int
+}
+
+var (
+	// fileRefRe matches path/to/file.go:42 references
+	fileRefRe = regexp.MustCompile(`([\w./-]+\.[A-Za-z0-9]+):(\d+)`)
+	// categoryRe matches a leading [category] tag, optionally in bold or code
+	categoryRe = regexp.MustCompile("^[*`]*\\[([A-Za-z][\\w-]*)\\][*`]*\\s*")
+	// bulletRe matches a top-level list item
+	bulletRe = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+`)
+)
+
+// Parse extracts findings from the severity sections of a review response
+// - a finding is a top-level bullet (plus its indented continuation lines)
+// - fileContents (repo-relative path -> content) provides the snippet for the fingerprint; may be nil
+func Parse(response string, fileContents map[string]string) []*Finding {
+	lines := strings.Split(response, "\n")
+
+	var findings []*Finding
+	var current *Finding
+	severity := ""
+	inFence := false
+	// fenceInBullet is true while inside a code block indented under the current bullet
+	fenceInBullet := false
+
+	flush := func() {
+		if current != nil {
+			findings = append(findings, complete(current, lines, fileContents))
+			current = nil
+		}
+	}
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		indented := strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+
+		if strings.HasPrefix(trimmed, "```") {
+			if !inFence {
+				fenceInBullet = current != nil && indented
+				if !fenceInBullet {
+					flush()
+				}
+			}
+			inFence = !inFence
+			if fenceInBullet {
+				current.end = i
+			}
+			continue
+		}
+		if inFence {
+			if fenceInBullet {
+				current.end = i
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			flush()
+			severity = severityOf(trimmed)
+		case trimmed == "":
+			// Blank lines may separate a bullet from its indented continuation
+		case !indented && bulletRe.MatchString(line):
+			flush()
+			if severity != "" {
+				current = &Finding{Severity: severity, start: i, end: i}
+			}
+		case indented && current != nil:
+			current.end = i
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return findings
+}
+
+// complete fills in the message, reference and fingerprint of a bullet
+func complete(f *Finding, lines []string, fileContents map[string]string) *Finding {
+	text := bulletRe.ReplaceAllString(strings.TrimSpace(lines[f.start]), "")
+	for _, line := range lines[f.start+1 : f.end+1] {
+		text += "\n" + strings.TrimSpace(line)
+	}
+
+	f.Category = DefaultCategory
+	if match := categoryRe.FindStringSubmatch(text); match != nil {
+		f.Category = strings.ToLower(match[1])
+		text = text[len(match[0]):]
+	}
+	f.Message = strings.TrimSpace(text)
+
+	if match := fileRefRe.FindStringSubmatch(f.Message); match != nil {
+		f.Path = strings.TrimPrefix(match[1], "./")
+		f.Line, _ = strconv.Atoi(match[2])
+	}
+
+	f.Snippet = sourceLine(fileContents, f.Path, f.Line)
+	if f.Snippet == "" {
+		f.Snippet = normalizeMessage(f.Message)
+	}
+	f.Fingerprint = Fingerprint(f.Category, f.Snippet, f.Path)
+	f.ID = f.Fingerprint[:IDLength]
+	return f
+}
+
+// Fingerprint hashes the parts of a finding that survive unrelated edits
+// Line numbers are left out so a finding keeps its ID when code above it moves
+func Fingerprint(category, snippet, path string) string {
+	sum := sha256.Sum256([]byte(category + "\x00" + snippet + "\x00" + path))
+	return hex.EncodeToString(sum[:])
+}
+
+// severityOf maps a response heading to a severity, or "" for sections that hold no findings
+func severityOf(heading string) string {
+	lower := strings.ToLower(heading)
+	switch {
+	case strings.Contains(heading, "🔴") || strings.Contains(lower, "critical"):
+		return SeverityCritical
+	case strings.Contains(heading, "🟠") || strings.Contains(lower, "warning"):
+		return SeverityWarning
+	case strings.Contains(heading, "🟡") || strings.Contains(lower, "refactor"):
+		return SeverityRefactoring
+	default:
+		return ""
+	}
+}
+
+// sourceLine returns the whitespace-normalized line of a file, or "" when unavailable
+func sourceLine(fileContents map[string]string, path string, line int) string {
+	content, ok := fileContents[path]
+	if !ok || line < 1 {
+		return ""
+	}
+	lines := strings.Split(content, "\n")
+	if line > len(lines) {
+		return ""
+	}
+	return collapseSpaces(lines[line-1])
+}
+
+// normalizeMessage strips references and markdown so rephrased line numbers don't change the fingerprint
+func normalizeMessage(message string) string {
+	message = fileRefRe.ReplaceAllString(message, "$1")
+	message = strings.NewReplacer("*", "", "`", "").Replace(message)
+	return strings.ToLower(collapseSpaces(message))
+}
+
+// collapseSpaces trims a string and collapses inner whitespace runs to one space
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,275 @@
+package finding
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const source = "package main\n\nfunc main() {\n\tdata, _ := os.ReadFile(path)\n}\n"
+
+var response = strings.Join([]string{
+	"### 🔴 Critical (Must Fix)",
+	"- [error-wrapping] **main.go:4** ignores the error from os.ReadFile.",
+	"  Wrap and return it.",
+	"",
+	"### 🟠 Warnings",
+	"* Global state in config.go:10 makes tests order-dependent.",
+	"",
+	"### 💡 Code Suggestions",
+	"```diff category=error-wrapping",
+	"- not a finding",
+	"```",
+	"- suggestion bullets are not findings either",
+}, "\n")
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	findings := Parse(response, map[string]string{"main.go": source})
+	require.Len(t, findings, 2)
+
+	first := findings[0]
+	require.Equal(t, SeverityCritical, first.Severity)
+	require.Equal(t, "error-wrapping", first.Category)
+	require.Equal(t, "main.go", first.Path)
+	require.Equal(t, 4, first.Line)
+	require.Equal(t, "data, _ := os.ReadFile(path)", first.Snippet)
+	require.Equal(t, "**main.go:4** ignores the error from os.ReadFile.\nWrap and return it.", first.Message)
+	require.Len(t, first.ID, IDLength)
+
+	second := findings[1]
+	require.Equal(t, SeverityWarning, second.Severity)
+	require.Equal(t, DefaultCategory, second.Category)
+	require.Equal(t, "config.go", second.Path)
+	require.Equal(t, "global state in config.go makes tests order-dependent.", second.Snippet)
+}
+
+func TestFingerprintSurvivesLineShift(t *testing.T) {
+	t.Parallel()
+
+	shifted := "package main\n\nimport \"os\"\n\n" + strings.TrimPrefix(source, "package main\n\n")
+	moved := strings.Replace(response, "main.go:4", "main.go:6", 1)
+
+	before := Parse(response, map[string]string{"main.go": source})[0]
+	after := Parse(moved, map[string]string{"main.go": shifted})[0]
+	require.Equal(t, before.ID, after.ID)
+	require.NotEqual(t, before.ID, Fingerprint("logic", before.Snippet, before.Path)[:IDLength])
+}
+
+func TestProcess(t *testing.T) {
+	t.Parallel()
+
+	findings := Parse(response, map[string]string{"main.go": source})
+	suppressions := []Suppression{
+		{ID: findings[0].ID, Fingerprint: findings[0].Fingerprint, Scope: ScopeBranch, Branch: "feature"},
+	}
+
+	// Branch suppressions only apply on their branch
+	rewritten, visible, suppressed := Process(response, map[string]string{"main.go": source}, suppressions, "main")
+	require.Len(t, visible, 2)
+	require.Empty(t, suppressed)
+	require.Contains(t, rewritten, "Wrap and return it.")
+	require.Contains(t, rewritten, "order-dependent. `#"+visible[1].ID+"`")
+
+	rewritten, visible, suppressed = Process(response, map[string]string{"main.go": source}, suppressions, "feature")
+	require.Len(t, visible, 1)
+	require.Len(t, suppressed, 1)
+	require.NotContains(t, rewritten, "os.ReadFile")
+	require.NotContains(t, rewritten, "Wrap and return it.")
+	require.Contains(t, rewritten, "```diff category=error-wrapping")
+	require.True(t, strings.HasSuffix(rewritten, "> 🔇 1 suppressed finding(s) hidden. See `revcli suppress list`.\n"))
+}
+
+func TestDedupe(t *testing.T) {
+	t.Parallel()
+
+	other := "### 🟠 Warnings\n- [logic] Off-by-one in loop.go:3.\n"
+	deduped, duplicates := Dedupe([]string{response, response + "\n" + other}, map[string]string{"main.go": source})
+	require.Equal(t, 2, duplicates)
+	require.Equal(t, response, deduped[0])
+	require.NotContains(t, deduped[1], "os.ReadFile")
+	require.NotContains(t, deduped[1], "order-dependent")
+	require.Contains(t, deduped[1], "Off-by-one in loop.go:3.")
+}
+
+func TestPromptSections(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, PromptSections(nil))
+
+	sections := PromptSections([]Suppression{
+		{Category: "lint", Path: "main.go", Snippet: "x := 1", Message: "unused variable"},
+	})
+	require.Len(t, sections, 1)
+	require.Equal(t, SuppressedSectionTitle, sections[0].Title)
+	require.Contains(t, sections[0].Body, "- [lint] `main.go`: unused variable\n  - code: `x := 1`\n")
+}
+
+func TestSuppressionFiles(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	rootDir := t.TempDir()
+	gitIgnorePath := filepath.Join(rootDir, RepoDirName, ".gitignore")
+	require.NoError(t, os.MkdirAll(filepath.Dir(gitIgnorePath), 0o755))
+	require.NoError(t, os.WriteFile(gitIgnorePath, []byte("*\n"), 0o644))
+
+	global := Suppression{ID: "aaaaaaaaaa", Fingerprint: "aaaaaaaaaa00", Scope: ScopeGlobal, Category: "lint", CreatedAt: time.Unix(0, 0).UTC()}
+	branch := Suppression{ID: "bbbbbbbbbb", Fingerprint: "bbbbbbbbbb00", Scope: ScopeBranch, Branch: "feature", Category: "style", CreatedAt: time.Unix(0, 0).UTC()}
+	require.NoError(t, AddSuppression(rootDir, global))
+	require.NoError(t, AddSuppression(rootDir, branch))
+	// Re-suppressing in the same scope replaces the entry
+	require.NoError(t, AddSuppression(rootDir, branch))
+
+	all, err := ListSuppressions(rootDir)
+	require.NoError(t, err)
+	require.Equal(t, []Suppression{global, branch}, all)
+	require.FileExists(t, RepoSuppressionsPath(rootDir))
+	gitIgnore, err := os.ReadFile(gitIgnorePath)
+	require.NoError(t, err)
+	require.Equal(t, "*\n!suppressions.yaml\n", string(gitIgnore))
+
+	active, err := LoadSuppressions(rootDir, "main")
+	require.NoError(t, err)
+	require.Equal(t, []Suppression{global}, active)
+
+	removed, err := RemoveSuppression(rootDir, "bbbbbbbbbb")
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+
+	active, err = LoadSuppressions(rootDir, "feature")
+	require.NoError(t, err)
+	require.Equal(t, []Suppression{global}, active)
+}
+
+func TestParseScope(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		want    Scope
+		wantErr bool
+	}{
+		{"global", ScopeGlobal, false},
+		{"Repo", ScopeRepo, false},
+		{"branch", ScopeBranch, false},
+		{"team", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseScope(tt.name)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	fileContents := map[string]string{"main.go": source}
+	rewritten, current, _ := Process(response, fileContents, nil, "main")
+	persisting := current[0]
+
+	previous := []Record{
+		{Fingerprint: persisting.Fingerprint, Category: persisting.Category, Message: persisting.Message},
+		{Fingerprint: strings.Repeat("a", 64), Category: "logic", Message: "off-by-one in loop\nmore detail"},
+		{Fingerprint: strings.Repeat("b", 64), Category: "lint", Message: "suppressed since"},
+	}
+	suppressions := []Suppression{{Fingerprint: strings.Repeat("b", 64), Scope: ScopeRepo}}
+
+	comparison := Compare(Run{HeadSHA: "0123456789abcdef"}, previous, current, suppressions, "main")
+	require.Equal(t, []*Finding{current[1]}, comparison.New)
+	require.Equal(t, []*Finding{persisting}, comparison.Persisting)
+	require.Len(t, comparison.Resolved, 1)
+	require.Equal(t, "Since last run (0123456): 1 new, 1 persisting, 1 resolved", comparison.Summary())
+
+	badged := Badge(rewritten, comparison)
+	require.Contains(t, badged, "`#"+persisting.ID+"` "+BadgePersisting)
+	require.Contains(t, badged, "`#"+current[1].ID+"` "+BadgeNew)
+	require.True(t, strings.HasSuffix(badged, ResolvedSectionHeading+"\n\n- [logic] off-by-one in loop `#aaaaaaaaaa`\n"))
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	fileContents := map[string]string{"main.go": source, "config.go": strings.Repeat("var x = 1\n", 12)}
+	findings := Parse(response, fileContents)
+
+	generate := func(_ context.Context, systemPrompt, prompt string) (string, error) {
+		require.Equal(t, VerifierSystemPrompt, systemPrompt)
+		if strings.Contains(prompt, "os.ReadFile") {
+			require.Contains(t, prompt, "## Code (main.go, go; the referenced line is marked with >)")
+			require.Contains(t, prompt, ">    4 | \tdata, _ := os.ReadFile(path)")
+			return "```json\n{\"verdict\": \"downgrade\", \"severity\": \"critical\", \"confidence\": 1.4}\n```", nil
+		}
+		return `{"verdict": "drop", "confidence": 0.9, "reason": "no global state here"}`, nil
+	}
+
+	kept, dropped, err := Verify(t.Context(), findings, fileContents, generate)
+	require.NoError(t, err)
+	require.Equal(t, []*Finding{findings[0]}, kept)
+	require.Equal(t, []*Finding{findings[1]}, dropped)
+	// A downgrade to an equal or higher severity moves one level down
+	require.Equal(t, SeverityWarning, kept[0].Severity)
+	require.InDelta(t, 1.0, kept[0].Verification.Confidence, 0)
+	require.Equal(t, "Verification: 0 confirmed, 1 downgraded, 1 dropped", VerificationSummary(kept, dropped))
+
+	rewritten := Rewrite(response, kept, nil, dropped)
+	require.Contains(t, rewritten, "`#"+kept[0].ID+"` _(downgraded to warning, confidence 1.00)_")
+	require.NotContains(t, rewritten, "order-dependent")
+	require.Contains(t, rewritten, "> 🔍 Self-verification dropped 1 finding(s)")
+}
+
+func TestVerifyKeepsUncheckedFindings(t *testing.T) {
+	t.Parallel()
+
+	findings := Parse(response, map[string]string{"main.go": source})
+	generate := func(context.Context, string, string) (string, error) { return "I think it's fine", nil }
+
+	kept, dropped, err := Verify(t.Context(), findings, map[string]string{"main.go": source}, generate)
+	require.ErrorContains(t, err, "no JSON object")
+	require.Len(t, kept, 2)
+	require.Empty(t, dropped)
+	require.Nil(t, kept[0].Verification)
+}
+
+func TestParseReply(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		reply   string
+		want    Verdict
+		wantErr bool
+	}{
+		{"plain", `{"verdict":"confirm","confidence":0.8}`, VerdictConfirm, false},
+		{"fenced with text", "Sure:\n```json\n{\"verdict\":\"Drop\"}\n```", VerdictDrop, false},
+		{"unknown verdict", `{"verdict":"maybe"}`, "", true},
+		{"no json", "confirm", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseReply(tt.reply)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, Verdict(got.Verdict))
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package finding
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/trankhanh040147/revcli/internal/db"
+)
+
+// Run identifies the review a set of findings came from
+type Run struct {
+	SessionID string
+	// Repo is the absolute repository root
+	Repo    string
+	Branch  string
+	HeadSHA string
+}
+
+// Record is a finding stored in the database
+type Record struct {
+	ID          string
+	SessionID   string
+	Fingerprint string
+	Repo        string
+	Branch      string
+	HeadSHA     string
+	Severity    string
+	Category    string
+	Path        string
+	Line        int64
+	Snippet     string
+	Message     string
+	CreatedAt   int64
+}
+
+// Service persists findings so they can be looked up by ID later
+type Service interface {
+	// SaveRun stores a review run and its findings
+	SaveRun(ctx context.Context, run Run, findings []*Finding) error
+	// PreviousRun returns the latest other run on the same repository and branch, with its findings
+	// Returns ErrNoPreviousRun when this is the first run
+	PreviousRun(ctx context.Context, run Run) (Run, []Record, error)
+	// GetByID returns the latest finding of a repository whose fingerprint starts with id
+	GetByID(ctx context.Context, repo, id string) (Record, error)
+	// ListBySession returns the findings stored for a session
+	ListBySession(ctx context.Context, sessionID string) ([]Record, error)
+}
+
+// ErrNoPreviousRun is returned by PreviousRun for the first run on a branch
+var ErrNoPreviousRun = errors.New("no previous review run on this branch")
+
+// idRe matches a finding ID; it is used as a LIKE prefix, so wildcards must not get through
+var idRe = regexp.MustCompile(`^[0-9a-f]{4,64}$`)
+
+type service struct {
+	db *sql.DB
+	q  *db.Queries
+}
+
+// NewService creates a findings service backed by the database
+func NewService(q *db.Queries, db *sql.DB) Service {
+	return &service{q: q, db: db}
+}
+
+// SaveRun stores the run and its findings in one transaction, so a run is never stored with part of its findings
+func (s *service) SaveRun(ctx context.Context, run Run, findings []*Finding) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := s.q.WithTx(tx)
+
+	_, err = qtx.CreateReviewRun(ctx, db.CreateReviewRunParams{
+		SessionID: run.SessionID,
+		Repo:      run.Repo,
+		Branch:    run.Branch,
+		HeadSha:   run.HeadSHA,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save review run: %w", err)
+	}
+
+	for _, f := range findings {
+		_, err := qtx.CreateFinding(ctx, db.CreateFindingParams{
+			ID:          uuid.New().String(),
+			SessionID:   run.SessionID,
+			Fingerprint: f.Fingerprint,
+			Repo:        run.Repo,
+			Branch:      run.Branch,
+			HeadSha:     run.HeadSHA,
+			Severity:    f.Severity,
+			Category:    f.Category,
+			Path:        f.Path,
+			Line:        int64(f.Line),
+			Snippet:     f.Snippet,
+			Message:     f.Message,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save finding %s: %w", f.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (s *service) PreviousRun(ctx context.Context, run Run) (Run, []Record, error) {
+	dbRun, err := s.q.GetPreviousReviewRun(ctx, db.GetPreviousReviewRunParams{
+		Repo:      run.Repo,
+		Branch:    run.Branch,
+		SessionID: run.SessionID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Run{}, nil, ErrNoPreviousRun
+	}
+	if err != nil {
+		return Run{}, nil, fmt.Errorf("failed to load previous review run: %w", err)
+	}
+
+	records, err := s.ListBySession(ctx, dbRun.SessionID)
+	if err != nil {
+		return Run{}, nil, fmt.Errorf("failed to load previous findings: %w", err)
+	}
+	previous := Run{SessionID: dbRun.SessionID, Repo: dbRun.Repo, Branch: dbRun.Branch, HeadSHA: dbRun.HeadSha}
+	return previous, records, nil
+}
+
+func (s *service) GetByID(ctx context.Context, repo, id string) (Record, error) {
+	id = strings.TrimPrefix(strings.ToLower(id), "#")
+	if !idRe.MatchString(id) {
+		return Record{}, fmt.Errorf("invalid finding ID %q", id)
+	}
+	dbFinding, err := s.q.GetLatestFindingByFingerprint(ctx, db.GetLatestFindingByFingerprintParams{
+		Repo:        repo,
+		Fingerprint: id + "%",
+	})
+	if err != nil {
+		return Record{}, err
+	}
+	return fromDBItem(dbFinding), nil
+}
+
+func (s *service) ListBySession(ctx context.Context, sessionID string) ([]Record, error) {
+	dbFindings, err := s.q.ListFindingsBySession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]Record, len(dbFindings))
+	for i, dbFinding := range dbFindings {
+		records[i] = fromDBItem(dbFinding)
+	}
+	return records, nil
+}
+
+func fromDBItem(item db.Finding) Record {
+	return Record{
+		ID:          item.ID,
+		SessionID:   item.SessionID,
+		Fingerprint: item.Fingerprint,
+		Repo:        item.Repo,
+		Branch:      item.Branch,
+		HeadSHA:     item.HeadSha,
+		Severity:    item.Severity,
+		Category:    item.Category,
+		Path:        item.Path,
+		Line:        item.Line,
+		Snippet:     item.Snippet,
+		Message:     item.Message,
+		CreatedAt:   item.CreatedAt,
+	}
+}
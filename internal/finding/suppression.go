@@ -0,0 +1,206 @@
+package finding
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+
+	"github.com/trankhanh040147/revcli/internal/config"
+	"github.com/trankhanh040147/revcli/internal/fsext"
+)
+
+// Scope controls where a suppression applies
+type Scope string
+
+const (
+	// ScopeGlobal applies in every repository (~/.config/revcli/suppressions.yaml)
+	ScopeGlobal Scope = "global"
+	// ScopeRepo applies to the whole repository (.revcli/suppressions.yaml)
+	ScopeRepo Scope = "repo"
+	// ScopeBranch applies to one branch of the repository (.revcli/suppressions.yaml)
+	ScopeBranch Scope = "branch"
+)
+
+// ParseScope validates a scope name
+func ParseScope(name string) (Scope, error) {
+	scope := Scope(strings.ToLower(name))
+	switch scope {
+	case ScopeGlobal, ScopeRepo, ScopeBranch:
+		return scope, nil
+	default:
+		return "", fmt.Errorf("invalid scope %q: must be global, repo or branch", name)
+	}
+}
+
+// Suppression hides a finding from review output and tells the model not to raise it
+type Suppression struct {
+	ID          string    `yaml:"id"`
+	Fingerprint string    `yaml:"fingerprint"`
+	Scope       Scope     `yaml:"scope"`
+	Branch      string    `yaml:"branch,omitempty"`
+	Category    string    `yaml:"category"`
+	Path        string    `yaml:"path,omitempty"`
+	Snippet     string    `yaml:"snippet"`
+	Message     string    `yaml:"message"`
+	Reason      string    `yaml:"reason,omitempty"`
+	CreatedAt   time.Time `yaml:"created_at"`
+}
+
+// suppressionFile is the on-disk layout of suppressions.yaml
+type suppressionFile struct {
+	Suppressions []Suppression `yaml:"suppressions"`
+}
+
+// Matches reports whether the suppression hides a finding on the given branch
+func (s Suppression) Matches(f *Finding, branch string) bool {
+	return s.matchesFingerprint(f.Fingerprint, branch)
+}
+
+// matchesFingerprint reports whether the suppression hides a fingerprint on the given branch
+func (s Suppression) matchesFingerprint(fingerprint, branch string) bool {
+	return s.Fingerprint == fingerprint && s.appliesTo(branch)
+}
+
+// appliesTo reports whether the suppression is active on the given branch
+func (s Suppression) appliesTo(branch string) bool {
+	return s.Scope != ScopeBranch || s.Branch == branch
+}
+
+// GlobalSuppressionsPath returns ~/.config/revcli/suppressions.yaml
+func GlobalSuppressionsPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, config.ConfigDirName, config.AppDirName, SuppressionsFileName), nil
+}
+
+// RepoSuppressionsPath returns <rootDir>/.revcli/suppressions.yaml
+func RepoSuppressionsPath(rootDir string) string {
+	return filepath.Join(rootDir, RepoDirName, SuppressionsFileName)
+}
+
+// LoadSuppressions returns the suppressions active on a branch of the repository at rootDir
+func LoadSuppressions(rootDir, branch string) ([]Suppression, error) {
+	all, err := ListSuppressions(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Filter(all, func(s Suppression, _ int) bool { return s.appliesTo(branch) }), nil
+}
+
+// ListSuppressions returns the global and repository suppressions, global first
+func ListSuppressions(rootDir string) ([]Suppression, error) {
+	globalPath, err := GlobalSuppressionsPath()
+	if err != nil {
+		return nil, err
+	}
+	global, err := readSuppressions(globalPath)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := readSuppressions(RepoSuppressionsPath(rootDir))
+	if err != nil {
+		return nil, err
+	}
+	return append(global, repo...), nil
+}
+
+// AddSuppression stores a suppression in the file for its scope
+// An existing suppression of the same finding in the same scope is replaced
+func AddSuppression(rootDir string, s Suppression) error {
+	path, err := suppressionsPath(rootDir, s.Scope)
+	if err != nil {
+		return err
+	}
+	existing, err := readSuppressions(path)
+	if err != nil {
+		return err
+	}
+
+	kept := lo.Reject(existing, func(e Suppression, _ int) bool {
+		return e.Fingerprint == s.Fingerprint && e.Scope == s.Scope && e.Branch == s.Branch
+	})
+	if err := writeSuppressions(path, append(kept, s)); err != nil {
+		return err
+	}
+	if s.Scope == ScopeGlobal {
+		return nil
+	}
+	// Suppressions are shared with the team, so git tracks them despite the data directory's .gitignore
+	return fsext.Unignore(filepath.Dir(path), SuppressionsFileName)
+}
+
+// RemoveSuppression deletes suppressions by finding ID from both files
+// Returns the number of suppressions removed
+func RemoveSuppression(rootDir, id string) (int, error) {
+	globalPath, err := GlobalSuppressionsPath()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, path := range []string{globalPath, RepoSuppressionsPath(rootDir)} {
+		existing, err := readSuppressions(path)
+		if err != nil {
+			return removed, err
+		}
+		kept := lo.Reject(existing, func(s Suppression, _ int) bool { return s.ID == id })
+		if len(kept) == len(existing) {
+			continue
+		}
+		if err := writeSuppressions(path, kept); err != nil {
+			return removed, err
+		}
+		removed += len(existing) - len(kept)
+	}
+	return removed, nil
+}
+
+// suppressionsPath returns the file that stores suppressions of a scope
+func suppressionsPath(rootDir string, scope Scope) (string, error) {
+	if scope == ScopeGlobal {
+		return GlobalSuppressionsPath()
+	}
+	return RepoSuppressionsPath(rootDir), nil
+}
+
+// readSuppressions reads a suppressions file; a missing file has no suppressions
+func readSuppressions(path string) ([]Suppression, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suppressions: %w", err)
+	}
+
+	var file suppressionFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return file.Suppressions, nil
+}
+
+// writeSuppressions writes a suppressions file, creating its directory
+func writeSuppressions(path string, suppressions []Suppression) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create suppressions directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(suppressionFile{Suppressions: suppressions})
+	if err != nil {
+		return fmt.Errorf("failed to marshal suppressions: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write suppressions: %w", err)
+	}
+	return nil
+}
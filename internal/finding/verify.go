@@ -0,0 +1,174 @@
+package finding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/samber/lo"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/trankhanh040147/revcli/internal/prompt"
+)
+
+// Verdict is the verifier's decision on a finding
+type Verdict string
+
+const (
+	VerdictConfirm   Verdict = "confirm"
+	VerdictDowngrade Verdict = "downgrade"
+	VerdictDrop      Verdict = "drop"
+)
+
+// Verification is the outcome of the self-verification pass for one finding
+type Verification struct {
+	Verdict Verdict
+	// Confidence is the verifier's confidence in the finding, from 0 to 1
+	Confidence float64
+	Reason     string
+}
+
+// GenerateFunc sends a one-shot prompt to the verifier model and returns its text response
+type GenerateFunc func(ctx context.Context, systemPrompt, prompt string) (string, error)
+
+// verifierReply is the JSON object the verifier answers with
+type verifierReply struct {
+	Verdict    string  `json:"verdict"`
+	Severity   string  `json:"severity"`
+	Confidence float64 `json:"confidence"`
+	Reason     string  `json:"reason"`
+}
+
+// severityOrder ranks severities from most to least severe
+var severityOrder = []string{SeverityCritical, SeverityWarning, SeverityRefactoring, SeverityInfo}
+
+// Verify asks the verifier to confirm, downgrade or drop each finding that has a code location
+// - findings are checked concurrently; each call sees only the finding and its code region
+// - findings the verifier couldn't check are kept unchanged and reported in the joined error
+func Verify(ctx context.Context, findings []*Finding, fileContents map[string]string, generate GenerateFunc) (kept, dropped []*Finding, err error) {
+	errs := make([]error, len(findings))
+
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(VerifyConcurrency)
+	for i, f := range findings {
+		region := CodeRegion(fileContents, f.Path, f.Line)
+		if region == "" {
+			continue
+		}
+		language := prompt.DetectLanguage(f.Path, fileContents[f.Path])
+		g.Go(func() error {
+			errs[i] = verifyOne(gCtx, f, language, region, generate)
+			// A failed check keeps the finding; only cancellation stops the pass
+			return gCtx.Err()
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	kept, dropped = lo.FilterReject(findings, func(f *Finding, _ int) bool {
+		return f.Verification == nil || f.Verification.Verdict != VerdictDrop
+	})
+	return kept, dropped, errors.Join(errs...)
+}
+
+// verifyOne runs the verifier on one finding and records its verdict
+func verifyOne(ctx context.Context, f *Finding, language prompt.Language, region string, generate GenerateFunc) error {
+	verifierPrompt := fmt.Sprintf(VerifierPromptTemplate, f.Severity, f.Category, f.Message, f.Path, lo.CoalesceOrEmpty(string(language), UnknownLanguage), region)
+	response, err := generate(ctx, VerifierSystemPrompt, verifierPrompt)
+	if err != nil {
+		return fmt.Errorf("verify #%s: %w", f.ID, err)
+	}
+
+	reply, err := parseReply(response)
+	if err != nil {
+		return fmt.Errorf("verify #%s: %w", f.ID, err)
+	}
+
+	f.Verification = &Verification{
+		Verdict:    Verdict(reply.Verdict),
+		Confidence: min(max(reply.Confidence, 0), 1),
+		Reason:     reply.Reason,
+	}
+	if f.Verification.Verdict == VerdictDowngrade {
+		f.Severity = downgrade(f.Severity, reply.Severity)
+	}
+	return nil
+}
+
+// parseReply extracts the verifier's JSON object, tolerating code fences and surrounding text
+func parseReply(response string) (verifierReply, error) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return verifierReply{}, fmt.Errorf("no JSON object in verifier response")
+	}
+
+	var reply verifierReply
+	if err := sonic.UnmarshalString(response[start:end+1], &reply); err != nil {
+		return verifierReply{}, fmt.Errorf("failed to parse verifier response: %w", err)
+	}
+
+	reply.Verdict = strings.ToLower(strings.TrimSpace(reply.Verdict))
+	switch Verdict(reply.Verdict) {
+	case VerdictConfirm, VerdictDowngrade, VerdictDrop:
+		return reply, nil
+	default:
+		return verifierReply{}, fmt.Errorf("unknown verdict %q", reply.Verdict)
+	}
+}
+
+// downgrade returns the requested lower severity, or the next lower one when the request isn't lower
+func downgrade(current, requested string) string {
+	currentRank := lo.IndexOf(severityOrder, current)
+	requestedRank := lo.IndexOf(severityOrder, strings.ToLower(requested))
+	if requestedRank > currentRank {
+		return severityOrder[requestedRank]
+	}
+	return severityOrder[min(currentRank+1, len(severityOrder)-1)]
+}
+
+// CodeRegion returns the numbered lines around path:line, or "" when the location is unknown
+func CodeRegion(fileContents map[string]string, path string, line int) string {
+	content, ok := fileContents[path]
+	if !ok || line < 1 {
+		return ""
+	}
+	lines := strings.Split(content, "\n")
+	if line > len(lines) {
+		return ""
+	}
+
+	first := max(1, line-VerifyContextLines)
+	last := min(len(lines), line+VerifyContextLines)
+	var builder strings.Builder
+	for n := first; n <= last; n++ {
+		marker := lo.Ternary(n == line, ">", " ")
+		builder.WriteString(fmt.Sprintf("%s%5d | %s\n", marker, n, lines[n-1]))
+	}
+	return builder.String()
+}
+
+// VerificationSummary counts confirmed, downgraded and dropped findings
+func VerificationSummary(kept, dropped []*Finding) string {
+	downgraded := lo.CountBy(kept, func(f *Finding) bool {
+		return f.Verification != nil && f.Verification.Verdict == VerdictDowngrade
+	})
+	confirmed := lo.CountBy(kept, func(f *Finding) bool {
+		return f.Verification != nil && f.Verification.Verdict == VerdictConfirm
+	})
+	return fmt.Sprintf(VerificationSummaryFormat, confirmed, downgraded, len(dropped))
+}
+
+// verificationTag renders a verified finding's confidence (and new severity when downgraded)
+func verificationTag(f *Finding) string {
+	if f.Verification == nil {
+		return ""
+	}
+	if f.Verification.Verdict == VerdictDowngrade {
+		return fmt.Sprintf(DowngradedTagFormat, f.Severity, f.Verification.Confidence)
+	}
+	return fmt.Sprintf(ConfidenceTagFormat, f.Verification.Confidence)
+}
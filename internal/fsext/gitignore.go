@@ -0,0 +1,42 @@
+package fsext
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Unignore adds a "!name" rule for each name to dir's .gitignore, so git tracks those files in a
+// directory whose .gitignore ignores everything else; names already un-ignored are left alone
+// Does nothing when dir has no .gitignore
+func Unignore(dir string, names ...string) error {
+	gitIgnorePath := filepath.Join(dir, ".gitignore")
+	data, err := os.ReadFile(gitIgnorePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", gitIgnorePath, err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	content := strings.TrimRight(string(data), "\n") + "\n"
+	changed := false
+	for _, name := range names {
+		if rule := "!" + name; !slices.Contains(lines, rule) {
+			content += rule + "\n"
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := os.WriteFile(gitIgnorePath, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to update %s: %w", gitIgnorePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ApplyCheck verifies that a patch applies cleanly to the working tree
+// without modifying any files
+func ApplyCheck(patch string) error {
+	rootDir, err := getGitRoot()
+	if err != nil {
+		return err
+	}
+	return ApplyInDir(rootDir, patch, "--check")
+}
+
+// ApplyInDir runs git apply against an arbitrary directory
+// Used to preview patch results in a scratch copy of the affected files
+// --recount tolerates hunk headers with wrong line counts (common in LLM output)
+func ApplyInDir(dir, patch string, extraArgs ...string) error {
+	args := append([]string{"apply", "--recount", "--whitespace=nowarn"}, extraArgs...)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(patch)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("git apply failed: %s", msg)
+	}
+
+	return nil
+}
@@ -146,6 +146,39 @@ func parseFilePaths(diff string) []string {
 	return paths
 }
 
+// FileDiff is the part of a diff that changes one file
+type FileDiff struct {
+	Path string
+	Diff string
+}
+
+// SplitDiff splits a diff into per-file diffs, in diff order
+func SplitDiff(diff string) []FileDiff {
+	var files []FileDiff
+	start := -1
+	path := ""
+	flush := func(end int) {
+		if start >= 0 {
+			files = append(files, FileDiff{Path: path, Diff: diff[start:end]})
+		}
+	}
+
+	for offset := 0; offset < len(diff); {
+		line, _, _ := strings.Cut(diff[offset:], "\n")
+		if strings.HasPrefix(line, "diff --git") {
+			flush(offset)
+			start = offset
+			path = ""
+			if parts := strings.Split(line, " "); len(parts) >= 4 {
+				path = strings.TrimPrefix(parts[3], "b/")
+			}
+		}
+		offset += len(line) + 1
+	}
+	flush(len(diff))
+	return files
+}
+
 // readFileContent reads the full content of a file
 func readFileContent(path string) (string, error) {
 	// Get the git root directory
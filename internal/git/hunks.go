@@ -0,0 +1,35 @@
+package git
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hunkRe matches a hunk header and captures the first line of the new file
+var hunkRe = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// AddedLines returns the line numbers, in the new file, of the lines a file diff adds, in order
+func AddedLines(fileDiff string) []int {
+	var lines []int
+	line := 0
+	inHunk := false
+	for _, text := range strings.Split(fileDiff, "\n") {
+		if match := hunkRe.FindStringSubmatch(text); match != nil {
+			line, _ = strconv.Atoi(match[1])
+			inHunk = true
+			continue
+		}
+		if !inHunk || text == "" {
+			continue
+		}
+		switch text[0] {
+		case '+':
+			lines = append(lines, line)
+			line++
+		case ' ':
+			line++
+		}
+	}
+	return lines
+}
@@ -0,0 +1,48 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CurrentBranch returns the checked-out branch name ("HEAD" when detached)
+func CurrentBranch() (string, error) {
+	return revParse("--abbrev-ref", "HEAD")
+}
+
+// HeadSHA returns the commit hash of HEAD
+func HeadSHA() (string, error) {
+	return revParse("HEAD")
+}
+
+// revParse runs git rev-parse and returns its trimmed output
+func revParse(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"rev-parse"}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git rev-parse %s failed: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// CommitMessages returns the messages of the commits on HEAD since it branched from base, oldest first
+func CommitMessages(base string) ([]string, error) {
+	out, err := runGit("log", "--reverse", "--format=%B%x00", base+"..HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []string
+	for _, message := range strings.Split(out, "\x00") {
+		if message = strings.TrimSpace(message); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
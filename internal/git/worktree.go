@@ -0,0 +1,71 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// AddWorktree checks out rev in a temporary detached worktree
+// Returns the worktree directory and a function that removes it
+func AddWorktree(rev string) (string, func(), error) {
+	tempDir, err := os.MkdirTemp("", "revcli-worktree-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+	dir := filepath.Join(tempDir, "tree")
+
+	if _, err := runGit("worktree", "add", "--detach", "--quiet", dir, rev); err != nil {
+		os.RemoveAll(tempDir)
+		return "", nil, err
+	}
+
+	remove := func() {
+		// Unregister the worktree before deleting it so the repository doesn't keep a stale entry
+		_, _ = runGit("worktree", "remove", "--force", dir)
+		os.RemoveAll(tempDir)
+	}
+	return dir, remove, nil
+}
+
+// ExportIndex writes the files staged in the index to a temporary directory
+// Returns the directory and a function that removes it
+func ExportIndex() (string, func(), error) {
+	tempDir, err := os.MkdirTemp("", "revcli-index-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create index directory: %w", err)
+	}
+	remove := func() { os.RemoveAll(tempDir) }
+
+	if _, err := runGit("checkout-index", "--all", "--prefix="+tempDir+string(filepath.Separator)); err != nil {
+		remove()
+		return "", nil, err
+	}
+	return tempDir, remove, nil
+}
+
+// MergeBase returns the best common ancestor of two revisions
+func MergeBase(a, b string) (string, error) {
+	return runGit("merge-base", a, b)
+}
+
+// runGit runs a git command and returns its trimmed output
+func runGit(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s failed: %s", args[0], msg)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,256 @@
+package goref
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/filter"
+	"github.com/trankhanh040147/revcli/internal/prompt"
+)
+
+// Caller is a use of a changed exported symbol in a file outside the diff
+type Caller struct {
+	// Symbol is the changed symbol (e.g. NewBuilder or Builder.Build)
+	Symbol string
+	// Path and Line locate the use (repo-relative)
+	Path string
+	Line int
+	// Function is the enclosing declaration ("" when the use is in a package-level var)
+	Function string
+	// Code is the trimmed source line of the use
+	Code string
+}
+
+// Callers finds the uses of exported symbols declared in changed code, across the module's unchanged files
+// - a symbol is changed when its declaration overlaps an added or removed line
+// - test files, vendor/testdata/hidden directories and files excluded from the review are not searched
+// - the code of each use has its secrets masked
+// - fileContents (repo-relative path -> content) overrides the changed files on disk
+func Callers(rootDir, diff string, fileContents map[string]string) []Caller {
+	changed := changedLines(diff)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	l := newLoader(rootDir, fileContents)
+	symbols := make(map[types.Object]string)
+	forChangedDecls(l, changed, func(pkg *loadedPackage, decl ast.Decl) {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			addSymbol(symbols, pkg.info.Defs[d.Name])
+		case *ast.GenDecl:
+			// Only the specs of a grouped declaration that were changed
+			for _, spec := range d.Specs {
+				if !overlaps(l.fset, spec, changed[l.relPath(spec.Pos())]) {
+					continue
+				}
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					addSymbol(symbols, pkg.info.Defs[s.Name])
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						addSymbol(symbols, pkg.info.Defs[name])
+					}
+				}
+			}
+		}
+	})
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	var callers []Caller
+	for _, dir := range moduleDirs(rootDir) {
+		pkg := l.loadDir(dir)
+		if pkg == nil {
+			continue
+		}
+		for filePath, file := range pkg.files {
+			if _, ok := changed[filePath]; ok || filter.ShouldIgnore(filePath) {
+				continue
+			}
+			callers = append(callers, fileCallers(l, pkg, filePath, file, symbols)...)
+		}
+	}
+
+	slices.SortFunc(callers, func(a, b Caller) int {
+		return cmp.Or(cmp.Compare(a.Symbol, b.Symbol), cmp.Compare(a.Path, b.Path), cmp.Compare(a.Line, b.Line))
+	})
+	return callers
+}
+
+// CallerSections renders the callers grouped by symbol, listing at most MaxCallersPerSymbol call sites each
+// Returns nil when there are no callers
+func CallerSections(callers []Caller) []prompt.Section {
+	if len(callers) == 0 {
+		return nil
+	}
+
+	bySymbol := lo.GroupBy(callers, func(c Caller) string { return c.Symbol })
+	var builder strings.Builder
+	builder.WriteString(CallersIntro)
+	for _, symbol := range slices.Sorted(maps.Keys(bySymbol)) {
+		sites := bySymbol[symbol]
+		builder.WriteString(fmt.Sprintf("- `%s`:\n", symbol))
+		for _, c := range sites[:min(len(sites), MaxCallersPerSymbol)] {
+			location := fmt.Sprintf("`%s:%d`", c.Path, c.Line)
+			if c.Function != "" {
+				location += fmt.Sprintf(" in `%s`", c.Function)
+			}
+			builder.WriteString(fmt.Sprintf("  - %s: `%s`\n", location, c.Code))
+		}
+		if omitted := len(sites) - MaxCallersPerSymbol; omitted > 0 {
+			builder.WriteString(fmt.Sprintf(CallersOmittedFormat, omitted))
+		}
+	}
+	return []prompt.Section{{Title: CallersTitle, Body: builder.String()}}
+}
+
+// RelatedFiles returns the files of the callers, sorted
+func RelatedFiles(callers []Caller) []string {
+	return slices.Sorted(slices.Values(lo.Uniq(lo.Map(callers, func(c Caller, _ int) string { return c.Path }))))
+}
+
+// addSymbol records an exported package-level symbol or method with its display name
+func addSymbol(symbols map[types.Object]string, obj types.Object) {
+	if obj == nil || !obj.Exported() {
+		return
+	}
+	if fn, ok := obj.(*types.Func); ok {
+		if recv := fn.Signature().Recv(); recv != nil {
+			symbols[obj] = receiverName(recv.Type()) + "." + obj.Name()
+			return
+		}
+	}
+	if obj.Parent() != obj.Pkg().Scope() {
+		return
+	}
+	symbols[obj] = obj.Name()
+}
+
+// receiverName returns the type name of a method receiver
+func receiverName(t types.Type) string {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return types.TypeString(t, func(*types.Package) string { return "" })
+}
+
+// fileCallers returns the uses of the symbols in one file
+// Types, variables and constants count only when used from another package;
+// within their own package they change along with the code using them
+func fileCallers(l *loader, pkg *loadedPackage, filePath string, file *ast.File, symbols map[types.Object]string) []Caller {
+	var callers []Caller
+	var lines []string
+	for _, decl := range file.Decls {
+		function := declName(decl)
+		ast.Inspect(decl, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			obj := pkg.info.Uses[ident]
+			if fn, ok := obj.(*types.Func); ok {
+				obj = fn.Origin()
+			}
+			symbol, ok := symbols[obj]
+			if !ok {
+				return true
+			}
+			if _, isFunc := obj.(*types.Func); !isFunc && obj.Pkg() == pkg.types {
+				return true
+			}
+
+			if lines == nil {
+				lines = l.fileLines(filePath)
+			}
+			line := l.fset.Position(ident.Pos()).Line
+			code := ""
+			if line <= len(lines) {
+				code = filter.RedactSecrets(strings.TrimSpace(lines[line-1]))
+			}
+			callers = append(callers, Caller{Symbol: symbol, Path: filePath, Line: line, Function: function, Code: code})
+			return true
+		})
+	}
+	return callers
+}
+
+// declName returns the name of a function declaration ("Type.Method" for methods, "" for other declarations)
+func declName(decl ast.Decl) string {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok {
+		return ""
+	}
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	switch r := recv.(type) {
+	case *ast.IndexExpr:
+		recv = r.X
+	case *ast.IndexListExpr:
+		recv = r.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// moduleDirs returns the repo-relative directories of the module that may hold packages
+// Hidden, vendor and testdata directories, directories excluded from the review (e.g. mocks) and nested modules are skipped
+func moduleDirs(rootDir string) []string {
+	var dirs []string
+	_ = filepath.WalkDir(rootDir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(rootDir, p)
+		if err != nil {
+			return nil
+		}
+		if rel != "." {
+			name := entry.Name()
+			if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "vendor" || name == "testdata" ||
+				filter.ShouldIgnore(filepath.ToSlash(rel)+"/") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+		dirs = append(dirs, filepath.ToSlash(rel))
+		return nil
+	})
+	return dirs
+}
+
+// fileLines returns the lines of a repo-relative file, preferring the overlay
+func (l *loader) fileLines(filePath string) []string {
+	content, ok := l.overlay[filePath]
+	if !ok {
+		data, err := os.ReadFile(filepath.Join(l.rootDir, filePath))
+		if err != nil {
+			return []string{}
+		}
+		content = string(data)
+	}
+	return strings.Split(content, "\n")
+}
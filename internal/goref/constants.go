@@ -0,0 +1,25 @@
+package goref
+
+// MaxDeclarations caps the declarations listed in the prompt section
+const MaxDeclarations = 40
+
+// DeclarationsTitle is the prompt section listing referenced declarations
+const DeclarationsTitle = "Referenced Declarations"
+
+// DeclarationsIntro explains the referenced declarations section to the model
+const DeclarationsIntro = "Definitions from unchanged files that the changed Go code uses. Rely on these instead of guessing fields or signatures:\n\n"
+
+// DeclarationsOmittedFormat notes declarations left out of the section
+const DeclarationsOmittedFormat = "\n(%d more referenced declarations omitted)\n"
+
+// MaxCallersPerSymbol caps the call sites listed for one symbol
+const MaxCallersPerSymbol = 8
+
+// CallersTitle is the prompt section listing the callers of changed exported symbols
+const CallersTitle = "Affected Callers"
+
+// CallersIntro explains the affected callers section to the model
+const CallersIntro = "Code outside the diff that uses exported symbols declared in changed code. Flag changes that break these callers (signature, semantics, error or nil behavior):\n\n"
+
+// CallersOmittedFormat notes call sites of a symbol left out of the section
+const CallersOmittedFormat = "  - (%d more call sites omitted)\n"
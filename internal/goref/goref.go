@@ -0,0 +1,251 @@
+// Package goref finds the declarations that changed Go code references, so the reviewer sees
+// struct fields and signatures that live outside the diff
+package goref
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/filter"
+	"github.com/trankhanh040147/revcli/internal/git"
+	"github.com/trankhanh040147/revcli/internal/prompt"
+)
+
+// Declaration is a declaration from an unchanged file that changed code references
+type Declaration struct {
+	Name string
+	// Path and Line locate the declaration (repo-relative)
+	Path string
+	Line int
+	// Source is the full type definition, or the function signature without its body
+	Source string
+}
+
+// hunkRe matches a hunk header and captures the first line of the new file
+var hunkRe = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// Referenced returns the declarations that the changed Go code in a diff references
+// - changed code is every top-level declaration that overlaps an added or removed line
+// - only declarations of the module's own packages are resolved; those in changed files are
+// left out since the files are already in the prompt
+// - fileContents (repo-relative path -> content) overrides the changed files on disk
+func Referenced(rootDir, diff string, fileContents map[string]string) []Declaration {
+	changed := changedLines(diff)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	c := &collector{
+		loader:  newLoader(rootDir, fileContents),
+		changed: changed,
+		seen:    make(map[types.Object]bool),
+	}
+	forChangedDecls(c.loader, changed, c.visit)
+
+	slices.SortFunc(c.declarations, func(a, b Declaration) int {
+		return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.Line, b.Line))
+	})
+	return c.declarations
+}
+
+// DeclarationSections renders the declarations as a Go code block
+// Returns nil when there are no declarations
+func DeclarationSections(declarations []Declaration) []prompt.Section {
+	if len(declarations) == 0 {
+		return nil
+	}
+
+	var builder strings.Builder
+	builder.WriteString(DeclarationsIntro)
+	builder.WriteString("```go\n")
+	for i, d := range declarations[:min(len(declarations), MaxDeclarations)] {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(fmt.Sprintf("// %s:%d\n%s\n", d.Path, d.Line, d.Source))
+	}
+	builder.WriteString("```\n")
+	if omitted := len(declarations) - MaxDeclarations; omitted > 0 {
+		builder.WriteString(fmt.Sprintf(DeclarationsOmittedFormat, omitted))
+	}
+	return []prompt.Section{{Title: DeclarationsTitle, Body: builder.String()}}
+}
+
+// changedLines maps changed Go files (tests excluded) to their added lines and the lines where lines were removed
+func changedLines(diff string) map[string][]int {
+	changed := make(map[string][]int)
+	for _, fileDiff := range git.SplitDiff(diff) {
+		if !strings.HasSuffix(fileDiff.Path, ".go") || strings.HasSuffix(fileDiff.Path, "_test.go") {
+			continue
+		}
+
+		var lines []int
+		line := 0
+		inHunk := false
+		for _, text := range strings.Split(fileDiff.Diff, "\n") {
+			if match := hunkRe.FindStringSubmatch(text); match != nil {
+				line, _ = strconv.Atoi(match[1])
+				inHunk = true
+				continue
+			}
+			if !inHunk || text == "" {
+				continue
+			}
+			switch text[0] {
+			case '+':
+				lines = append(lines, line)
+				line++
+			case '-':
+				lines = append(lines, line)
+			case ' ':
+				line++
+			}
+		}
+		if len(lines) > 0 {
+			changed[fileDiff.Path] = lines
+		}
+	}
+	return changed
+}
+
+// forChangedDecls calls fn with every top-level declaration of the changed files that overlaps a changed line
+func forChangedDecls(l *loader, changed map[string][]int, fn func(pkg *loadedPackage, decl ast.Decl)) {
+	dirs := lo.Uniq(lo.Map(slices.Sorted(maps.Keys(changed)), func(p string, _ int) string { return path.Dir(p) }))
+	for _, dir := range dirs {
+		pkg := l.loadDir(dir)
+		if pkg == nil {
+			continue
+		}
+		for filePath, file := range pkg.files {
+			lines, ok := changed[filePath]
+			if !ok {
+				continue
+			}
+			for _, decl := range file.Decls {
+				if overlaps(l.fset, decl, lines) {
+					fn(pkg, decl)
+				}
+			}
+		}
+	}
+}
+
+// overlaps reports whether a node spans one of the lines
+func overlaps(fset *token.FileSet, node ast.Node, lines []int) bool {
+	start := fset.Position(node.Pos()).Line
+	end := fset.Position(node.End()).Line
+	return lo.ContainsBy(lines, func(line int) bool { return line >= start && line <= end })
+}
+
+// collector gathers the declarations referenced by changed code
+type collector struct {
+	loader       *loader
+	changed      map[string][]int
+	seen         map[types.Object]bool
+	declarations []Declaration
+}
+
+// visit records the types and functions a changed declaration uses, and a method's receiver type
+func (c *collector) visit(pkg *loadedPackage, decl ast.Decl) {
+	if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil {
+		if obj, ok := pkg.info.Defs[fn.Name].(*types.Func); ok {
+			c.addType(obj.Signature().Recv().Type())
+		}
+	}
+
+	ast.Inspect(decl, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			// The struct or interface whose field or method is used
+			if selection := pkg.info.Selections[n]; selection != nil {
+				c.addType(selection.Recv())
+			}
+		case *ast.Ident:
+			switch obj := pkg.info.Uses[n].(type) {
+			case *types.TypeName, *types.Func:
+				c.add(obj)
+			}
+		}
+		return true
+	})
+}
+
+// addType records the named type behind a (pointer) type
+func (c *collector) addType(t types.Type) {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+		c.add(named.Origin().Obj())
+	}
+}
+
+// add records a type or function declared in an unchanged file of the module
+// Files excluded from the review are skipped and secrets in the source are masked, like the rest of the context
+func (c *collector) add(obj types.Object) {
+	if c.seen[obj] || obj.Pkg() == nil || !obj.Pos().IsValid() {
+		return
+	}
+	c.seen[obj] = true
+
+	pkg := c.loader.packages[obj.Pkg().Path()]
+	filePath := c.loader.relPath(obj.Pos())
+	if _, changed := c.changed[filePath]; changed || filter.ShouldIgnore(filePath) || pkg == nil || pkg.files[filePath] == nil {
+		return
+	}
+
+	source := declarationSource(c.loader.fset, pkg.files[filePath], obj.Pos())
+	if source == "" {
+		return
+	}
+	c.declarations = append(c.declarations, Declaration{
+		Name:   obj.Name(),
+		Path:   filePath,
+		Line:   c.loader.fset.Position(obj.Pos()).Line,
+		Source: filter.RedactSecrets(source),
+	})
+}
+
+// declarationSource prints the type spec or function signature declaring the name at pos
+func declarationSource(fset *token.FileSet, file *ast.File, pos token.Pos) string {
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Name.Pos() == pos {
+				signature := *d
+				signature.Doc = nil
+				signature.Body = nil
+				return printNode(fset, &signature)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.Pos() == pos {
+					return printNode(fset, &ast.GenDecl{Tok: token.TYPE, Specs: []ast.Spec{ts}})
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// printNode prints a node as gofmt-formatted source without comments
+func printNode(fset *token.FileSet, node any) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
@@ -0,0 +1,215 @@
+package goref
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferenced(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.22\n",
+		"store/store.go": `package store
+
+// Store persists orders
+type Store interface {
+	Save(id string) error
+}
+
+// Lookup finds an order
+func Lookup(id string) (string, error) {
+	return id, nil
+}
+`,
+		"service/service.go": `package service
+
+import "example.com/shop/store"
+
+// Service handles orders
+type Service struct {
+	store store.Store
+	limit int
+}
+
+// Unused is never referenced
+func Unused() {}
+`,
+		"service/order.go": `package service
+
+import (
+	"fmt"
+
+	"example.com/shop/store"
+)
+
+func (s *Service) Place(id string) error {
+	if _, err := store.Lookup(id); err != nil {
+		return fmt.Errorf("lookup: %w", err)
+	}
+	return s.store.Save(id)
+}
+
+func helper() {}
+`,
+	})
+
+	diff := `diff --git a/service/order.go b/service/order.go
+--- a/service/order.go
++++ b/service/order.go
+@@ -10,4 +10,4 @@ func (s *Service) Place(id string) error {
+ 	if _, err := store.Lookup(id); err != nil {
+-		return err
++		return fmt.Errorf("lookup: %w", err)
+ 	}
+ 	return s.store.Save(id)
+`
+
+	declarations := Referenced(root, diff, nil)
+	names := make([]string, 0, len(declarations))
+	for _, d := range declarations {
+		names = append(names, d.Name)
+	}
+	require.Equal(t, []string{"Service", "Store", "Lookup"}, names)
+
+	require.Equal(t, "service/service.go", declarations[0].Path)
+	require.Equal(t, 6, declarations[0].Line)
+	require.Contains(t, declarations[0].Source, "store.Store")
+	require.NotContains(t, declarations[0].Source, "Service handles orders")
+	require.Equal(t, "func Lookup(id string) (string, error)", declarations[2].Source)
+
+	sections := DeclarationSections(declarations)
+	require.Len(t, sections, 1)
+	require.Equal(t, DeclarationsTitle, sections[0].Title)
+	require.Contains(t, sections[0].Body, "// store/store.go:4\ntype Store interface")
+}
+
+func TestReferencedOverlay(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"types.go": "package main\n\ntype Config struct {\n\tName string\n}\n",
+		"main.go":  "package main\n\nfunc main() {}\n",
+	})
+
+	// The changed content comes from the overlay, not the disk
+	overlay := map[string]string{"main.go": "package main\n\nfunc main() {\n\t_ = Config{Name: \"x\"}\n}\n"}
+	diff := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -3 +3,3 @@\n-func main() {}\n+func main() {\n+\t_ = Config{Name: \"x\"}\n+}\n"
+
+	declarations := Referenced(root, diff, overlay)
+	require.Len(t, declarations, 1)
+	require.Equal(t, "Config", declarations[0].Name)
+	require.Nil(t, Referenced(root, "", overlay))
+	require.Nil(t, DeclarationSections(nil))
+}
+
+func TestReferencedFiltersDeclarations(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":         "module example.com/shop\n\ngo 1.22\n",
+		"mocks/store.go": "package mocks\n\ntype FakeStore struct{}\n",
+		"config/config.go": "package config\n\ntype Config struct {\n" +
+			"\tKey string `default:\"api_key=abcdefghijklmnopqrstuvwxyz\"`\n}\n",
+		"service/order.go": "package service\n\nimport (\n\t\"example.com/shop/config\"\n\t\"example.com/shop/mocks\"\n)\n\n" +
+			"func Place() {\n\t_ = mocks.FakeStore{}\n\t_ = config.Config{}\n}\n",
+	})
+	diff := "diff --git a/service/order.go b/service/order.go\n--- a/service/order.go\n+++ b/service/order.go\n" +
+		"@@ -8,2 +8,4 @@\n func Place() {\n+\t_ = mocks.FakeStore{}\n+\t_ = config.Config{}\n }\n"
+
+	// Mocks are excluded from the review; the secret in the struct tag is masked
+	declarations := Referenced(root, diff, nil)
+	require.Len(t, declarations, 1)
+	require.Equal(t, "Config", declarations[0].Name)
+	require.NotContains(t, declarations[0].Source, "abcdefghijklmnopqrstuvwxyz")
+	require.Contains(t, declarations[0].Source, "api_k***")
+}
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func TestCallers(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.22\n",
+		"store/store.go": `package store
+
+type Store struct{}
+
+func (s *Store) Save(id string) error {
+	return nil
+}
+
+func Open(path string) *Store {
+	return &Store{}
+}
+
+func unexported() {}
+`,
+		"store/store_extra.go": `package store
+
+var Default = Open("default")
+`,
+		"api/handler.go": `package api
+
+import "example.com/shop/store"
+
+func Handle(s *store.Store) error {
+	return s.Save("x")
+}
+`,
+		"cmd/main.go": `package main
+
+import "example.com/shop/store"
+
+func main() {
+	s := store.Open("db")
+	_ = s.Save("y")
+}
+`,
+		"testdata/ignored.go": "package ignored\n",
+		"mocks/store.go":      "package mocks\n\nimport \"example.com/shop/store\"\n\nfunc Save(s *store.Store) error { return s.Save(\"mock\") }\n",
+		"api/api.pb.go":       "package api\n\nimport \"example.com/shop/store\"\n\nfunc generated(s *store.Store) error { return s.Save(\"pb\") }\n",
+		"api/config.go":       "package api\n\nimport \"example.com/shop/store\"\n\nfunc configure(s *store.Store) error {\n\treturn s.Save(\"api_key=abcdefghijklmnopqrstuvwxyz\")\n}\n",
+	})
+
+	diff := `diff --git a/store/store.go b/store/store.go
+--- a/store/store.go
++++ b/store/store.go
+@@ -4,3 +4,3 @@ type Store struct{}
+ 
+-func (s *Store) Save(id string) error {
++func (s *Store) Save(id string, force bool) error {
+ 	return nil
+@@ -13,1 +13,1 @@ func Open(path string) *Store {
+-func unexported() {}
++func unexported() { _ = 1 }
+`
+
+	callers := Callers(root, diff, nil)
+	require.Equal(t, []Caller{
+		{Symbol: "Store.Save", Path: "api/config.go", Line: 6, Function: "configure", Code: `return s.Save("api_k***yz")`},
+		{Symbol: "Store.Save", Path: "api/handler.go", Line: 6, Function: "Handle", Code: `return s.Save("x")`},
+		{Symbol: "Store.Save", Path: "cmd/main.go", Line: 7, Function: "main", Code: `_ = s.Save("y")`},
+	}, callers)
+	require.Equal(t, []string{"api/config.go", "api/handler.go", "cmd/main.go"}, RelatedFiles(callers))
+
+	sections := CallerSections(callers)
+	require.Len(t, sections, 1)
+	require.Contains(t, sections[0].Body, "- `Store.Save`:\n  - `api/config.go:6` in `configure`: `return s.Save(\"api_k***yz\")`\n  - `api/handler.go:6` in `Handle`: `return s.Save(\"x\")`\n")
+	require.Nil(t, CallerSections(nil))
+}
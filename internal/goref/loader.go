@@ -0,0 +1,261 @@
+package goref
+
+import (
+	"bufio"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// loader type-checks the module's packages from source
+// Packages outside the module are stubbed with a named type for every name the module uses from them,
+// so signatures still read e.g. context.Context; type errors are ignored so a package that doesn't
+// compile is still checked as far as possible
+type loader struct {
+	fset    *token.FileSet
+	rootDir string
+	// module is the module path from go.mod ("" when the repository isn't a Go module)
+	module string
+	// overlay holds changed file contents by repo-relative path, used instead of the disk
+	overlay map[string]string
+	// packages caches checked packages by import path
+	packages map[string]*loadedPackage
+	// stubs caches stubbed packages by import path; external holds the names used from them
+	stubs    map[string]*types.Package
+	external map[string]map[string]bool
+}
+
+// loadedPackage is a type-checked package with its syntax
+type loadedPackage struct {
+	types *types.Package
+	info  *types.Info
+	files map[string]*ast.File
+}
+
+// LoadPackages type-checks the packages in repo-relative directories of the module at rootDir
+// overlay (repo-relative path -> content) overrides files on disk; directories without Go files are left out
+func LoadPackages(rootDir string, overlay map[string]string, dirs []string) map[string]*types.Package {
+	l := newLoader(rootDir, overlay)
+	packages := make(map[string]*types.Package)
+	for _, dir := range dirs {
+		if pkg := l.loadDir(dir); pkg != nil {
+			packages[dir] = pkg.types
+		}
+	}
+	return packages
+}
+
+// newLoader creates a loader for the repository at rootDir
+func newLoader(rootDir string, overlay map[string]string) *loader {
+	return &loader{
+		fset:     token.NewFileSet(),
+		rootDir:  rootDir,
+		module:   modulePath(rootDir),
+		overlay:  overlay,
+		packages: make(map[string]*loadedPackage),
+		stubs:    make(map[string]*types.Package),
+		external: make(map[string]map[string]bool),
+	}
+}
+
+// Import implements types.Importer
+func (l *loader) Import(importPath string) (*types.Package, error) {
+	if dir, ok := l.moduleDir(importPath); ok {
+		if pkg := l.load(importPath, dir); pkg != nil {
+			return pkg.types, nil
+		}
+	}
+	return l.stub(importPath), nil
+}
+
+// stub returns the stub of an external package, declaring every name used from it so far as a named type
+func (l *loader) stub(importPath string) *types.Package {
+	stub, ok := l.stubs[importPath]
+	if !ok {
+		stub = types.NewPackage(importPath, packageName(importPath))
+		stub.MarkComplete()
+		l.stubs[importPath] = stub
+	}
+	for name := range l.external[importPath] {
+		if stub.Scope().Lookup(name) == nil {
+			obj := types.NewTypeName(token.NoPos, stub, name, nil)
+			types.NewNamed(obj, types.NewInterfaceType(nil, nil), nil)
+			stub.Scope().Insert(obj)
+		}
+	}
+	return stub
+}
+
+// collectExternal records the names that files use from imported packages
+func (l *loader) collectExternal(files map[string]*ast.File) {
+	for _, file := range files {
+		imports := make(map[string]string)
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+			name := packageName(importPath)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			imports[name] = importPath
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				if importPath, ok := imports[ident.Name]; ok {
+					if l.external[importPath] == nil {
+						l.external[importPath] = make(map[string]bool)
+					}
+					l.external[importPath][sel.Sel.Name] = true
+				}
+			}
+			return true
+		})
+	}
+}
+
+// loadDir type-checks the package in a repo-relative directory
+func (l *loader) loadDir(dir string) *loadedPackage {
+	importPath := dir
+	if l.module != "" {
+		importPath = path.Join(l.module, dir)
+	}
+	return l.load(importPath, dir)
+}
+
+// load type-checks the package in a repo-relative directory (nil when it has no Go files)
+func (l *loader) load(importPath, dir string) *loadedPackage {
+	if pkg, ok := l.packages[importPath]; ok {
+		return pkg
+	}
+	// Mark the package as loading so an import cycle stubs it instead of recursing
+	l.packages[importPath] = nil
+
+	files := l.parseDir(dir)
+	if len(files) == 0 {
+		return nil
+	}
+	l.collectExternal(files)
+
+	pkg := &loadedPackage{
+		info: &types.Info{
+			Types:      make(map[ast.Expr]types.TypeAndValue),
+			Defs:       make(map[*ast.Ident]types.Object),
+			Uses:       make(map[*ast.Ident]types.Object),
+			Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		},
+		files: files,
+	}
+	conf := types.Config{
+		Importer:    l,
+		Error:       func(error) {},
+		FakeImportC: true,
+	}
+	pkg.types, _ = conf.Check(importPath, l.fset, lo.Values(files), pkg.info)
+	l.packages[importPath] = pkg
+	return pkg
+}
+
+// parseDir parses the non-test Go files of a repo-relative directory that build on this platform
+// When files declare different packages, the first file's package wins
+func (l *loader) parseDir(dir string) map[string]*ast.File {
+	entries, err := os.ReadDir(filepath.Join(l.rootDir, dir))
+	if err != nil {
+		return nil
+	}
+
+	files := make(map[string]*ast.File)
+	packageName := ""
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if match, err := build.Default.MatchFile(filepath.Join(l.rootDir, dir), name); err != nil || !match {
+			continue
+		}
+
+		relPath := path.Join(dir, name)
+		var src any
+		if content, ok := l.overlay[relPath]; ok {
+			src = content
+		}
+		file, err := parser.ParseFile(l.fset, filepath.Join(l.rootDir, relPath), src, parser.SkipObjectResolution)
+		if err != nil && file == nil {
+			continue
+		}
+		if packageName == "" {
+			packageName = file.Name.Name
+		}
+		if file.Name.Name == packageName {
+			files[relPath] = file
+		}
+	}
+	return files
+}
+
+// moduleDir returns the repo-relative directory of an import path inside the module
+func (l *loader) moduleDir(importPath string) (string, bool) {
+	if l.module == "" {
+		return "", false
+	}
+	if importPath == l.module {
+		return ".", true
+	}
+	rel, ok := strings.CutPrefix(importPath, l.module+"/")
+	return rel, ok
+}
+
+// relPath returns the repo-relative path of a position's file
+func (l *loader) relPath(pos token.Pos) string {
+	rel, err := filepath.Rel(l.rootDir, l.fset.Position(pos).Filename)
+	if err != nil {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// modulePath reads the module path from go.mod in rootDir ("" when there is none)
+func modulePath(rootDir string) string {
+	file, err := os.Open(filepath.Join(rootDir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if module, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(module), `"`)
+		}
+	}
+	return ""
+}
+
+// packageName guesses the name of a stubbed package from its import path (e.g. charm.land/lipgloss/v2 -> lipgloss)
+func packageName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+	name = strings.TrimPrefix(strings.TrimSuffix(name, ".go"), "go-")
+	// gopkg.in/yaml.v3 -> yaml
+	name, _, _ = strings.Cut(name, ".")
+	return strings.ReplaceAll(name, "-", "")
+}
@@ -0,0 +1,9 @@
+package gotool
+
+import "time"
+
+// DefaultTimeout bounds a single go build/test invocation
+const DefaultTimeout = 5 * time.Minute
+
+// MaxOutputLines is how many trailing output lines are kept when reporting failures
+const MaxOutputLines = 40
@@ -0,0 +1,120 @@
+// Package gotool runs the go command (build, test) on the repository
+// through the internal shell executor.
+package gotool
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+	"mvdan.cc/sh/v3/syntax"
+
+	"github.com/trankhanh040147/revcli/internal/shell"
+)
+
+// Runner executes go commands in a repository root
+type Runner struct {
+	shell *shell.Shell
+}
+
+// NewRunner creates a runner rooted at rootDir
+func NewRunner(rootDir string) *Runner {
+	return &Runner{shell: shell.NewShell(&shell.Options{WorkingDir: rootDir})}
+}
+
+// Run executes `go <args...>` and returns the combined output
+// A non-zero exit is returned as an error; the output is still returned
+func (r *Runner) Run(ctx context.Context, args ...string) (string, error) {
+	quoted := make([]string, 0, len(args)+1)
+	quoted = append(quoted, "go")
+	for _, arg := range args {
+		q, err := syntax.Quote(arg, syntax.LangBash)
+		if err != nil {
+			return "", fmt.Errorf("failed to quote %q: %w", arg, err)
+		}
+		quoted = append(quoted, q)
+	}
+
+	command := strings.Join(quoted, " ")
+	stdout, stderr, err := r.shell.Exec(ctx, command)
+	output := strings.TrimSpace(stdout + stderr)
+	if err != nil {
+		return output, fmt.Errorf("%s failed (exit %d): %w", command, shell.ExitCode(err), err)
+	}
+	return output, nil
+}
+
+// Build runs `go build ./...`
+func (r *Runner) Build(ctx context.Context) (string, error) {
+	return r.Run(ctx, "build", "./...")
+}
+
+// Test runs `go test` on the given package patterns with optional extra flags
+func (r *Runner) Test(ctx context.Context, pkgs []string, flags ...string) (string, error) {
+	args := append([]string{"test"}, flags...)
+	return r.Run(ctx, append(args, pkgs...)...)
+}
+
+// Module is a Go module in the repository with the packages of some of its files
+type Module struct {
+	// Dir is the module root, relative to the repository root ("." for the repository root)
+	Dir string
+	// Path is the module path declared in go.mod ("" without one)
+	Path string
+	// Packages are ./dir package patterns, relative to Dir
+	Packages []string
+}
+
+// ModulesForFiles groups the packages of the Go files among paths by their module: the nearest
+// directory at or above the file, up to rootDir, with a go.mod (rootDir itself when there is none)
+// Paths are repo-relative; anything outside the repository is ignored. Modules are sorted by Dir
+func ModulesForFiles(rootDir string, paths []string) []Module {
+	goFiles := lo.Filter(paths, func(p string, _ int) bool {
+		return strings.HasSuffix(p, ".go") && filepath.IsLocal(p)
+	})
+	dirs := lo.Uniq(lo.Map(goFiles, func(p string, _ int) string {
+		return path.Dir(filepath.ToSlash(p))
+	}))
+	byModule := lo.GroupBy(dirs, func(dir string) string { return moduleDir(rootDir, dir) })
+
+	modules := make([]Module, 0, len(byModule))
+	for _, dir := range slices.Sorted(maps.Keys(byModule)) {
+		pkgs := lo.Map(byModule[dir], func(pkgDir string, _ int) string {
+			rel, _ := filepath.Rel(dir, pkgDir)
+			return strings.TrimSuffix("./"+filepath.ToSlash(rel), "/.")
+		})
+		slices.Sort(pkgs)
+		modules = append(modules, Module{Dir: dir, Path: ModulePath(filepath.Join(rootDir, dir)), Packages: pkgs})
+	}
+	return modules
+}
+
+// moduleDir returns the nearest directory at or above dir with a go.mod, relative to rootDir ("." when there is none)
+func moduleDir(rootDir, dir string) string {
+	for ; dir != "."; dir = path.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(rootDir, dir, "go.mod")); err == nil {
+			return dir
+		}
+	}
+	return "."
+}
+
+// ModulePath reads the module path from dir's go.mod ("" when there is none)
+func ModulePath(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
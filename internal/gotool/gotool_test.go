@@ -0,0 +1,54 @@
+package gotool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestModulesForFiles(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":           "module example.com/app\n\ngo 1.22\n",
+		"tools/gen/go.mod": "module \"example.com/app/tools/gen\"\n",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(rootDir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(rootDir, name), []byte(content), 0o644))
+	}
+
+	got := ModulesForFiles(rootDir, []string{
+		"main.go",
+		"internal/ui/model.go",
+		"internal/ui/view.go",
+		"internal/ui/README.md",
+		"tools/gen/main.go",
+		"tools/gen/parse/parse.go",
+		"tools/other.go",
+		"../outside/evil.go",
+	})
+	require.Equal(t, []Module{
+		{Dir: ".", Path: "example.com/app", Packages: []string{".", "./internal/ui", "./tools"}},
+		{Dir: "tools/gen", Path: "example.com/app/tools/gen", Packages: []string{".", "./parse"}},
+	}, got)
+
+	// Without a go.mod, the packages run from the repository root
+	require.Equal(t, []Module{{Dir: ".", Packages: []string{"./a"}}}, ModulesForFiles(t.TempDir(), []string{"a/a.go"}))
+	require.Empty(t, ModulesForFiles(rootDir, []string{"README.md"}))
+}
+
+func TestTrimOutput(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "a\nb", TrimOutput("a\nb\n"))
+
+	long := make([]byte, 0, 2*(MaxOutputLines+5))
+	for range MaxOutputLines + 5 {
+		long = append(long, 'x', '\n')
+	}
+	trimmed := TrimOutput(string(long))
+	require.Equal(t, "...", trimmed[:3])
+}
@@ -0,0 +1,12 @@
+package gotool
+
+import "strings"
+
+// TrimOutput keeps the last MaxOutputLines lines of command output
+func TrimOutput(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) <= MaxOutputLines {
+		return strings.Join(lines, "\n")
+	}
+	return "...\n" + strings.Join(lines[len(lines)-MaxOutputLines:], "\n")
+}
@@ -0,0 +1,4 @@
+package intent
+
+// FocusAreas are the focus areas a review intent can select
+var FocusAreas = []string{"security", "performance", "logic", "style", "typo", "naming"}
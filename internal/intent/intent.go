@@ -0,0 +1,91 @@
+// Package intent loads review intents from files and flags, and remembers the last intent per branch
+package intent
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+
+	appcontext "github.com/trankhanh040147/revcli/internal/context"
+)
+
+// File is the on-disk layout of an intent file (--intent-file)
+type File struct {
+	Instruction string   `yaml:"instruction"`
+	Focus       []string `yaml:"focus"`
+	Ignore      []string `yaml:"ignore"`
+	CheckIntent bool     `yaml:"check_intent"`
+}
+
+// Overrides are intent fields set on the command line; nil fields are left unchanged
+type Overrides struct {
+	Instruction *string
+	Focus       []string
+	Ignore      []string
+}
+
+// LoadFile reads an intent file
+func LoadFile(path string) (*appcontext.Intent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read intent file %s: %w", path, err)
+	}
+
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse intent file %s: %w", path, err)
+	}
+	focus, err := ParseFocusAreas(file.Focus)
+	if err != nil {
+		return nil, fmt.Errorf("invalid intent file %s: %w", path, err)
+	}
+	return &appcontext.Intent{
+		CustomInstruction:   strings.TrimSpace(file.Instruction),
+		FocusAreas:          focus,
+		NegativeConstraints: clean(file.Ignore),
+		WebSearchEnabled:    true,
+		CheckIntent:         file.CheckIntent,
+	}, nil
+}
+
+// Apply returns a copy of base (a new intent when nil) with the overrides set
+func Apply(base *appcontext.Intent, overrides Overrides) (*appcontext.Intent, error) {
+	applied := appcontext.Intent{WebSearchEnabled: true}
+	if base != nil {
+		applied = *base
+	}
+	if overrides.Instruction != nil {
+		applied.CustomInstruction = strings.TrimSpace(*overrides.Instruction)
+	}
+	if overrides.Focus != nil {
+		focus, err := ParseFocusAreas(overrides.Focus)
+		if err != nil {
+			return nil, err
+		}
+		applied.FocusAreas = focus
+	}
+	if overrides.Ignore != nil {
+		applied.NegativeConstraints = clean(overrides.Ignore)
+	}
+	return &applied, nil
+}
+
+// ParseFocusAreas normalizes focus areas and checks they are known
+func ParseFocusAreas(areas []string) ([]string, error) {
+	focus := lo.Map(clean(areas), func(area string, _ int) string { return strings.ToLower(area) })
+	for _, area := range focus {
+		if !slices.Contains(FocusAreas, area) {
+			return nil, fmt.Errorf("unknown focus area %q: must be one of %s", area, strings.Join(FocusAreas, ", "))
+		}
+	}
+	return lo.Uniq(focus), nil
+}
+
+// clean trims the items and drops empty ones
+func clean(items []string) []string {
+	return lo.Compact(lo.Map(items, func(item string, _ int) string { return strings.TrimSpace(item) }))
+}
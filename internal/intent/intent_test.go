@@ -0,0 +1,94 @@
+package intent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	appcontext "github.com/trankhanh040147/revcli/internal/context"
+)
+
+func TestLoadFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "intent.yaml")
+	content := "instruction: \" Check the retry logic \"\nfocus: [Security, logic, security]\nignore: [naming, \" \", docs]\ncheck_intent: true\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	got, err := LoadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, &appcontext.Intent{
+		CustomInstruction:   "Check the retry logic",
+		FocusAreas:          []string{"security", "logic"},
+		NegativeConstraints: []string{"naming", "docs"},
+		WebSearchEnabled:    true,
+		CheckIntent:         true,
+	}, got)
+
+	bad := filepath.Join(dir, "bad.yaml")
+	require.NoError(t, os.WriteFile(bad, []byte("focus: [speed]\n"), 0o644))
+	_, err = LoadFile(bad)
+	require.ErrorContains(t, err, `unknown focus area "speed"`)
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	base := &appcontext.Intent{
+		CustomInstruction:   "Check the retry logic",
+		FocusAreas:          []string{"security"},
+		NegativeConstraints: []string{"docs"},
+		CheckIntent:         true,
+	}
+	instruction := "Review the cache"
+
+	tests := []struct {
+		name      string
+		base      *appcontext.Intent
+		overrides Overrides
+		want      *appcontext.Intent
+		wantErr   string
+	}{
+		{
+			name:      "flags override the base",
+			base:      base,
+			overrides: Overrides{Instruction: &instruction, Focus: []string{"logic", "performance"}},
+			want: &appcontext.Intent{
+				CustomInstruction:   "Review the cache",
+				FocusAreas:          []string{"logic", "performance"},
+				NegativeConstraints: []string{"docs"},
+				CheckIntent:         true,
+			},
+		},
+		{
+			name:      "no base",
+			overrides: Overrides{Ignore: []string{"naming", "docs"}},
+			want: &appcontext.Intent{
+				NegativeConstraints: []string{"naming", "docs"},
+				WebSearchEnabled:    true,
+			},
+		},
+		{
+			name:      "unknown focus area",
+			base:      base,
+			overrides: Overrides{Focus: []string{"speed"}},
+			wantErr:   `unknown focus area "speed"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Apply(tt.base, tt.overrides)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+	require.Equal(t, "Check the retry logic", base.CustomInstruction, "the base intent is not modified")
+}
@@ -0,0 +1,89 @@
+package intent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/bytedance/sonic"
+
+	appcontext "github.com/trankhanh040147/revcli/internal/context"
+	"github.com/trankhanh040147/revcli/internal/db"
+)
+
+// Service remembers the last review intent of each repository branch
+type Service interface {
+	// Last returns the last intent saved for a branch (nil when there is none)
+	Last(ctx context.Context, repo, branch string) (*appcontext.Intent, error)
+	// Save stores the intent the user set for a branch (its derived branch, commit and issue fields are not stored)
+	Save(ctx context.Context, repo, branch string, intent *appcontext.Intent) error
+}
+
+type service struct {
+	q *db.Queries
+}
+
+// NewService creates an intent service backed by the database
+func NewService(q *db.Queries) Service {
+	return &service{q: q}
+}
+
+func (s *service) Last(ctx context.Context, repo, branch string) (*appcontext.Intent, error) {
+	dbIntent, err := s.q.GetReviewIntent(ctx, db.GetReviewIntentParams{Repo: repo, Branch: branch})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load review intent: %w", err)
+	}
+
+	intent := &appcontext.Intent{
+		CustomInstruction: dbIntent.Instruction,
+		WebSearchEnabled:  true,
+		CheckIntent:       dbIntent.CheckIntent != 0,
+	}
+	if err := sonic.UnmarshalString(dbIntent.FocusAreas, &intent.FocusAreas); err != nil {
+		return nil, fmt.Errorf("failed to parse review intent focus areas: %w", err)
+	}
+	if err := sonic.UnmarshalString(dbIntent.NegativeConstraints, &intent.NegativeConstraints); err != nil {
+		return nil, fmt.Errorf("failed to parse review intent constraints: %w", err)
+	}
+	return intent, nil
+}
+
+func (s *service) Save(ctx context.Context, repo, branch string, intent *appcontext.Intent) error {
+	focusAreas, err := sonic.MarshalString(nonNil(intent.FocusAreas))
+	if err != nil {
+		return fmt.Errorf("failed to encode review intent focus areas: %w", err)
+	}
+	constraints, err := sonic.MarshalString(nonNil(intent.NegativeConstraints))
+	if err != nil {
+		return fmt.Errorf("failed to encode review intent constraints: %w", err)
+	}
+
+	var checkIntent int64
+	if intent.CheckIntent {
+		checkIntent = 1
+	}
+	err = s.q.UpsertReviewIntent(ctx, db.UpsertReviewIntentParams{
+		Repo:                repo,
+		Branch:              branch,
+		Instruction:         intent.CustomInstruction,
+		FocusAreas:          focusAreas,
+		NegativeConstraints: constraints,
+		CheckIntent:         checkIntent,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save review intent: %w", err)
+	}
+	return nil
+}
+
+// nonNil stores a nil list as [] rather than null
+func nonNil(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
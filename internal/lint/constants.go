@@ -0,0 +1,62 @@
+package lint
+
+import (
+	"time"
+
+	"github.com/trankhanh040147/revcli/internal/config"
+)
+
+// Output formats of the analyzers
+const (
+	// FormatVet is go vet -json: per-package objects of analyzer -> diagnostics, after "# pkg" lines
+	FormatVet = "vet"
+	// FormatStaticcheck is staticcheck -f json: one diagnostic object per line
+	FormatStaticcheck = "staticcheck"
+	// FormatGolangCI is golangci-lint's JSON output: an object with the Issues list
+	FormatGolangCI = "golangci-lint"
+	// FormatSARIF is the SARIF 2.1 log most security and lint tools can write
+	FormatSARIF = "sarif"
+)
+
+// Severities of the language server diagnostics
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// KnownAnalyzers are the default command, arguments and format of the analyzers revcli knows by name
+var KnownAnalyzers = map[string]config.Analyzer{
+	"vet":           {Name: "vet", Command: "go", Args: []string{"vet", "-json"}, Format: FormatVet},
+	"staticcheck":   {Name: "staticcheck", Command: "staticcheck", Args: []string{"-f", "json"}, Format: FormatStaticcheck},
+	"golangci-lint": {Name: "golangci-lint", Command: "golangci-lint", Args: []string{"run", "--output.json.path=stdout", "--show-stats=false"}, Format: FormatGolangCI},
+}
+
+// Timeout bounds a single analyzer run
+const Timeout = 5 * time.Minute
+
+// SectionTitle is the prompt section listing the tool findings on changed lines
+const SectionTitle = "Known Tool Findings"
+
+// SectionIntro explains the tool findings to the model
+const SectionIntro = "Static analyzers already report these findings on the changed lines. Don't repeat them; focus on what linters can't catch (logic, design, concurrency, security). Mention one only to add context the tool is missing.\n\n"
+
+// MaxSectionDiagnostics caps the findings listed in the prompt
+const MaxSectionDiagnostics = 100
+
+// SectionOmittedFormat notes the findings left out of the prompt (count)
+const SectionOmittedFormat = "\n(%d more findings omitted)\n"
+
+// SummaryFormat summarizes a report (finding count and the count per tool)
+const SummaryFormat = "Tool findings on changed lines: %d (%s)"
+
+// LSPWait bounds how long the language servers are given to publish diagnostics for the changed files
+const LSPWait = 3 * time.Second
+
+// NearLines is how far from a changed line a language server diagnostic is still reported
+const NearLines = 3
+
+// LSPSectionTitle is the prompt section listing the language servers' errors and warnings
+const LSPSectionTitle = "Compiler Diagnostics"
+
+// LSPSectionIntro explains the language server diagnostics to the model
+const LSPSectionIntro = "The language servers report these errors and warnings on or near the changed lines. Code with errors doesn't compile: report each error once as blocking, and don't spend effort on the details of code that can't build.\n\n"
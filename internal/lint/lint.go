@@ -0,0 +1,196 @@
+// Package lint runs static analyzers (go vet, staticcheck, golangci-lint or any tool writing SARIF)
+// on the changed Go packages, and asks the language servers about the changed files,
+// keeping the diagnostics on or near the changed lines
+package lint
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+	"mvdan.cc/sh/v3/syntax"
+
+	"github.com/trankhanh040147/revcli/internal/config"
+	"github.com/trankhanh040147/revcli/internal/git"
+	"github.com/trankhanh040147/revcli/internal/gotool"
+	"github.com/trankhanh040147/revcli/internal/prompt"
+	"github.com/trankhanh040147/revcli/internal/shell"
+)
+
+// Diagnostic is a finding reported by a static analyzer
+type Diagnostic struct {
+	// Tool is the name of the analyzer that reported the finding
+	Tool string `json:"tool"`
+	// Check is the tool's rule or pass (e.g. printf, SA4006, errcheck)
+	Check    string `json:"check,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	// Path is repo-relative
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// Failure is an analyzer that couldn't run or whose output couldn't be parsed
+type Failure struct {
+	Tool  string `json:"tool"`
+	Error string `json:"error"`
+}
+
+// Report is the analyzers' findings on the changed lines
+type Report struct {
+	Diagnostics []Diagnostic `json:"diagnostics"`
+	Failures    []Failure    `json:"failures,omitempty"`
+}
+
+// Run runs the enabled analyzers on the Go packages a diff changes, in rootDir,
+// and keeps the diagnostics on added lines
+// Each analyzer is bounded by Timeout; one that fails is recorded in the report instead of failing the others
+// Returns nil when no analyzer is enabled or the diff changes no Go packages
+func Run(rootDir, diff string, analyzers []config.Analyzer) *Report {
+	analyzers = lo.Reject(analyzers, func(a config.Analyzer, _ int) bool { return a.Disabled })
+	added := make(map[string][]int)
+	for _, fileDiff := range git.SplitDiff(diff) {
+		if lines := git.AddedLines(fileDiff.Diff); len(lines) > 0 {
+			added[fileDiff.Path] = lines
+		}
+	}
+	modules := gotool.ModulesForFiles(rootDir, lo.Keys(added))
+	if len(analyzers) == 0 || len(modules) == 0 {
+		return nil
+	}
+
+	report := &Report{}
+	for _, analyzer := range analyzers {
+		// Analyzers resolve packages in the module of the working directory, so each module runs from its root
+		for _, module := range modules {
+			diagnostics, err := runAnalyzer(context.Background(), rootDir, module, analyzer)
+			if err != nil {
+				message := lo.Ternary(module.Dir == ".", err.Error(), fmt.Sprintf("module %s: %v", module.Dir, err))
+				report.Failures = append(report.Failures, Failure{Tool: analyzer.Name, Error: message})
+				continue
+			}
+			report.Diagnostics = append(report.Diagnostics, OnLines(diagnostics, added)...)
+		}
+	}
+
+	slices.SortStableFunc(report.Diagnostics, func(a, b Diagnostic) int {
+		return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column), cmp.Compare(a.Tool, b.Tool))
+	})
+	return report
+}
+
+// OnLines keeps the diagnostics on the given lines (repo-relative path -> line numbers)
+func OnLines(diagnostics []Diagnostic, lines map[string][]int) []Diagnostic {
+	return lo.Filter(diagnostics, func(d Diagnostic, _ int) bool { return slices.Contains(lines[d.Path], d.Line) })
+}
+
+// runAnalyzer runs one analyzer on a module's packages, from the module's root, and parses its output
+// The diagnostics' paths are repo-relative
+func runAnalyzer(ctx context.Context, rootDir string, module gotool.Module, analyzer config.Analyzer) ([]Diagnostic, error) {
+	analyzer = withDefaults(analyzer)
+	if analyzer.Command == "" || analyzer.Format == "" {
+		return nil, fmt.Errorf("unknown analyzer %q: set its command and format", analyzer.Name)
+	}
+
+	args := append(append([]string{analyzer.Command}, analyzer.Args...), module.Packages...)
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		q, err := syntax.Quote(arg, syntax.LangBash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to quote %q: %w", arg, err)
+		}
+		quoted = append(quoted, q)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	// Analyzers exit non-zero when they report findings, so a failed run only counts when it reported none
+	moduleDir := filepath.Join(rootDir, module.Dir)
+	sh := shell.NewShell(&shell.Options{WorkingDir: moduleDir})
+	stdout, stderr, runErr := sh.Exec(ctx, strings.Join(quoted, " "))
+	diagnostics, err := Parse(analyzer.Format, analyzer.Name, moduleDir, stdout, stderr)
+	if runErr != nil && (err != nil || len(diagnostics) == 0) {
+		return nil, fmt.Errorf("%s failed (exit %d): %s", analyzer.Name, shell.ExitCode(runErr), gotool.TrimOutput(stdout+stderr))
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, d := range diagnostics {
+		if filepath.IsLocal(d.Path) {
+			diagnostics[i].Path = path.Join(module.Dir, d.Path)
+		}
+	}
+	return diagnostics, nil
+}
+
+// withDefaults fills in the command, arguments and format of a known analyzer
+func withDefaults(analyzer config.Analyzer) config.Analyzer {
+	known, ok := KnownAnalyzers[analyzer.Name]
+	if !ok {
+		return analyzer
+	}
+	if analyzer.Command == "" {
+		analyzer.Command = known.Command
+		if analyzer.Args == nil {
+			analyzer.Args = known.Args
+		}
+	}
+	analyzer.Format = cmp.Or(analyzer.Format, known.Format)
+	return analyzer
+}
+
+// Summary returns the finding count per tool
+func (r *Report) Summary() string {
+	counts := lo.CountValuesBy(r.Diagnostics, func(d Diagnostic) string { return d.Tool })
+	perTool := lo.Map(slices.Sorted(maps.Keys(counts)), func(tool string, _ int) string {
+		return fmt.Sprintf("%s %d", tool, counts[tool])
+	})
+	if len(perTool) == 0 {
+		perTool = []string{"none"}
+	}
+	return fmt.Sprintf(SummaryFormat, len(r.Diagnostics), strings.Join(perTool, ", "))
+}
+
+// Sections renders the diagnostics as a prompt section
+// Returns nil for a nil report or one without diagnostics
+func (r *Report) Sections() []prompt.Section {
+	if r == nil || len(r.Diagnostics) == 0 {
+		return nil
+	}
+
+	return []prompt.Section{{Title: SectionTitle, Body: renderDiagnostics(SectionIntro, r.Diagnostics)}}
+}
+
+// renderDiagnostics lists up to MaxSectionDiagnostics diagnostics after an intro
+func renderDiagnostics(intro string, diagnostics []Diagnostic) string {
+	var builder strings.Builder
+	builder.WriteString(intro)
+	for _, d := range diagnostics[:min(len(diagnostics), MaxSectionDiagnostics)] {
+		builder.WriteString("- " + d.String() + "\n")
+	}
+	if omitted := len(diagnostics) - MaxSectionDiagnostics; omitted > 0 {
+		builder.WriteString(fmt.Sprintf(SectionOmittedFormat, omitted))
+	}
+	return builder.String()
+}
+
+// String formats a diagnostic as "`path:line:col` [tool check] severity: message"
+func (d Diagnostic) String() string {
+	location := fmt.Sprintf("%s:%d", d.Path, d.Line)
+	if d.Column > 0 {
+		location += fmt.Sprintf(":%d", d.Column)
+	}
+	source := strings.TrimSpace(d.Tool + " " + d.Check)
+	message := d.Message
+	if d.Severity != "" {
+		message = d.Severity + ": " + message
+	}
+	return fmt.Sprintf("`%s` [%s] %s", location, source, message)
+}
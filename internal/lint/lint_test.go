@@ -0,0 +1,184 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/x/powernap/pkg/lsp/protocol"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trankhanh040147/revcli/internal/config"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.22\n"), 0o644))
+	source := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Printf(\"%d\\n\", \"x\")\n\tfmt.Printf(\"%s\\n\", 1)\n}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(source), 0o644))
+	// Only line 7 is changed, so the finding on line 6 is left out
+	diff := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -6,2 +6,2 @@ func main() {\n \tfmt.Printf(\"%d\\n\", \"x\")\n-\tfmt.Println(1)\n+\tfmt.Printf(\"%s\\n\", 1)\n"
+
+	require.Nil(t, Run(dir, diff, nil))
+
+	report := Run(dir, diff, []config.Analyzer{{Name: "vet"}, {Name: "missing", Command: "revcli-missing-analyzer", Format: FormatSARIF}})
+	require.NotNil(t, report)
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, "vet", report.Diagnostics[0].Tool)
+	require.Equal(t, "printf", report.Diagnostics[0].Check)
+	require.Equal(t, "main.go", report.Diagnostics[0].Path)
+	require.Equal(t, 7, report.Diagnostics[0].Line)
+	require.Len(t, report.Failures, 1)
+	require.Equal(t, "missing", report.Failures[0].Tool)
+}
+
+func TestRunNestedModule(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.22\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "tools"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tools", "go.mod"), []byte("module example.com/tools\n\ngo 1.22\n"), 0o644))
+	source := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Printf(\"%s\\n\", 1)\n}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tools", "main.go"), []byte(source), 0o644))
+	diff := "diff --git a/tools/main.go b/tools/main.go\n--- a/tools/main.go\n+++ b/tools/main.go\n@@ -6 +6 @@ func main() {\n-\tfmt.Println(1)\n+\tfmt.Printf(\"%s\\n\", 1)\n"
+
+	// go vet runs from the nested module's root; the finding keeps its repo-relative path
+	report := Run(dir, diff, []config.Analyzer{{Name: "vet"}})
+	require.NotNil(t, report)
+	require.Empty(t, report.Failures)
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, "tools/main.go", report.Diagnostics[0].Path)
+	require.Equal(t, 6, report.Diagnostics[0].Line)
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		format string
+		stdout string
+		stderr string
+		want   []Diagnostic
+	}{
+		{
+			name:   "vet",
+			format: FormatVet,
+			stderr: "# example.com/app/internal/ui\n" +
+				`{"example.com/app/internal/ui": {"printf": [{"posn": "/repo/internal/ui/view.go:12:3", "message": "fmt.Sprintf format %d has arg of wrong type"}]}}` + "\n" +
+				"# example.com/app\n{}\n",
+			want: []Diagnostic{{Tool: "tool", Check: "printf", Path: "internal/ui/view.go", Line: 12, Column: 3, Message: "fmt.Sprintf format %d has arg of wrong type"}},
+		},
+		{
+			name:   "staticcheck",
+			format: FormatStaticcheck,
+			stdout: `{"code":"SA4006","severity":"error","location":{"file":"/repo/main.go","line":7,"column":2},"message":"this value of err is never used"}` + "\n" +
+				`{"code":"S1002","severity":"error","location":{"file":"/repo/main.go","line":9,"column":5},"message":"should omit comparison to bool constant"}` + "\n",
+			want: []Diagnostic{
+				{Tool: "tool", Check: "SA4006", Severity: "error", Path: "main.go", Line: 7, Column: 2, Message: "this value of err is never used"},
+				{Tool: "tool", Check: "S1002", Severity: "error", Path: "main.go", Line: 9, Column: 5, Message: "should omit comparison to bool constant"},
+			},
+		},
+		{
+			name:   "golangci-lint",
+			format: FormatGolangCI,
+			stdout: `{"Issues":[{"FromLinter":"errcheck","Text":"Error return value is not checked","Severity":"","Pos":{"Filename":"internal/db/db.go","Line":40,"Column":10}}],"Report":{}}`,
+			want:   []Diagnostic{{Tool: "tool", Check: "errcheck", Path: "internal/db/db.go", Line: 40, Column: 10, Message: "Error return value is not checked"}},
+		},
+		{
+			name:   "sarif",
+			format: FormatSARIF,
+			stdout: `{"version":"2.1.0","runs":[{"tool":{"driver":{"name":"gosec"}},"results":[` +
+				`{"ruleId":"G104","level":"warning","message":{"text":"Errors unhandled."},"locations":[{"physicalLocation":{"artifactLocation":{"uri":"file:///repo/cmd/run.go"},"region":{"startLine":21,"startColumn":4}}}]},` +
+				`{"ruleId":"G101","level":"error","message":{"text":"No location"}}]}]}`,
+			want: []Diagnostic{{Tool: "tool", Check: "G104", Severity: "warning", Path: "cmd/run.go", Line: 21, Column: 4, Message: "Errors unhandled."}},
+		},
+		{
+			name:   "no findings",
+			format: FormatStaticcheck,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Parse(tt.format, "tool", "/repo", tt.stdout, tt.stderr)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := Parse(FormatVet, "vet", "/repo", "", "# example.com/app\nvet: main.go:3:1: expected declaration")
+	require.Error(t, err)
+	_, err = Parse("checkstyle", "tool", "/repo", "", "")
+	require.ErrorContains(t, err, `unknown analyzer output format "checkstyle"`)
+}
+
+func TestOnLines(t *testing.T) {
+	t.Parallel()
+
+	diagnostics := []Diagnostic{
+		{Tool: "vet", Path: "main.go", Line: 3},
+		{Tool: "vet", Path: "main.go", Line: 4},
+		{Tool: "vet", Path: "other.go", Line: 3},
+	}
+	got := OnLines(diagnostics, map[string][]int{"main.go": {3, 5}})
+	require.Equal(t, []Diagnostic{{Tool: "vet", Path: "main.go", Line: 3}}, got)
+}
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	var empty *Report
+	require.Nil(t, empty.Sections())
+
+	report := &Report{Diagnostics: []Diagnostic{
+		{Tool: "vet", Check: "printf", Path: "main.go", Line: 3, Column: 2, Message: "bad format"},
+		{Tool: "staticcheck", Check: "SA4006", Path: "main.go", Line: 8, Message: "unused value"},
+		{Tool: "vet", Check: "copylocks", Path: "sync.go", Line: 1, Message: "copies lock"},
+	}}
+	require.Equal(t, "Tool findings on changed lines: 3 (staticcheck 1, vet 2)", report.Summary())
+
+	sections := report.Sections()
+	require.Len(t, sections, 1)
+	require.Equal(t, SectionTitle, sections[0].Title)
+	require.Contains(t, sections[0].Body, "- `main.go:3:2` [vet printf] bad format\n")
+	require.Contains(t, sections[0].Body, "- `main.go:8` [staticcheck SA4006] unused value\n")
+}
+
+func TestLSPDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	at := func(line uint32) protocol.Range {
+		return protocol.Range{Start: protocol.Position{Line: line, Character: 4}}
+	}
+	diags := []protocol.Diagnostic{
+		{Range: at(9), Severity: protocol.SeverityError, Source: "compiler", Code: "UndeclaredName", Message: "undefined: retry"},
+		{Range: at(40), Severity: protocol.SeverityWarning, Source: "unusedparams", Message: "unused parameter: ctx"},
+		{Range: at(11), Severity: protocol.SeverityHint, Message: "could use tagged switch"},
+	}
+	got := Near(fromLSP("gopls", "internal/ui/view.go", diags), map[string][]int{"internal/ui/view.go": {7}}, NearLines)
+	require.Equal(t, []Diagnostic{{
+		Tool:     "gopls",
+		Check:    "compiler UndeclaredName",
+		Severity: SeverityError,
+		Path:     "internal/ui/view.go",
+		Line:     10,
+		Column:   5,
+		Message:  "undefined: retry",
+	}}, got)
+
+	errors, warnings := CountSeverity(fromLSP("gopls", "internal/ui/view.go", diags))
+	require.Equal(t, 1, errors)
+	require.Equal(t, 1, warnings)
+
+	require.Nil(t, LSPSections(nil))
+	sections := LSPSections(got)
+	require.Len(t, sections, 1)
+	require.Equal(t, LSPSectionTitle, sections[0].Title)
+	require.Contains(t, sections[0].Body, "- `internal/ui/view.go:10:5` [gopls compiler UndeclaredName] error: undefined: retry\n")
+
+	require.Nil(t, LSPDiagnostics(nil, "/repo", "diff --git a/a.go b/a.go\n"))
+}
@@ -0,0 +1,127 @@
+package lint
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/x/powernap/pkg/lsp/protocol"
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/csync"
+	"github.com/trankhanh040147/revcli/internal/git"
+	"github.com/trankhanh040147/revcli/internal/lsp"
+	"github.com/trankhanh040147/revcli/internal/prompt"
+)
+
+// LSPDiagnostics opens the files a diff changes in the language servers that handle them,
+// waits up to LSPWait for their diagnostics, and returns the errors and warnings within NearLines of an added line
+// Only the servers that are running when it's called are asked
+func LSPDiagnostics(clients *csync.Map[string, *lsp.Client], rootDir, diff string) []Diagnostic {
+	if clients == nil || clients.Len() == 0 {
+		return nil
+	}
+	added := make(map[string][]int)
+	for _, fileDiff := range git.SplitDiff(diff) {
+		if lines := git.AddedLines(fileDiff.Diff); len(lines) > 0 {
+			added[fileDiff.Path] = lines
+		}
+	}
+	paths := slices.Sorted(maps.Keys(added))
+	ctx := context.Background()
+
+	var diagnostics []Diagnostic
+	for name, client := range clients.Seq2() {
+		handled := lo.Filter(paths, func(p string, _ int) bool { return client.HandlesFile(p) })
+		if len(handled) == 0 {
+			continue
+		}
+		for _, p := range handled {
+			fullPath := filepath.Join(rootDir, p)
+			if client.IsFileOpen(fullPath) {
+				_ = client.NotifyChange(ctx, fullPath)
+			} else {
+				_ = client.OpenFileOnDemand(ctx, fullPath)
+			}
+		}
+		client.WaitForDiagnostics(ctx, LSPWait)
+
+		for _, p := range handled {
+			diags := client.GetFileDiagnostics(protocol.URIFromPath(filepath.Join(rootDir, p)))
+			diagnostics = append(diagnostics, fromLSP(name, p, diags)...)
+		}
+	}
+
+	diagnostics = Near(diagnostics, added, NearLines)
+	slices.SortStableFunc(diagnostics, func(a, b Diagnostic) int {
+		return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column), cmp.Compare(a.Tool, b.Tool))
+	})
+	return diagnostics
+}
+
+// Near keeps the diagnostics within distance lines of the given lines (repo-relative path -> line numbers)
+func Near(diagnostics []Diagnostic, lines map[string][]int, distance int) []Diagnostic {
+	return lo.Filter(diagnostics, func(d Diagnostic, _ int) bool {
+		return lo.SomeBy(lines[d.Path], func(line int) bool { return max(line-d.Line, d.Line-line) <= distance })
+	})
+}
+
+// CountSeverity counts the error and warning diagnostics
+func CountSeverity(diagnostics []Diagnostic) (errors, warnings int) {
+	for _, d := range diagnostics {
+		switch d.Severity {
+		case SeverityError:
+			errors++
+		case SeverityWarning:
+			warnings++
+		}
+	}
+	return errors, warnings
+}
+
+// ForFile returns the diagnostics of a file (repo-relative path)
+func ForFile(diagnostics []Diagnostic, path string) []Diagnostic {
+	return lo.Filter(diagnostics, func(d Diagnostic, _ int) bool { return d.Path == path })
+}
+
+// LSPSections renders the language server diagnostics as a prompt section
+// Returns nil when there are none
+func LSPSections(diagnostics []Diagnostic) []prompt.Section {
+	if len(diagnostics) == 0 {
+		return nil
+	}
+	return []prompt.Section{{Title: LSPSectionTitle, Body: renderDiagnostics(LSPSectionIntro, diagnostics)}}
+}
+
+// fromLSP converts a server's errors and warnings for a file (repo-relative path), dropping information and hints
+func fromLSP(server, path string, diags []protocol.Diagnostic) []Diagnostic {
+	return lo.FilterMap(diags, func(d protocol.Diagnostic, _ int) (Diagnostic, bool) {
+		var severity string
+		switch d.Severity {
+		case protocol.SeverityError:
+			severity = SeverityError
+		case protocol.SeverityWarning:
+			severity = SeverityWarning
+		default:
+			return Diagnostic{}, false
+		}
+
+		check := d.Source
+		if d.Code != nil {
+			check = strings.TrimSpace(check + " " + fmt.Sprint(d.Code))
+		}
+		return Diagnostic{
+			Tool:     server,
+			Check:    check,
+			Severity: severity,
+			Path:     path,
+			Line:     int(d.Range.Start.Line) + 1,
+			Column:   int(d.Range.Start.Character) + 1,
+			Message:  d.Message,
+		}, true
+	})
+}
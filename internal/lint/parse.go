@@ -0,0 +1,235 @@
+package lint
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic/decoder"
+	"github.com/samber/lo"
+)
+
+// vetPackage is one package of go vet -json output: analyzer -> diagnostics
+type vetPackage map[string]map[string][]struct {
+	Posn    string `json:"posn"`
+	Message string `json:"message"`
+}
+
+// staticcheckDiagnostic is one line of staticcheck -f json output
+type staticcheckDiagnostic struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Location struct {
+		File   string `json:"file"`
+		Line   int    `json:"line"`
+		Column int    `json:"column"`
+	} `json:"location"`
+	Message string `json:"message"`
+}
+
+// golangCIOutput is golangci-lint's JSON output
+type golangCIOutput struct {
+	Issues []struct {
+		FromLinter string `json:"FromLinter"`
+		Text       string `json:"Text"`
+		Severity   string `json:"Severity"`
+		Pos        struct {
+			Filename string `json:"Filename"`
+			Line     int    `json:"Line"`
+			Column   int    `json:"Column"`
+		} `json:"Pos"`
+	} `json:"Issues"`
+}
+
+// sarifLog is the part of a SARIF 2.1 log with the results
+type sarifLog struct {
+	Runs []struct {
+		Results []struct {
+			RuleID  string `json:"ruleId"`
+			Level   string `json:"level"`
+			Message struct {
+				Text string `json:"text"`
+			} `json:"message"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+					Region struct {
+						StartLine   int `json:"startLine"`
+						StartColumn int `json:"startColumn"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+// Parse parses an analyzer's output in one of the formats; tool names the analyzer in the diagnostics
+// and paths are made relative to rootDir
+func Parse(format, tool, rootDir, stdout, stderr string) ([]Diagnostic, error) {
+	var diagnostics []Diagnostic
+	var err error
+	switch format {
+	case FormatVet:
+		// go vet writes its JSON to stderr
+		diagnostics, err = parseVet(tool, stdout+"\n"+stderr)
+	case FormatStaticcheck:
+		diagnostics, err = parseStaticcheck(tool, stdout)
+	case FormatGolangCI:
+		diagnostics, err = parseGolangCI(tool, stdout)
+	case FormatSARIF:
+		diagnostics, err = parseSARIF(tool, stdout)
+	default:
+		return nil, fmt.Errorf("unknown analyzer output format %q: must be vet, staticcheck, golangci-lint or sarif", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s output: %w", tool, err)
+	}
+
+	for i := range diagnostics {
+		diagnostics[i].Path = relPath(rootDir, diagnostics[i].Path)
+	}
+	return diagnostics, nil
+}
+
+// parseVet parses go vet -json output, skipping its "# package" lines
+func parseVet(tool, output string) ([]Diagnostic, error) {
+	lines := lo.Reject(strings.Split(output, "\n"), func(line string, _ int) bool { return strings.HasPrefix(line, "#") })
+	var diagnostics []Diagnostic
+	err := decodeAll(strings.Join(lines, "\n"), func(pkg vetPackage) {
+		for _, analyzers := range pkg {
+			for _, analyzer := range slices.Sorted(maps.Keys(analyzers)) {
+				for _, d := range analyzers[analyzer] {
+					path, line, column := splitPosition(d.Posn)
+					diagnostics = append(diagnostics, Diagnostic{
+						Tool:    tool,
+						Check:   analyzer,
+						Path:    path,
+						Line:    line,
+						Column:  column,
+						Message: d.Message,
+					})
+				}
+			}
+		}
+	})
+	return diagnostics, err
+}
+
+// parseStaticcheck parses staticcheck's JSON lines
+func parseStaticcheck(tool, output string) ([]Diagnostic, error) {
+	var diagnostics []Diagnostic
+	err := decodeAll(output, func(d staticcheckDiagnostic) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Tool:     tool,
+			Check:    d.Code,
+			Severity: d.Severity,
+			Path:     d.Location.File,
+			Line:     d.Location.Line,
+			Column:   d.Location.Column,
+			Message:  d.Message,
+		})
+	})
+	return diagnostics, err
+}
+
+// parseGolangCI parses golangci-lint's JSON output
+func parseGolangCI(tool, output string) ([]Diagnostic, error) {
+	var diagnostics []Diagnostic
+	err := decodeAll(output, func(out golangCIOutput) {
+		for _, issue := range out.Issues {
+			diagnostics = append(diagnostics, Diagnostic{
+				Tool:     tool,
+				Check:    issue.FromLinter,
+				Severity: issue.Severity,
+				Path:     issue.Pos.Filename,
+				Line:     issue.Pos.Line,
+				Column:   issue.Pos.Column,
+				Message:  issue.Text,
+			})
+		}
+	})
+	return diagnostics, err
+}
+
+// parseSARIF parses a SARIF log, keeping the first location of each result
+func parseSARIF(tool, output string) ([]Diagnostic, error) {
+	var diagnostics []Diagnostic
+	err := decodeAll(output, func(log sarifLog) {
+		for _, run := range log.Runs {
+			for _, result := range run.Results {
+				if len(result.Locations) == 0 {
+					continue
+				}
+				location := result.Locations[0].PhysicalLocation
+				diagnostics = append(diagnostics, Diagnostic{
+					Tool:     tool,
+					Check:    result.RuleID,
+					Severity: result.Level,
+					Path:     strings.TrimPrefix(location.ArtifactLocation.URI, "file://"),
+					Line:     location.Region.StartLine,
+					Column:   location.Region.StartColumn,
+					Message:  result.Message.Text,
+				})
+			}
+		}
+	})
+	return diagnostics, err
+}
+
+// decodeAll decodes a stream of JSON values of type T and calls fn with each
+func decodeAll[T any](output string, fn func(T)) error {
+	dec := decoder.NewDecoder(output)
+	for strings.TrimSpace(output[dec.Pos():]) != "" {
+		var value T
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		fn(value)
+	}
+	return nil
+}
+
+// splitPosition splits a file:line:column position
+func splitPosition(posn string) (string, int, int) {
+	rest, column, err := cutLastNumber(posn)
+	if err != nil {
+		return posn, 0, 0
+	}
+	path, line, err := cutLastNumber(rest)
+	if err != nil {
+		return rest, column, 0
+	}
+	return path, line, column
+}
+
+// cutLastNumber splits "prefix:number"
+func cutLastNumber(s string) (string, int, error) {
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return s, 0, fmt.Errorf("no position in %q", s)
+	}
+	n, err := strconv.Atoi(s[i+1:])
+	return s[:i], n, err
+}
+
+// relPath makes a tool's file path relative to rootDir, resolving symlinks in rootDir if needed
+func relPath(rootDir, path string) string {
+	if !filepath.IsAbs(path) {
+		return filepath.ToSlash(filepath.Clean(path))
+	}
+	roots := []string{rootDir}
+	if resolved, err := filepath.EvalSymlinks(rootDir); err == nil {
+		roots = append(roots, resolved)
+	}
+	for _, root := range roots {
+		if rel, err := filepath.Rel(root, path); err == nil && filepath.IsLocal(rel) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(path)
+}
@@ -0,0 +1,45 @@
+package patch
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// Annotate rewrites a review response so every patch carries its verification result
+// - repaired patches replace the original block
+// - invalid patches are flagged with their problems so they aren't copied blindly
+func Annotate(response string, patches []*Patch) string {
+	for _, p := range patches {
+		response = strings.Replace(response, p.Block, annotation(p), 1)
+	}
+	return response
+}
+
+// annotation renders a patch block with its status label
+func annotation(p *Patch) string {
+	switch p.Status {
+	case StatusValid:
+		return LabelVerified + "\n\n" + p.Block
+	case StatusRepaired:
+		return LabelRepaired + "\n\n" + fence(p)
+	case StatusInvalid:
+		problems := lo.Map(p.Problems, func(problem string, _ int) string {
+			return "> - " + problem
+		})
+		return LabelInvalid + "\n" + strings.Join(problems, "\n") + "\n\n" + p.Block
+	default:
+		return p.Block
+	}
+}
+
+// Summary returns a one-line verification summary, or "" when there are no patches
+func Summary(patches []*Patch) string {
+	if len(patches) == 0 {
+		return ""
+	}
+	counts := lo.CountValuesBy(patches, func(p *Patch) Status { return p.Status })
+	return fmt.Sprintf("Patches: %d verified, %d repaired, %d invalid",
+		counts[StatusValid], counts[StatusRepaired], counts[StatusInvalid])
+}
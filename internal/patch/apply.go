@@ -0,0 +1,169 @@
+package patch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/trankhanh040147/revcli/internal/git"
+	"github.com/trankhanh040147/revcli/internal/history"
+)
+
+// Backup is the pre-change state of one file touched by an applied patch
+type Backup struct {
+	// Path is the absolute file path
+	Path string
+	// VersionID is the history version holding the pre-change content
+	VersionID string
+	// Existed is false when the patch created the file
+	Existed bool
+}
+
+// Applied records a patch written to disk so it can be undone
+type Applied struct {
+	Patch   *Patch
+	Backups []Backup
+}
+
+// Applier writes verified patches to the working tree
+// Every touched file is versioned through history.Service before it changes
+type Applier struct {
+	history   history.Service
+	sessionID string
+	rootDir   string
+}
+
+// NewApplier creates an applier for the current repository
+func NewApplier(historyService history.Service, sessionID string) (*Applier, error) {
+	rootDir, err := git.GetGitRoot()
+	if err != nil {
+		return nil, err
+	}
+	return &Applier{history: historyService, sessionID: sessionID, rootDir: rootDir}, nil
+}
+
+// Apply writes a verified patch to disk, recording pre- and post-change versions
+// On error nothing stays applied: files already changed are restored from their backups
+func (a *Applier) Apply(ctx context.Context, p *Patch) (*Applied, error) {
+	if !p.Usable() {
+		return nil, fmt.Errorf("patch is %s; refusing to apply", p.Status)
+	}
+
+	// The tree may have moved since verification (e.g. another patch was applied)
+	if err := git.ApplyInDir(a.rootDir, p.Diff, "--check"); err != nil {
+		return nil, err
+	}
+
+	applied := &Applied{Patch: p, Backups: make([]Backup, 0, len(p.Files))}
+	for _, path := range p.Files {
+		backup, err := a.backup(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		applied.Backups = append(applied.Backups, backup)
+	}
+
+	if err := git.ApplyInDir(a.rootDir, p.Diff); err != nil {
+		return nil, err
+	}
+
+	for _, backup := range applied.Backups {
+		if err := a.recordCurrent(ctx, backup.Path); err != nil {
+			return nil, errors.Join(err, a.restoreAll(ctx, applied))
+		}
+	}
+	return applied, nil
+}
+
+// Undo restores every file touched by an applied patch to its pre-change content
+func (a *Applier) Undo(ctx context.Context, applied *Applied) error {
+	for _, backup := range applied.Backups {
+		if err := a.restore(ctx, backup); err != nil {
+			return err
+		}
+		if !backup.Existed {
+			continue
+		}
+		if err := a.recordCurrent(ctx, backup.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreAll restores every backup without recording versions, continuing past failures
+func (a *Applier) restoreAll(ctx context.Context, applied *Applied) error {
+	var errs []error
+	for _, backup := range applied.Backups {
+		errs = append(errs, a.restore(ctx, backup))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to roll back the patch: %w", err)
+	}
+	return nil
+}
+
+// restore writes a file's pre-change content back (or removes a file the patch created)
+func (a *Applier) restore(ctx context.Context, backup Backup) error {
+	if !backup.Existed {
+		if err := os.Remove(backup.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove %s: %w", backup.Path, err)
+		}
+		return nil
+	}
+
+	version, err := a.history.Get(ctx, backup.VersionID)
+	if err != nil {
+		return fmt.Errorf("failed to load backup of %s: %w", backup.Path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(backup.Path), 0o755); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", backup.Path, err)
+	}
+	if err := os.WriteFile(backup.Path, []byte(version.Content), fileMode(backup.Path)); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", backup.Path, err)
+	}
+	return nil
+}
+
+// backup versions the current content of a repo-relative path
+func (a *Applier) backup(ctx context.Context, path string) (Backup, error) {
+	if !filepath.IsLocal(path) {
+		return Backup{}, fmt.Errorf("refusing path outside repository: %s", path)
+	}
+
+	absPath := filepath.Join(a.rootDir, path)
+	content, err := os.ReadFile(absPath)
+	existed := err == nil
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Backup{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	version, err := a.history.CreateVersion(ctx, a.sessionID, absPath, string(content))
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to record version of %s: %w", path, err)
+	}
+	return Backup{Path: absPath, VersionID: version.ID, Existed: existed}, nil
+}
+
+// recordCurrent versions a file after it changed (deleted files record empty content)
+func (a *Applier) recordCurrent(ctx context.Context, absPath string) error {
+	content, err := os.ReadFile(absPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", absPath, err)
+	}
+	if _, err := a.history.CreateVersion(ctx, a.sessionID, absPath, string(content)); err != nil {
+		return fmt.Errorf("failed to record version of %s: %w", absPath, err)
+	}
+	return nil
+}
+
+// fileMode keeps the existing permissions of a file, defaulting to 0644
+func fileMode(path string) fs.FileMode {
+	if info, err := os.Stat(path); err == nil {
+		return info.Mode().Perm()
+	}
+	return 0o644
+}
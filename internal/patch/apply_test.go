@@ -0,0 +1,134 @@
+package patch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trankhanh040147/revcli/internal/history"
+)
+
+// fakeHistory keeps file versions in memory; failAfter > 0 fails every CreateVersion after that many
+type fakeHistory struct {
+	history.Service
+	versions  map[string]history.File
+	failAfter int
+}
+
+func (h *fakeHistory) CreateVersion(_ context.Context, sessionID, path, content string) (history.File, error) {
+	if h.failAfter > 0 && len(h.versions) >= h.failAfter {
+		return history.File{}, errors.New("database is locked")
+	}
+	file := history.File{ID: fmt.Sprintf("v%d", len(h.versions)), SessionID: sessionID, Path: path, Content: content}
+	h.versions[file.ID] = file
+	return file, nil
+}
+
+func (h *fakeHistory) Get(_ context.Context, id string) (history.File, error) {
+	file, ok := h.versions[id]
+	if !ok {
+		return history.File{}, fmt.Errorf("version %s not found", id)
+	}
+	return file, nil
+}
+
+// newTestApplier creates an applier for a temporary git repository holding files
+func newTestApplier(t *testing.T, files map[string]string) (*Applier, *fakeHistory) {
+	t.Helper()
+
+	rootDir := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", "-q", rootDir).Run())
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(rootDir, filepath.Dir(path)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(rootDir, path), []byte(content), 0o644))
+	}
+	historyService := &fakeHistory{versions: make(map[string]history.File)}
+	return &Applier{history: historyService, sessionID: "session", rootDir: rootDir}, historyService
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestApplierApplyAndUndo(t *testing.T) {
+	t.Parallel()
+
+	applier, _ := newTestApplier(t, map[string]string{"calc/calc.go": "package calc\n\nconst a = 1\n"})
+	path := filepath.Join(applier.rootDir, "calc/calc.go")
+	p := &Patch{
+		Diff:   "--- a/calc/calc.go\n+++ b/calc/calc.go\n@@ -3 +3 @@\n-const a = 1\n+const a = 2\n",
+		Files:  []string{"calc/calc.go"},
+		Status: StatusValid,
+	}
+
+	applied, err := applier.Apply(t.Context(), p)
+	require.NoError(t, err)
+	require.Equal(t, "package calc\n\nconst a = 2\n", readFile(t, path))
+	require.Equal(t, []Backup{{Path: path, VersionID: "v0", Existed: true}}, applied.Backups)
+
+	require.NoError(t, applier.Undo(t.Context(), applied))
+	require.Equal(t, "package calc\n\nconst a = 1\n", readFile(t, path))
+}
+
+func TestApplierApplyFailsWhenTreeMoved(t *testing.T) {
+	t.Parallel()
+
+	applier, historyService := newTestApplier(t, map[string]string{"calc/calc.go": "package calc\n\nconst a = 3\n"})
+	p := &Patch{
+		Diff:   "--- a/calc/calc.go\n+++ b/calc/calc.go\n@@ -3 +3 @@\n-const a = 1\n+const a = 2\n",
+		Files:  []string{"calc/calc.go"},
+		Status: StatusValid,
+	}
+
+	applied, err := applier.Apply(t.Context(), p)
+	require.ErrorContains(t, err, "git apply failed")
+	require.Nil(t, applied)
+	require.Empty(t, historyService.versions)
+	require.Equal(t, "package calc\n\nconst a = 3\n", readFile(t, filepath.Join(applier.rootDir, "calc/calc.go")))
+
+	_, err = applier.Apply(t.Context(), &Patch{Status: StatusInvalid})
+	require.ErrorContains(t, err, "refusing to apply")
+}
+
+func TestApplierUndoDeletesNewFile(t *testing.T) {
+	t.Parallel()
+
+	applier, _ := newTestApplier(t, nil)
+	path := filepath.Join(applier.rootDir, "internal/patch/zz_generated_example.go")
+
+	applied, err := applier.Apply(t.Context(), &Patch{Diff: newFilePatch, Files: []string{"internal/patch/zz_generated_example.go"}, Status: StatusValid})
+	require.NoError(t, err)
+	require.Equal(t, "package patch\n\nconst example = 1\n", readFile(t, path))
+	require.False(t, applied.Backups[0].Existed)
+
+	require.NoError(t, applier.Undo(t.Context(), applied))
+	require.NoFileExists(t, path)
+}
+
+func TestApplierApplyRestoresFilesWhenRecordingFails(t *testing.T) {
+	t.Parallel()
+
+	applier, historyService := newTestApplier(t, map[string]string{"calc/calc.go": "package calc\n\nconst a = 1\n"})
+	// The backup is recorded; recording the patched content fails
+	historyService.failAfter = 1
+	p := &Patch{
+		Diff:   "--- a/calc/calc.go\n+++ b/calc/calc.go\n@@ -3 +3 @@\n-const a = 1\n+const a = 2\n",
+		Files:  []string{"calc/calc.go"},
+		Status: StatusValid,
+	}
+
+	applied, err := applier.Apply(t.Context(), p)
+	require.ErrorContains(t, err, "database is locked")
+	require.Nil(t, applied)
+	require.Equal(t, "package calc\n\nconst a = 1\n", readFile(t, filepath.Join(applier.rootDir, "calc/calc.go")))
+}
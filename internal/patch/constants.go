@@ -0,0 +1,20 @@
+package patch
+
+// categoryKey is the fence info attribute carrying a patch category
+const categoryKey = "category="
+
+// Annotation labels shown above patches in the review output
+const (
+	LabelVerified = "*✅ Patch verified (applies cleanly)*"
+	LabelRepaired = "*🔧 Patch repaired after failing verification*"
+	LabelInvalid  = "> ⚠️ **Invalid patch — do not apply.**"
+)
+
+// RepairPromptTemplate asks the model to fix patches that failed verification
+// Args: patch count, numbered patch list
+const RepairPromptTemplate = `The following suggested patches from your review failed verification against the current working tree.
+Fix each one so that it applies cleanly with ` + "`git apply`" + ` and, for Go files, parses and is gofmt-formatted.
+Reply with exactly %d ` + "```diff" + ` code blocks, one per patch, in the same order, and nothing else.
+Keep the --- a/<path> and +++ b/<path> headers relative to the repository root.
+
+%s`
@@ -0,0 +1,124 @@
+// Package patch extracts unified diff suggestions from review responses
+// and verifies them against the working tree before they are shown or applied.
+package patch
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// Status is the verification state of a suggested patch
+type Status int
+
+const (
+	StatusUnverified Status = iota
+	StatusValid
+	StatusRepaired
+	StatusInvalid
+)
+
+// String returns the string representation of Status
+func (s Status) String() string {
+	switch s {
+	case StatusValid:
+		return "verified"
+	case StatusRepaired:
+		return "repaired"
+	case StatusInvalid:
+		return "invalid"
+	default:
+		return "unverified"
+	}
+}
+
+// Patch is a single unified diff suggested by the reviewer
+type Patch struct {
+	// Block is the fenced code block exactly as it appeared in the response
+	Block string
+	// Diff is the unified diff body
+	Diff string
+	// Category is the optional category from the fence info string (```diff category=typo)
+	Category string
+	// Files lists the repo-relative paths touched by the patch
+	Files []string
+	// Status is the verification result
+	Status Status
+	// Problems explains why verification failed
+	Problems []string
+}
+
+// Usable reports whether the patch passed verification (directly or after repair)
+func (p *Patch) Usable() bool {
+	return p.Status == StatusValid || p.Status == StatusRepaired
+}
+
+// diffBlockRe matches ```diff fenced blocks, capturing the info string and body
+var diffBlockRe = regexp.MustCompile("(?ms)^```diff([^\\n]*)\\n(.*?)^```[ \\t]*$")
+
+// Extract finds every unified diff patch in a review response
+// Blocks without file headers or hunks are ignored (plain diff snippets, not patches)
+func Extract(response string) []*Patch {
+	matches := diffBlockRe.FindAllStringSubmatch(response, -1)
+
+	patches := lo.FilterMap(matches, func(m []string, _ int) (*Patch, bool) {
+		files := parseFiles(m[2])
+		if len(files) == 0 || !strings.Contains(m[2], "\n@@") {
+			return nil, false
+		}
+		return &Patch{
+			Block:    m[0],
+			Diff:     m[2],
+			Category: parseCategory(m[1]),
+			Files:    files,
+		}, true
+	})
+
+	return patches
+}
+
+// parseFiles extracts target paths from the ---/+++ headers of a unified diff
+func parseFiles(diff string) []string {
+	var files []string
+	var oldPath string
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			oldPath = headerPath(line, "--- ", "a/")
+		case strings.HasPrefix(line, "+++ "):
+			path := headerPath(line, "+++ ", "b/")
+			if path == "/dev/null" {
+				// Deleted file: target is the old path
+				path = oldPath
+			}
+			if path != "" && path != "/dev/null" {
+				files = append(files, path)
+			}
+		}
+	}
+
+	return lo.Uniq(files)
+}
+
+// headerPath strips the marker, git prefix and optional timestamp from a file header line
+func headerPath(line, marker, gitPrefix string) string {
+	path := strings.TrimPrefix(line, marker)
+	if idx := strings.Index(path, "\t"); idx >= 0 {
+		path = path[:idx]
+	}
+	path = strings.TrimSpace(path)
+	return strings.TrimPrefix(path, gitPrefix)
+}
+
+// parseCategory reads category=<value> from a fence info string
+func parseCategory(info string) string {
+	field, ok := lo.Find(strings.Fields(info), func(f string) bool {
+		return strings.HasPrefix(f, categoryKey)
+	})
+	if !ok {
+		return ""
+	}
+	return strings.ToLower(strings.Trim(strings.TrimPrefix(field, categoryKey), `"'`))
+}
@@ -0,0 +1,110 @@
+package patch
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const newFilePatch = "--- /dev/null\n+++ b/internal/patch/zz_generated_example.go\n@@ -0,0 +1,3 @@\n+package patch\n+\n+const example = 1\n"
+
+func TestExtract(t *testing.T) {
+	t.Parallel()
+
+	response := strings.Join([]string{
+		"### 💡 Code Suggestions",
+		"```diff category=Typo",
+		"--- a/main.go",
+		"+++ b/main.go",
+		"@@ -1,1 +1,1 @@",
+		"-pakage main",
+		"+package main",
+		"```",
+		"Plain snippet, not a patch:",
+		"```diff",
+		"-old",
+		"+new",
+		"```",
+	}, "\n")
+
+	patches := Extract(response)
+	require.Len(t, patches, 1)
+	require.Equal(t, "typo", patches[0].Category)
+	require.Equal(t, []string{"main.go"}, patches[0].Files)
+	require.Equal(t, StatusUnverified, patches[0].Status)
+	require.True(t, strings.HasPrefix(patches[0].Block, "```diff category=Typo\n"))
+}
+
+func TestParseFiles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		diff string
+		want []string
+	}{
+		{"modified", "--- a/x.go\t2024-01-01\n+++ b/x.go\t2024-01-01\n@@ -1 +1 @@\n", []string{"x.go"}},
+		{"new file", "--- /dev/null\n+++ b/new.go\n@@ -0,0 +1 @@\n", []string{"new.go"}},
+		{"deleted file", "--- a/old.go\n+++ /dev/null\n@@ -1 +0,0 @@\n", []string{"old.go"}},
+		{"no headers", "@@ -1 +1 @@\n-a\n+b\n", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, parseFiles(tt.diff))
+		})
+	}
+}
+
+func TestCheckGoSource(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, CheckGoSource("ok.go", []byte("package x\n\nconst a = 1\n"), nil))
+	require.Len(t, CheckGoSource("bad.go", []byte("package x\n\nfunc {\n"), nil), 1)
+	require.Equal(t,
+		[]string{"ugly.go: not gofmt-formatted"},
+		CheckGoSource("ugly.go", []byte("package x\nconst a   = 1\n"), nil),
+	)
+	require.Equal(t,
+		[]string{"ugly.go: not gofmt-formatted"},
+		CheckGoSource("ugly.go", []byte("package x\nconst a   = 1\n"), []byte("package x\n\nconst a = 1\n")),
+	)
+	// A file that was already unformatted isn't held against the patch
+	require.Empty(t, CheckGoSource("ugly.go", []byte("package x\nconst a   = 2\n"), []byte("package x\nconst a   = 1\n")))
+}
+
+func TestAnnotateAndSummary(t *testing.T) {
+	t.Parallel()
+
+	valid := &Patch{Block: "```diff\nA\n```", Status: StatusValid}
+	invalid := &Patch{Block: "```diff\nB\n```", Status: StatusInvalid, Problems: []string{"git apply failed: corrupt patch"}}
+	response := "intro\n" + valid.Block + "\n" + invalid.Block + "\n"
+
+	annotated := Annotate(response, []*Patch{valid, invalid})
+	require.Contains(t, annotated, LabelVerified+"\n\n"+valid.Block)
+	require.Contains(t, annotated, LabelInvalid+"\n> - git apply failed: corrupt patch\n\n"+invalid.Block)
+	require.Equal(t, "Patches: 1 verified, 0 repaired, 1 invalid", Summary([]*Patch{valid, invalid}))
+	require.Empty(t, Summary(nil))
+}
+
+func TestVerifyAllRepairsOnce(t *testing.T) {
+	t.Parallel()
+
+	broken := &Patch{Diff: "--- a/does/not/exist.go\n+++ b/does/not/exist.go\n@@ -1 +1 @@\n-a\n+b\n", Files: []string{"does/not/exist.go"}}
+
+	calls := 0
+	repair := func(_ context.Context, prompt string) (string, error) {
+		calls++
+		require.Contains(t, prompt, "### Patch 1")
+		return "```diff\n" + newFilePatch + "```\n", nil
+	}
+
+	require.NoError(t, VerifyAll(t.Context(), []*Patch{broken}, repair))
+	require.Equal(t, 1, calls)
+	require.Equal(t, StatusRepaired, broken.Status)
+	require.Equal(t, []string{"internal/patch/zz_generated_example.go"}, broken.Files)
+	require.Empty(t, broken.Problems)
+}
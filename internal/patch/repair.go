@@ -0,0 +1,74 @@
+package patch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// RepairFunc sends a prompt to the reviewer model and returns its text response
+type RepairFunc func(ctx context.Context, prompt string) (string, error)
+
+// VerifyAll verifies every patch, then gives the model one attempt to repair the invalid ones
+// - repair may be nil to only verify
+// - patches that are still invalid after the attempt stay flagged
+func VerifyAll(ctx context.Context, patches []*Patch, repair RepairFunc) error {
+	for _, p := range patches {
+		Verify(p)
+	}
+
+	invalid := lo.Filter(patches, func(p *Patch, _ int) bool { return p.Status == StatusInvalid })
+	if len(invalid) == 0 || repair == nil {
+		return nil
+	}
+
+	response, err := repair(ctx, BuildRepairPrompt(invalid))
+	if err != nil {
+		return fmt.Errorf("failed to repair patches: %w", err)
+	}
+
+	// Repairs are matched by position; a mismatched count can't be mapped safely
+	repaired := Extract(response)
+	if len(repaired) != len(invalid) {
+		return nil
+	}
+
+	for i, original := range invalid {
+		candidate := repaired[i]
+		Verify(candidate)
+		if candidate.Status != StatusValid {
+			continue
+		}
+		original.Diff = candidate.Diff
+		original.Files = candidate.Files
+		original.Problems = nil
+		original.Status = StatusRepaired
+	}
+	return nil
+}
+
+// BuildRepairPrompt lists the invalid patches with their problems
+func BuildRepairPrompt(invalid []*Patch) string {
+	var builder strings.Builder
+	for i, p := range invalid {
+		builder.WriteString(fmt.Sprintf("### Patch %d\n\nProblems:\n", i+1))
+		for _, problem := range p.Problems {
+			builder.WriteString(fmt.Sprintf("- %s\n", problem))
+		}
+		builder.WriteString("\n")
+		builder.WriteString(fence(p))
+		builder.WriteString("\n\n")
+	}
+	return fmt.Sprintf(RepairPromptTemplate, len(invalid), builder.String())
+}
+
+// fence renders a patch as a ```diff block, keeping its category
+func fence(p *Patch) string {
+	info := ""
+	if p.Category != "" {
+		info = " " + categoryKey + p.Category
+	}
+	return "```diff" + info + "\n" + strings.TrimRight(p.Diff, "\n") + "\n```"
+}
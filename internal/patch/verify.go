@@ -0,0 +1,159 @@
+package patch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/git"
+)
+
+// Verify checks a patch against the working tree and records the result on it
+// - must pass `git apply --check`
+// - Go files must parse with go/parser and be gofmt-formatted after applying (when they were before)
+func Verify(p *Patch) {
+	p.Problems = nil
+
+	if err := git.ApplyCheck(p.Diff); err != nil {
+		p.Problems = append(p.Problems, err.Error())
+	} else if hasGoFiles(p.Files) {
+		p.Problems = append(p.Problems, verifyGoFiles(p)...)
+	}
+
+	p.Status = lo.Ternary(len(p.Problems) == 0, StatusValid, StatusInvalid)
+}
+
+// hasGoFiles reports whether any path is a Go source file
+func hasGoFiles(files []string) bool {
+	return lo.SomeBy(files, isGoFile)
+}
+
+func isGoFile(path string) bool {
+	return strings.HasSuffix(path, ".go")
+}
+
+// verifyGoFiles previews the patched Go files and validates their syntax and formatting
+func verifyGoFiles(p *Patch) []string {
+	patched, err := Preview(p)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	rootDir, err := git.GetGitRoot()
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var problems []string
+	for _, path := range lo.Filter(p.Files, func(f string, _ int) bool { return isGoFile(f) }) {
+		src, ok := patched[path]
+		if !ok {
+			// Deleted by the patch
+			continue
+		}
+		original, err := os.ReadFile(filepath.Join(rootDir, path))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			problems = append(problems, fmt.Sprintf("failed to read %s: %v", path, err))
+			continue
+		}
+		problems = append(problems, CheckGoSource(path, src, original)...)
+	}
+	return problems
+}
+
+// CheckGoSource validates Go source with go/parser and gofmt
+// Formatting is only required when the original file (nil for a new file) was gofmt-formatted,
+// so a patch isn't blamed for a file that was already unformatted
+func CheckGoSource(path string, src, original []byte) []string {
+	if _, err := parser.ParseFile(token.NewFileSet(), path, src, parser.AllErrors); err != nil {
+		return []string{fmt.Sprintf("%s: does not parse: %v", path, err)}
+	}
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: gofmt failed: %v", path, err)}
+	}
+	if !bytes.Equal(formatted, src) && isFormatted(original) {
+		return []string{fmt.Sprintf("%s: not gofmt-formatted", path)}
+	}
+	return nil
+}
+
+// isFormatted reports whether Go source is gofmt-formatted (nil, a new file, counts as formatted)
+func isFormatted(src []byte) bool {
+	if src == nil {
+		return true
+	}
+	formatted, err := format.Source(src)
+	return err == nil && bytes.Equal(formatted, src)
+}
+
+// Preview applies a patch to a scratch copy of the touched files and returns their new content
+// Files deleted by the patch are absent from the result
+func Preview(p *Patch) (map[string][]byte, error) {
+	rootDir, err := git.GetGitRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	scratch, err := os.MkdirTemp("", "revcli-patch-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scratch dir: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+
+	for _, path := range p.Files {
+		if err := copyIntoScratch(rootDir, scratch, path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := git.ApplyInDir(scratch, p.Diff); err != nil {
+		return nil, err
+	}
+
+	patched := make(map[string][]byte, len(p.Files))
+	for _, path := range p.Files {
+		content, err := os.ReadFile(filepath.Join(scratch, path))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read patched %s: %w", path, err)
+		}
+		patched[path] = content
+	}
+	return patched, nil
+}
+
+// copyIntoScratch mirrors a repo file into the scratch dir (missing files are new files)
+func copyIntoScratch(rootDir, scratch, path string) error {
+	if !filepath.IsLocal(path) {
+		return fmt.Errorf("refusing path outside repository: %s", path)
+	}
+
+	content, err := os.ReadFile(filepath.Join(rootDir, path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	target := filepath.Join(scratch, path)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to create scratch dir for %s: %w", path, err)
+	}
+	if err := os.WriteFile(target, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write scratch copy of %s: %w", path, err)
+	}
+	return nil
+}
@@ -49,6 +49,8 @@ type Service interface {
 	Deny(permission PermissionRequest)
 	Request(opts CreatePermissionRequest) bool
 	AutoApproveSession(sessionID string)
+	// AutoApproveSessionTools approves the requests of one session for the named tools only
+	AutoApproveSessionTools(sessionID string, toolNames ...string)
 	SetSkipRequests(skip bool)
 	SkipRequests() bool
 	SubscribeNotifications(ctx context.Context) <-chan pubsub.Event[PermissionNotification]
@@ -63,6 +65,7 @@ type permissionService struct {
 	sessionPermissionsMu  sync.RWMutex
 	pendingRequests       *csync.Map[string, chan bool]
 	autoApproveSessions   map[string]bool
+	autoApproveTools      map[string][]string
 	autoApproveSessionsMu sync.RWMutex
 	skip                  bool
 	allowedTools          []string
@@ -141,7 +144,7 @@ func (s *permissionService) Request(opts CreatePermissionRequest) bool {
 	}
 
 	s.autoApproveSessionsMu.RLock()
-	autoApprove := s.autoApproveSessions[opts.SessionID]
+	autoApprove := s.autoApproveSessions[opts.SessionID] || slices.Contains(s.autoApproveTools[opts.SessionID], opts.ToolName)
 	s.autoApproveSessionsMu.RUnlock()
 
 	if autoApprove {
@@ -208,6 +211,12 @@ func (s *permissionService) AutoApproveSession(sessionID string) {
 	s.autoApproveSessionsMu.Unlock()
 }
 
+func (s *permissionService) AutoApproveSessionTools(sessionID string, toolNames ...string) {
+	s.autoApproveSessionsMu.Lock()
+	s.autoApproveTools[sessionID] = append(s.autoApproveTools[sessionID], toolNames...)
+	s.autoApproveSessionsMu.Unlock()
+}
+
 func (s *permissionService) SubscribeNotifications(ctx context.Context) <-chan pubsub.Event[PermissionNotification] {
 	return s.notificationBroker.Subscribe(ctx)
 }
@@ -227,6 +236,7 @@ func NewPermissionService(workingDir string, skip bool, allowedTools []string) S
 		workingDir:          workingDir,
 		sessionPermissions:  make([]PermissionRequest, 0),
 		autoApproveSessions: make(map[string]bool),
+		autoApproveTools:    make(map[string][]string),
 		skip:                skip,
 		allowedTools:        allowedTools,
 		pendingRequests:     csync.NewMap[string, chan bool](),
@@ -94,6 +94,30 @@ func TestPermissionService_SkipMode(t *testing.T) {
 	}
 }
 
+func TestPermissionService_AutoApproveSessionTools(t *testing.T) {
+	service := NewPermissionService("/tmp", false, []string{})
+	service.AutoApproveSessionTools("review", "view", "ls")
+
+	assert.True(t, service.Request(CreatePermissionRequest{SessionID: "review", ToolName: "view", Action: "read", Path: "/tmp"}))
+
+	// Other tools of the session, and the same tool in other sessions, still ask
+	events := service.Subscribe(t.Context())
+	for _, req := range []CreatePermissionRequest{
+		{SessionID: "review", ToolName: "bash", Action: "execute", Path: "/tmp"},
+		{SessionID: "coder", ToolName: "view", Action: "read", Path: "/tmp"},
+	} {
+		var result bool
+		var wg sync.WaitGroup
+		wg.Go(func() {
+			result = service.Request(req)
+		})
+		event := <-events
+		service.Deny(event.Payload)
+		wg.Wait()
+		assert.False(t, result, req.ToolName)
+	}
+}
+
 func TestPermissionService_SequentialProperties(t *testing.T) {
 	t.Run("Sequential permission requests with persistent grants", func(t *testing.T) {
 		service := NewPermissionService("/tmp", false, []string{})
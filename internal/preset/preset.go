@@ -381,6 +381,62 @@ func DeleteSystemPrompt() error {
 	return nil
 }
 
+// GetRulesDir returns the directory of language rule pack overrides
+func GetRulesDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "revcli", "presets", "rules"), nil
+}
+
+// LoadRules loads the rule pack override with a name (e.g. go, typescript) from the rules directory
+// Returns nil when there is none; its prompt extends the built-in pack, or replaces it when replace is set
+func LoadRules(name string) (*Preset, error) {
+	rulesDir, err := GetRulesDir()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(rulesDir, name+".yaml"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s rules: %w", name, err)
+	}
+
+	var rules Preset
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse %s rules: %w", name, err)
+	}
+	if rules.Name == "" {
+		rules.Name = name
+	}
+	return &rules, nil
+}
+
+// ListRulesNames returns the names of the rule pack overrides in the rules directory
+func ListRulesNames() ([]string, error) {
+	rulesDir, err := GetRulesDir()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := os.ReadDir(rulesDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.FilterMap(files, func(file os.DirEntry, _ int) (string, bool) {
+		name, ok := strings.CutSuffix(file.Name(), ".yaml")
+		return name, ok && !file.IsDir()
+	}), nil
+}
+
 // findSimilarPresets finds preset names similar to the given name using Levenshtein distance
 // threshold: maximum edit distance to consider a preset as similar (lower = more strict)
 func findSimilarPresets(name string, threshold int) []string {
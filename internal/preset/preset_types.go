@@ -8,6 +8,8 @@ type Preset struct {
 	Description string `yaml:"description"`
 	Prompt      string `yaml:"prompt"`
 	Replace     bool   `yaml:"replace,omitempty"` // If true, replace base prompt instead of appending
+	// Template is a text/template for the review's user prompt ("" uses the repository's or the default layout)
+	Template string `yaml:"template,omitempty"`
 }
 
 // MarshalYAML implements custom YAML marshaling to use literal block scalars for multiline prompts
@@ -49,6 +51,14 @@ func (p *Preset) MarshalYAML() (interface{}, error) {
 		)
 	}
 
+	// Add template field with literal style only if set
+	if p.Template != "" {
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "template"},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: p.Template, Style: yaml.LiteralStyle},
+		)
+	}
+
 	// Return root node directly - yaml.Marshal() will wrap it in a document automatically
 	return root, nil
 }
@@ -0,0 +1,178 @@
+package prompt
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// Language is a language detected in a changed file
+type Language string
+
+const (
+	LanguageGo         Language = "go"
+	LanguageTypeScript Language = "typescript"
+	LanguageJavaScript Language = "javascript"
+	LanguagePython     Language = "python"
+	LanguageRust       Language = "rust"
+	LanguageJava       Language = "java"
+	LanguageKotlin     Language = "kotlin"
+	LanguageCSharp     Language = "csharp"
+	LanguageC          Language = "c"
+	LanguageCPP        Language = "cpp"
+	LanguageRuby       Language = "ruby"
+	LanguagePHP        Language = "php"
+	LanguageSwift      Language = "swift"
+	LanguageSQL        Language = "sql"
+	LanguageShell      Language = "shell"
+	LanguageDockerfile Language = "dockerfile"
+	LanguageMakefile   Language = "makefile"
+	LanguageKubernetes Language = "kubernetes"
+	LanguageTerraform  Language = "terraform"
+	LanguageProtobuf   Language = "protobuf"
+	LanguageYAML       Language = "yaml"
+	LanguageJSON       Language = "json"
+	LanguageTOML       Language = "toml"
+	LanguageMarkdown   Language = "markdown"
+	LanguageHTML       Language = "html"
+	LanguageCSS        Language = "css"
+)
+
+// extensionLanguages maps file extensions to their language
+var extensionLanguages = map[string]Language{
+	".go":         LanguageGo,
+	".ts":         LanguageTypeScript,
+	".tsx":        LanguageTypeScript,
+	".mts":        LanguageTypeScript,
+	".cts":        LanguageTypeScript,
+	".js":         LanguageJavaScript,
+	".jsx":        LanguageJavaScript,
+	".mjs":        LanguageJavaScript,
+	".cjs":        LanguageJavaScript,
+	".py":         LanguagePython,
+	".pyi":        LanguagePython,
+	".rs":         LanguageRust,
+	".java":       LanguageJava,
+	".kt":         LanguageKotlin,
+	".kts":        LanguageKotlin,
+	".cs":         LanguageCSharp,
+	".c":          LanguageC,
+	".h":          LanguageC,
+	".cc":         LanguageCPP,
+	".cpp":        LanguageCPP,
+	".cxx":        LanguageCPP,
+	".hpp":        LanguageCPP,
+	".rb":         LanguageRuby,
+	".php":        LanguagePHP,
+	".swift":      LanguageSwift,
+	".sql":        LanguageSQL,
+	".sh":         LanguageShell,
+	".bash":       LanguageShell,
+	".zsh":        LanguageShell,
+	".dockerfile": LanguageDockerfile,
+	".mk":         LanguageMakefile,
+	".tf":         LanguageTerraform,
+	".tfvars":     LanguageTerraform,
+	".proto":      LanguageProtobuf,
+	".yaml":       LanguageYAML,
+	".yml":        LanguageYAML,
+	".json":       LanguageJSON,
+	".toml":       LanguageTOML,
+	".md":         LanguageMarkdown,
+	".html":       LanguageHTML,
+	".css":        LanguageCSS,
+	".scss":       LanguageCSS,
+}
+
+// shebangLanguages maps interpreters in a #! line to their language
+var shebangLanguages = map[string]Language{
+	"sh":     LanguageShell,
+	"bash":   LanguageShell,
+	"zsh":    LanguageShell,
+	"dash":   LanguageShell,
+	"python": LanguagePython,
+	"node":   LanguageJavaScript,
+	"ruby":   LanguageRuby,
+}
+
+// fenceLanguages are the markdown code fence identifiers that differ from the language name
+var fenceLanguages = map[Language]string{
+	LanguageShell:      "bash",
+	LanguageKubernetes: "yaml",
+	LanguageTerraform:  "hcl",
+	LanguageProtobuf:   "proto",
+}
+
+// kubernetesAPIVersion and kubernetesKind match the top-level keys every Kubernetes manifest has
+var (
+	kubernetesAPIVersion = regexp.MustCompile(`(?m)^apiVersion:\s*\S`)
+	kubernetesKind       = regexp.MustCompile(`(?m)^kind:\s*[A-Z]\w*`)
+)
+
+// DetectLanguage detects the language of a file from its name, then its content
+// (a #! line for scripts without an extension, apiVersion/kind for Kubernetes manifests); "" when unknown
+func DetectLanguage(filePath, content string) Language {
+	name := path.Base(filePath)
+	switch {
+	case name == "Dockerfile" || name == "Containerfile" || strings.HasPrefix(name, "Dockerfile."):
+		return LanguageDockerfile
+	case name == "Makefile" || name == "GNUmakefile":
+		return LanguageMakefile
+	}
+
+	language, ok := extensionLanguages[strings.ToLower(path.Ext(name))]
+	if !ok {
+		return shebangLanguage(content)
+	}
+	if language == LanguageYAML && kubernetesAPIVersion.MatchString(content) && kubernetesKind.MatchString(content) {
+		return LanguageKubernetes
+	}
+	return language
+}
+
+// DetectLanguages returns the languages of the files (path -> content), sorted
+func DetectLanguages(files map[string]string) []Language {
+	var languages []Language
+	for filePath, content := range files {
+		if language := DetectLanguage(filePath, content); language != "" && !slices.Contains(languages, language) {
+			languages = append(languages, language)
+		}
+	}
+	slices.Sort(languages)
+	return languages
+}
+
+// Fence returns the markdown code fence identifier of the language
+func (l Language) Fence() string {
+	if fence, ok := fenceLanguages[l]; ok {
+		return fence
+	}
+	return string(l)
+}
+
+// shebangLanguage detects a script's language from its #! line
+func shebangLanguage(content string) Language {
+	line, _, _ := strings.Cut(content, "\n")
+	interpreter, ok := strings.CutPrefix(line, "#!")
+	if !ok {
+		return ""
+	}
+	fields := strings.Fields(interpreter)
+	if len(fields) == 0 {
+		return ""
+	}
+	program := path.Base(fields[0])
+	// #!/usr/bin/env [-S] bash names the interpreter after env's flags
+	if program == "env" {
+		args := lo.Filter(fields[1:], func(f string, _ int) bool { return !strings.HasPrefix(f, "-") })
+		if len(args) == 0 {
+			return ""
+		}
+		program = path.Base(args[0])
+	}
+	// python3.12 -> python
+	return shebangLanguages[strings.TrimRight(program, "0123456789.")]
+}
@@ -0,0 +1,65 @@
+package prompt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path    string
+		content string
+		want    Language
+	}{
+		{"internal/app/app.go", "", LanguageGo},
+		{"web/src/App.tsx", "", LanguageTypeScript},
+		{"web/vite.config.mjs", "", LanguageJavaScript},
+		{"scripts/release", "#!/usr/bin/env bash\nset -e\n", LanguageShell},
+		{"scripts/gen", "#!/usr/bin/env -S python3.12 -u\n", LanguagePython},
+		{"scripts/notes", "no shebang\n", ""},
+		{"Dockerfile", "", LanguageDockerfile},
+		{"build/Dockerfile.dev", "", LanguageDockerfile},
+		{"migrations/001_init.SQL", "", LanguageSQL},
+		{"deploy/app.yaml", "apiVersion: apps/v1\nkind: Deployment\n", LanguageKubernetes},
+		{".github/workflows/ci.yml", "name: CI\non: push\n", LanguageYAML},
+		{"Makefile", "", LanguageMakefile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, DetectLanguage(tt.path, tt.content))
+		})
+	}
+}
+
+func TestRulePacks(t *testing.T) {
+	t.Parallel()
+
+	languages := DetectLanguages(map[string]string{
+		"main.go":       "package main\n",
+		"web/app.ts":    "",
+		"web/legacy.js": "",
+		"deploy/a.yaml": "apiVersion: v1\nkind: Service\n",
+		"README.md":     "",
+		"lib/tool.rb":   "",
+	})
+	require.Equal(t, []Language{LanguageGo, LanguageJavaScript, LanguageKubernetes, LanguageMarkdown, LanguageRuby, LanguageTypeScript}, languages)
+
+	// TS and JS share a pack; languages without a built-in pack keep their own name
+	names := RulePackNames(languages)
+	require.Equal(t, []string{"go", "typescript", "kubernetes", "markdown", "ruby"}, names)
+
+	goPack, ok := BuiltInRulePack("go")
+	require.True(t, ok)
+	_, ok = BuiltInRulePack("ruby")
+	require.False(t, ok)
+
+	rules := BuildLanguageRules([]RulePack{goPack, {Title: "Ruby", Rules: "- Freeze string literals.\n"}})
+	require.Contains(t, rules, "## "+LanguageRulesTitle)
+	require.Contains(t, rules, "### Go\n\n- **Goroutine Leaks**")
+	require.Contains(t, rules, "### Ruby\n\n- Freeze string literals.\n")
+	require.Empty(t, BuildLanguageRules(nil))
+}
@@ -0,0 +1,211 @@
+package prompt
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// RepoTemplateFile is the repository's review prompt template, relative to the repository root
+const RepoTemplateFile = ".revcli/prompt.tmpl"
+
+// MaxFileChars is the length files are truncated to by the truncate template function
+const MaxFileChars = 50000
+
+// fence is a markdown code fence, which can't appear in a raw string literal
+const fence = "```"
+
+// DefaultReviewTemplate is the built-in layout of the review prompt (see ReviewData for its fields)
+// The large, static file context and sections come first so the prompt prefix stays the same between runs (and cacheable by Claude models)
+const DefaultReviewTemplate = `## Code Review Request
+
+Please review the following code changes.
+
+{{ if .Files -}}
+### Full File Context
+
+Below are the complete contents of the modified files for additional context:
+
+{{ range .Files -}}
+{{ if .Pruned -}}
+#### File: ` + "`{{ .Path }}`" + ` (Pruned)
+
+*Summary: {{ .Summary }}*
+
+{{ else if .Attached -}}
+#### File: ` + "`{{ .Path }}`" + ` (attached below)
+
+{{ else -}}
+#### File: ` + "`{{ .Path }}`" + `
+
+` + fence + `{{ .Language }}
+{{ truncate .Content }}
+` + fence + `
+
+{{ end -}}
+{{ end -}}
+{{ end -}}
+{{ range .Sections -}}
+### {{ .Title }}
+
+{{ trimNewlines .Body }}
+
+{{ end -}}
+### Git Diff (Changes)
+
+` + fence + `diff
+{{ .Diff }}
+` + fence + `
+
+{{ .FindingFormat }}
+{{ .PatchFormat }}
+---
+
+Please provide your code review based on the diff and file context above.
+`
+
+// ReviewData is what a review prompt template renders
+type ReviewData struct {
+	// Diff is the (filtered) git diff
+	Diff string
+	// Files are the changed files sent with the prompt, sorted by path
+	Files []TemplateFile
+	// Sections are extra context (suppressed findings, referenced declarations, ...)
+	Sections []Section
+	// Intent is the user's review intent (nil when none was given)
+	Intent *TemplateIntent
+	// Branch is the reviewed branch; BaseBranch is --base ("" for uncommitted or staged changes)
+	Branch     string
+	BaseBranch string
+	// Commits are the messages of the reviewed commits, oldest first (empty without --base)
+	Commits []string
+	Tokens  TokenStats
+	// FindingFormat and PatchFormat are the format instructions the response is parsed with
+	FindingFormat string
+	PatchFormat   string
+}
+
+// TemplateFile is a changed file in ReviewData
+type TemplateFile struct {
+	Path     string
+	Content  string
+	Language string
+	// Pruned is true when only Summary is sent
+	Pruned  bool
+	Summary string
+	// Attached is true when Content is sent as an attachment after the prompt instead of in it
+	Attached bool
+}
+
+// TemplateIntent is the review intent in ReviewData
+type TemplateIntent struct {
+	CustomInstruction   string
+	FocusAreas          []string
+	NegativeConstraints []string
+	// IssueKeys are the issues the branch name and commit messages link to
+	IssueKeys []string
+}
+
+// TokenStats describes the token budget the context was packed into
+type TokenStats struct {
+	// Used is what the packed context costs; Available is the budget for context
+	Used          int
+	Available     int
+	ContextWindow int
+	Tokenizer     string
+	// Summarized and Dropped count the items that didn't fit in full
+	Summarized int
+	Dropped    int
+}
+
+// templateFuncs are the functions available to review prompt templates
+var templateFuncs = template.FuncMap{
+	"truncate":     truncateFile,
+	"trimNewlines": func(s string) string { return strings.TrimRight(s, "\n") },
+	"join":         func(elems []string, sep string) string { return strings.Join(elems, sep) },
+	"language":     getLanguageFromPath,
+}
+
+// defaultReviewTemplate is the parsed DefaultReviewTemplate
+var defaultReviewTemplate = template.Must(ParseReviewTemplate(DefaultReviewTemplate))
+
+// ParseReviewTemplate parses a review prompt template
+func ParseReviewTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("review").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid review prompt template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// LoadReviewTemplate returns the review prompt template: the preset's template when set,
+// then the repository's RepoTemplateFile, then the default
+func LoadReviewTemplate(rootDir, presetTemplate string) (*template.Template, error) {
+	if strings.TrimSpace(presetTemplate) != "" {
+		return ParseReviewTemplate(presetTemplate)
+	}
+
+	data, err := os.ReadFile(filepath.Join(rootDir, RepoTemplateFile))
+	if os.IsNotExist(err) {
+		return defaultReviewTemplate, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", RepoTemplateFile, err)
+	}
+	tmpl, err := ParseReviewTemplate(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", RepoTemplateFile, err)
+	}
+	return tmpl, nil
+}
+
+// NewReviewData creates the data of a review prompt; files that have a summary in prunedFiles are pruned
+func NewReviewData(rawDiff string, fileContents, prunedFiles map[string]string, sections []Section) ReviewData {
+	files := make([]TemplateFile, 0, len(fileContents))
+	for _, path := range slices.Sorted(maps.Keys(fileContents)) {
+		summary, pruned := prunedFiles[path]
+		files = append(files, TemplateFile{
+			Path:     path,
+			Content:  fileContents[path],
+			Language: getLanguageFromPath(path),
+			Pruned:   pruned,
+			Summary:  summary,
+		})
+	}
+	return ReviewData{
+		Diff:          rawDiff,
+		Files:         files,
+		Sections:      sections,
+		FindingFormat: FindingFormatInstructions,
+		PatchFormat:   PatchFormatInstructions,
+	}
+}
+
+// RenderReview renders review data with a template (nil uses DefaultReviewTemplate)
+func RenderReview(tmpl *template.Template, data ReviewData) (string, error) {
+	if tmpl == nil {
+		tmpl = defaultReviewTemplate
+	}
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return "", fmt.Errorf("failed to render review prompt template: %w", err)
+	}
+	return builder.String(), nil
+}
+
+// truncateFile cuts very large files to MaxFileChars, backing off to a rune boundary
+func truncateFile(content string) string {
+	if len(content) <= MaxFileChars {
+		return content
+	}
+	cut := MaxFileChars
+	for cut > 0 && !utf8.RuneStart(content[cut]) {
+		cut--
+	}
+	return content[:cut] + "\n\n... (file truncated due to size) ...\n"
+}
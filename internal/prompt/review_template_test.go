@@ -0,0 +1,101 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderReviewDefault(t *testing.T) {
+	t.Parallel()
+
+	data := NewReviewData("+func A() {}\n", map[string]string{
+		"b.go": "package b\n",
+		"a.go": "package a\n",
+		"c.go": "package c\n",
+	}, map[string]string{"b.go": "func B()"}, []Section{{Title: "Notes", Body: "note\n\n"}})
+	data.Files[2].Attached = true
+
+	out, err := RenderReview(nil, data)
+	require.NoError(t, err)
+	require.Contains(t, out, "```diff\n+func A() {}\n\n```")
+	require.Contains(t, out, "#### File: `a.go`\n\n```go\npackage a\n\n```")
+	require.Contains(t, out, "#### File: `b.go` (Pruned)\n\n*Summary: func B()*")
+	require.Contains(t, out, "#### File: `c.go` (attached below)\n\n")
+	require.NotContains(t, out, "package c")
+	require.Contains(t, out, "### Notes\n\nnote\n\n")
+	require.Less(t, strings.Index(out, "a.go"), strings.Index(out, "b.go"))
+	// The static context precedes the diff, so the prompt prefix is cacheable
+	require.Less(t, strings.Index(out, "### Notes"), strings.Index(out, "### Git Diff"))
+}
+
+func TestRenderReviewCustom(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := ParseReviewTemplate(`{{ .Branch }}..{{ .BaseBranch }} {{ join .Commits "|" }} {{ .Tokens.Used }}/{{ .Tokens.Available }}{{ with .Intent }} {{ .CustomInstruction }}{{ end }}`)
+	require.NoError(t, err)
+
+	data := NewReviewData("", nil, nil, nil)
+	data.Branch = "feature"
+	data.BaseBranch = "main"
+	data.Commits = []string{"Add A", "Fix B"}
+	data.Tokens = TokenStats{Used: 10, Available: 100}
+	data.Intent = &TemplateIntent{CustomInstruction: "check errors"}
+
+	out, err := RenderReview(tmpl, data)
+	require.NoError(t, err)
+	require.Equal(t, "feature..main Add A|Fix B 10/100 check errors", out)
+
+	_, err = ParseReviewTemplate("{{ .Diff")
+	require.Error(t, err)
+
+	missing, err := ParseReviewTemplate("{{ .Missing }}")
+	require.NoError(t, err)
+	_, err = RenderReview(missing, data)
+	require.Error(t, err)
+}
+
+func TestLoadReviewTemplate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tmpl, err := LoadReviewTemplate(dir, "")
+	require.NoError(t, err)
+	require.Same(t, defaultReviewTemplate, tmpl)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".revcli"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, RepoTemplateFile), []byte("repo {{ .Branch }}"), 0o644))
+	data := ReviewData{Branch: "main"}
+
+	tmpl, err = LoadReviewTemplate(dir, "")
+	require.NoError(t, err)
+	out, err := RenderReview(tmpl, data)
+	require.NoError(t, err)
+	require.Equal(t, "repo main", out)
+
+	tmpl, err = LoadReviewTemplate(dir, "preset {{ .Branch }}")
+	require.NoError(t, err)
+	out, err = RenderReview(tmpl, data)
+	require.NoError(t, err)
+	require.Equal(t, "preset main", out)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, RepoTemplateFile), []byte("{{ end }}"), 0o644))
+	_, err = LoadReviewTemplate(dir, "")
+	require.ErrorContains(t, err, RepoTemplateFile)
+}
+
+func TestTruncateFile(t *testing.T) {
+	t.Parallel()
+
+	// A multi-byte rune straddling the cap is dropped whole instead of split
+	content := strings.Repeat("a", MaxFileChars-1) + "é" + "rest"
+	truncated := truncateFile(content)
+	require.True(t, utf8.ValidString(truncated))
+	require.True(t, strings.HasPrefix(truncated, strings.Repeat("a", MaxFileChars-1)+"\n\n... (file truncated"))
+
+	require.Equal(t, "short", truncateFile("short"))
+}
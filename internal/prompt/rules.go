@@ -0,0 +1,188 @@
+package prompt
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RulePack is language-specific review guidance added to the system prompt
+// when the diff changes files in one of its languages
+type RulePack struct {
+	// Name identifies the pack, and is the file name of its override in the rules directory (e.g. typescript.yaml)
+	Name  string
+	Title string
+	// Languages are the detected languages the pack applies to
+	Languages []Language
+	Rules     string
+}
+
+// LanguageRulesTitle is the system prompt heading of the rule packs
+const LanguageRulesTitle = "Language Rules"
+
+// BuiltInRulePacks are the rule packs shipped with revcli
+var BuiltInRulePacks = []RulePack{
+	{
+		Name:      "go",
+		Title:     "Go",
+		Languages: []Language{LanguageGo},
+		Rules: `- **Goroutine Leaks**: Ensure every **go** func has a clear exit strategy (context cancellation or channel signal).
+- **Race Conditions**: Check for shared mutable state without **sync.Mutex** or atomic operations.
+- **Channel Safety**: Look for sends to closed channels or unbuffered channel deadlocks.
+- **ErrGroup Usage**: Prefer **errgroup.Group** over raw **sync.WaitGroup** for error propagation in parallel tasks.
+- **Sentinel Errors**: Check for **errors.Is** / **errors.As** usage over string comparison.
+- **Error Context**: Ensure errors are wrapped (**fmt.Errorf("...: %w", err)**) to preserve context.
+- **Panic Hygiene**: Flag any code that panics instead of returning an error (except during main initialization).
+- **Interface Pollution**: Enforce "Accept Interfaces, Return Structs". Flag overly large interfaces (prefer single-method interfaces).
+- **Dependency Injection**: Flag **init()** functions and package-level variables used for state.
+- **Functional Options**: Suggest the functional options pattern for complex struct constructors.
+- **Context Propagation**: Ensure **context.Context** is the first argument in async/IO-bound functions and isn't stored in structs.
+- **Slice/Map Preallocation**: Flag **append** loops where capacity is known but not set (**make([]T, 0, cap)**).
+- **Pointer Semantics**: Flag unnecessary pointer usage for small structs (causing heap escape) vs. value semantics.
+- **String Efficiency**: Suggest **strings.Builder** over **+** concatenation in loops.
+- **Crypto Safety**: Ensure **crypto/rand** is used for security tokens, not **math/rand**.
+- **Time Comparison**: Use **time.Equal** or **!Before/After** instead of **==** (monotonic clock).
+- Do not report errors ignored from **sonic.Marshal** or **sonic.Unmarshal**; it is sometimes intended.
+- Link to *Effective Go*, the *Go Wiki* or proposal specs when correcting idiomatic patterns.`,
+	},
+	{
+		Name:      "typescript",
+		Title:     "TypeScript / JavaScript",
+		Languages: []Language{LanguageTypeScript, LanguageJavaScript},
+		Rules: `- **Type Safety**: Flag **any**, non-null assertions (**!**) and unchecked **as** casts that hide real types; prefer **unknown** with narrowing.
+- **Async Errors**: Flag floating promises (missing **await** or **.catch**), **async** callbacks passed to **forEach**, and unhandled rejections.
+- **Equality**: Require **===** / **!==**; flag truthiness checks that mis-handle **0** or **""**.
+- **Mutation**: Flag mutation of props, state or function arguments; prefer immutable updates.
+- **React**: Check hook dependency arrays, hooks called conditionally, and missing **key** props in lists.
+- **Injection**: Flag **innerHTML**, **dangerouslySetInnerHTML**, **eval** and string-built queries or shell commands.
+- **Modules**: Flag circular imports and side effects at import time.`,
+	},
+	{
+		Name:      "python",
+		Title:     "Python",
+		Languages: []Language{LanguagePython},
+		Rules: `- **Mutable Defaults**: Flag mutable default arguments (**def f(x=[])**).
+- **Exceptions**: Flag bare **except:** and **except Exception** that swallow errors; re-raise with **raise ... from err**.
+- **Resources**: Require **with** blocks for files, locks and connections.
+- **Typing**: Check type hints on public functions and that **Optional** values are handled.
+- **Async**: Flag blocking calls (**requests**, **time.sleep**, file IO) inside **async def**.
+- **Injection**: Flag **eval**/**exec**, **pickle** on untrusted data, **subprocess** with **shell=True** and f-string SQL.
+- Follow PEP 8 and PEP 20; link to the relevant PEP when correcting style.`,
+	},
+	{
+		Name:      "rust",
+		Title:     "Rust",
+		Languages: []Language{LanguageRust},
+		Rules: `- **Panics**: Flag **unwrap()**, **expect()** and indexing that can panic outside tests; propagate with **?**.
+- **Unsafe**: Every **unsafe** block needs a **// SAFETY:** comment that justifies its invariants.
+- **Ownership**: Flag needless **clone()**, **to_string()** and **Box**; prefer borrowing and **&str**/**&[T]** parameters.
+- **Errors**: Prefer typed errors (**thiserror**) in libraries and context (**anyhow::Context**) in binaries.
+- **Async**: Flag blocking calls in async code and locks held across **.await**.
+- **Concurrency**: Check **Send**/**Sync** bounds and **Arc<Mutex<_>>** contention.`,
+	},
+	{
+		Name:      "java",
+		Title:     "Java",
+		Languages: []Language{LanguageJava, LanguageKotlin},
+		Rules: `- **Null Safety**: Flag possible **NullPointerException**s; prefer **Optional** returns over null.
+- **Resources**: Require try-with-resources for streams, connections and locks.
+- **Exceptions**: Flag swallowed exceptions, catching **Exception**/**Throwable**, and lost causes when rethrowing.
+- **Equality**: Check **equals**/**hashCode** are overridden together; flag **==** on strings and boxed values.
+- **Concurrency**: Check shared state for synchronization, and executors for shutdown.
+- **Injection**: Flag string-built SQL (use **PreparedStatement**) and unsafe deserialization.`,
+	},
+	{
+		Name:      "sql",
+		Title:     "SQL",
+		Languages: []Language{LanguageSQL},
+		Rules: `- **Migrations**: Flag destructive or locking changes (dropping columns, adding **NOT NULL** without a default, rewriting large tables) and missing down migrations.
+- **Indexes**: Check new filters, joins and foreign keys have supporting indexes; flag indexes created without **CONCURRENTLY** on large Postgres tables.
+- **Correctness**: Flag **SELECT \***, implicit joins, **NULL** comparisons with **=** and **NOT IN** over nullable subqueries.
+- **Safety**: Flag **UPDATE**/**DELETE** without a **WHERE** clause.
+- **Performance**: Flag functions on indexed columns in **WHERE**, unbounded queries and N+1 patterns.`,
+	},
+	{
+		Name:      "shell",
+		Title:     "Shell",
+		Languages: []Language{LanguageShell},
+		Rules: `- **Strict Mode**: Require **set -euo pipefail** (or an explicit reason not to).
+- **Quoting**: Flag unquoted variables and command substitutions (word splitting, globbing).
+- **Portability**: Flag bashisms in **#!/bin/sh** scripts.
+- **Safety**: Flag **rm -rf** with variables that may be empty, **eval**, and piping downloads into a shell.
+- **Temp Files**: Require **mktemp** and a **trap** for cleanup.
+- Reference ShellCheck codes (e.g. SC2086) where they apply.`,
+	},
+	{
+		Name:      "dockerfile",
+		Title:     "Dockerfile",
+		Languages: []Language{LanguageDockerfile},
+		Rules: `- **Base Images**: Flag **latest** tags; prefer pinned versions or digests and minimal images.
+- **User**: Flag containers running as root; require a **USER** instruction.
+- **Layers**: Combine **RUN** steps that install and clean up, order instructions for cache reuse, and use multi-stage builds.
+- **Secrets**: Flag secrets in **ENV**, **ARG** or copied files; use build secrets instead.
+- **Copy**: Prefer **COPY** over **ADD**, and check a **.dockerignore** keeps the context small.
+- **Runtime**: Check **HEALTHCHECK** and exec-form **CMD**/**ENTRYPOINT** for signal handling.`,
+	},
+	{
+		Name:      "kubernetes",
+		Title:     "Kubernetes YAML",
+		Languages: []Language{LanguageKubernetes},
+		Rules: `- **Resources**: Require CPU/memory requests and memory limits on every container.
+- **Probes**: Check readiness and liveness probes exist and don't share an endpoint that fails together.
+- **Security Context**: Require **runAsNonRoot**, **readOnlyRootFilesystem**, **allowPrivilegeEscalation: false** and dropped capabilities.
+- **Images**: Flag **latest** tags and **imagePullPolicy** mismatches.
+- **Secrets**: Flag credentials in plain **env** values or ConfigMaps; use Secrets or external secret stores.
+- **Rollouts**: Check selectors and labels match, replica counts, PodDisruptionBudgets and rolling update settings.
+- **RBAC**: Flag wildcard verbs/resources and cluster-wide roles where namespaced roles suffice.`,
+	},
+}
+
+// RulePackNames returns the names of the rule packs for the detected languages, in language order
+// A language without a built-in pack uses its own name, so a pack can be added for it in the rules directory
+func RulePackNames(languages []Language) []string {
+	var names []string
+	for _, language := range languages {
+		name := string(language)
+		if pack, ok := builtInRulePackFor(language); ok {
+			name = pack.Name
+		}
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// BuiltInRulePack returns the built-in rule pack with a name
+func BuiltInRulePack(name string) (RulePack, bool) {
+	i := slices.IndexFunc(BuiltInRulePacks, func(p RulePack) bool { return p.Name == name })
+	if i < 0 {
+		return RulePack{}, false
+	}
+	return BuiltInRulePacks[i], true
+}
+
+// builtInRulePackFor returns the built-in rule pack of a language
+func builtInRulePackFor(language Language) (RulePack, bool) {
+	i := slices.IndexFunc(BuiltInRulePacks, func(p RulePack) bool { return slices.Contains(p.Languages, language) })
+	if i < 0 {
+		return RulePack{}, false
+	}
+	return BuiltInRulePacks[i], true
+}
+
+// BuildLanguageRules renders rule packs as a system prompt section ("" when there are none)
+func BuildLanguageRules(packs []RulePack) string {
+	if len(packs) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("## %s\n\nApply these rules to files in the matching language.\n", LanguageRulesTitle))
+	for _, pack := range packs {
+		builder.WriteString(fmt.Sprintf("\n### %s\n\n", pack.Title))
+		builder.WriteString(strings.TrimRight(pack.Rules, "\n"))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
@@ -5,55 +5,53 @@ import (
 	"strings"
 )
 
-// SystemPrompt defines the Senior Go Engineer persona
-const SystemPrompt = `You are a Principal Go Engineer conducting a strict code review. Your goal is to catch subtle bugs, enforce idiomatic design, and ensure long-term maintainability.
+// SystemPrompt defines the Principal Engineer persona
+// Language-specific guidance comes from the rule packs of the languages in the diff
+const SystemPrompt = `You are a Principal Software Engineer conducting a strict code review. Your goal is to catch subtle bugs, enforce idiomatic design, and ensure long-term maintainability.
 
 ## Review Focus Areas (Comprehensive)
 
 ### 1. Project Structure & Architecture (High Priority)
 - **Layer Isolation**: Ensure strict separation of concerns 
-- **Cyclic Dependencies**: Identify package imports that risk circular references or tightly coupled domains.
-- **Dependency Injection**: Flag usage of global variables or **init()** functions for state. Prefer explicit dependency injection via constructors.
-- **Package Cohesion**: Criticize "util" or "common" packages. Suggest breaking them down by domain (e.g., **strutil** vs **user/formatting**).
+- **Cyclic Dependencies**: Identify module imports that risk circular references or tightly coupled domains.
+- **Dependency Injection**: Flag global mutable state. Prefer explicit dependencies passed through constructors.
+- **Module Cohesion**: Criticize "util" or "common" modules. Suggest breaking them down by domain.
 
 ### 2. Concurrency & Synchronization
-- **Goroutine Leaks**: Ensure every **go** func has a clear exit strategy (context cancellation or channel signal).
-- **Race Conditions**: Check for shared mutable state without **sync.Mutex** or atomic operations.
-- **Channel Safety**: Look for sends to closed channels or unbuffered channel deadlocks.
-- **ErrGroup Usage**: Prefer **errgroup.Group** over raw **sync.WaitGroup** for error propagation in parallel tasks.
+- **Leaks**: Ensure every background task has a clear exit strategy (cancellation or completion signal).
+- **Race Conditions**: Check for shared mutable state without synchronization.
+- **Deadlocks**: Look for inconsistent lock ordering and blocking calls while holding a lock.
 
 ### 3. Error Handling & Flow
-- **Sentinel Errors**: check for **errors.Is** / **errors.As** usage over string comparison.
-- **Panic Hygiene**: Flag any code that panics instead of returning an error (except during main initialization).
-- **Error Context**: Ensure errors are wrapped (**fmt.Errorf("%w", err)**) to preserve the stack trace/context.
+- **Swallowed Errors**: Flag errors that are ignored or logged without being handled.
+- **Error Context**: Ensure errors carry enough context to diagnose the failure.
+- **Failure Modes**: Flag crashes or exceptions used for expected conditions.
 
-### 4. Idiomatic Design (The Go Way)
-- **Interface Pollution**: Enforce "Accept Interfaces, Return Structs". Flag overly large interfaces (prefer single-method interfaces).
-- **Functional Options**: Suggest functional options pattern for complex struct constructors.
-- **Context Propagation**: Ensure **context.Context** is the first argument in async/IO-bound functions and isn't stored in structs.
+### 4. Idiomatic Design
+- **Language Idioms**: Follow the idioms of each file's language (see Language Rules).
+- **Interface Design**: Flag overly large interfaces and leaky abstractions.
+- **Resource Management**: Ensure files, connections and locks are always released.
 
 ### 5. Performance & Memory
-- **Slice/Map Preallocation**: Flag **append** loops where capacity is known but not set (**make([]T, 0, cap)**).
-- **Pointer Semantics**: Flag unnecessary pointer usage for small structs (causing heap escape) vs. value semantics.
-- **String Efficiency**: Suggest **strings.Builder** over **+** concatenation for loops.
+- **Allocations**: Flag avoidable allocations and copies in hot paths.
+- **Algorithms**: Flag quadratic work where linear is possible, and N+1 queries.
 
 ### 6. Security & Input
 - **Input Sanitization**: Check for SQL injection, path traversal, or shell injection risks.
-- **Crypto Safety**: Ensure **crypto/rand** is used for security tokens, not **math/rand**.
-- **Time Comparison**: Use **time.Equal** or **!Before/After** instead of **==** for strict time comparison (monotonic clock issues).
+- **Crypto Safety**: Ensure a cryptographically secure random source is used for security tokens.
+- **Secrets**: Flag hardcoded credentials and sensitive data in logs.
 
 ## Ignore (Do not report these)
 1. Non-standard ID field naming
 2. Non-transactional queries (general)
 3. **time.Now()** usage (Timezone/UTC issues)
 4. Specified error message
-5. Error ignored by **sonic.Marshal** or **sonic.Unmmarshal** sometimes is intented
 
 ## Response Guidelines (Strict)
 - **Format**: Bullet points only.
 - **Clickable References (CRITICAL)**: All file references MUST follow the format **path/to/file.go:line_number** (e.g., **internal/ui/list.go:42**). This allows modern terminals to hyperlink the file.
 - **Directness**: No fluff ("I think...", "Maybe..."). State the issue and the fix.
-- **The "Why"**: Link to *Effective Go*, *Go Wiki*, or specific proposal specs when correcting idiomatic patterns.
+- **The "Why"**: Link to the language's official documentation or style guide when correcting idiomatic patterns.
 - **Socratic Challenge**: Ask a targeted question to force the developer to defend their choice (e.g., "How does this package structure support testing without mocking the database?").
 
 ---
@@ -71,7 +69,7 @@ Structure your review exactly as follows:
 *List code style improvements (variable inlining, naming) or test coverage gaps.*
 
 ### 💡 Code Suggestions
-*Provide corrected code snippets for the issues above.*
+*Provide fixes for the issues above as unified diff patches (see Patch Format).*
 
 ### Questions
 Any clarifying questions about the intent of the code.
@@ -80,112 +78,108 @@ Any clarifying questions about the intent of the code.
 
 Be concise but thorough. Focus on the most impactful feedback. If the code looks good, acknowledge it and highlight any particularly well-written sections.`
 
+// PatchFormatInstructions tells the reviewer how to format code suggestions
+// Patches are verified with git apply --check (and go/parser + gofmt for Go) before being shown
+const PatchFormatInstructions = `### Patch Format
+
+Write every code suggestion as a unified diff in a ` + "```diff" + ` block that applies to the current working tree with ` + "`git apply`" + `:
+- Use ` + "`--- a/<path>`" + ` and ` + "`+++ b/<path>`" + ` headers relative to the repository root, followed by ` + "`@@`" + ` hunks with 3 lines of context.
+- One block per independent fix; never mix unrelated fixes in one block.
+- Tag the block with a category in the fence info, e.g. ` + "```diff category=typo" + `. Categories: typo, error-wrapping, lint, style, naming, performance, logic, security.
+- Go code must compile and be gofmt-formatted.
+`
+
+// FindingFormatInstructions tells the reviewer how to format findings
+// The [category] tag and file reference are part of the fingerprint used for suppressions
+const FindingFormatInstructions = `### Finding Format
+
+Group findings under ` + "`### 🔴 Critical`, `### 🟠 Warnings` and `### 🟡 Refactoring`" + ` headings, one top-level bullet per finding:
+- Start the bullet with a category tag, e.g. ` + "`- [error-wrapping] ...`" + `. Use the patch categories below.
+- Reference exactly one location as ` + "`path/to/file.go:line`" + ` relative to the repository root.
+`
+
+// ExplorationInstructionsFormat tells the reviewer how it may explore the repository (tool-call budget)
+const ExplorationInstructionsFormat = `### Repository Exploration
+
+When the diff and the files provided aren't enough to judge a change, explore the repository with the read-only tools (view, grep, glob, ls, and lsp_references when available):
+- You have at most %d tool calls; spend them on what the review needs, e.g. a caller or an interface implementation of the changed code.
+- Don't read files that are already provided. Files excluded from the review (tests, generated and vendored code) and secrets aren't returned.
+- You can't change files or run commands; write changes as patches.
+`
+
+// Section is an extra block of context appended to the review prompt
+type Section struct {
+	Title string
+	Body  string
+}
+
 // BuildReviewPrompt constructs the full prompt for code review
 func BuildReviewPrompt(rawDiff string, fileContents map[string]string) string {
-	return BuildReviewPromptWithPruning(rawDiff, fileContents, nil)
+	return BuildReviewPromptWithPruning(rawDiff, fileContents, nil, nil)
 }
 
-// BuildReviewPromptWithPruning constructs the full prompt for code review with pruning support
-func BuildReviewPromptWithPruning(rawDiff string, fileContents map[string]string, prunedFiles map[string]string) string {
+// BuildReviewPromptWithPruning constructs the full prompt for code review with pruning support,
+// using the default template; sections (may be nil) are written after the file context
+func BuildReviewPromptWithPruning(rawDiff string, fileContents map[string]string, prunedFiles map[string]string, sections []Section) string {
+	// The default template only renders the data's own fields, so it can't fail
+	userPrompt, _ := RenderReview(nil, NewReviewData(rawDiff, fileContents, prunedFiles, sections))
+	return userPrompt
+}
+
+// MergePromptIntroFormat explains the merge step of a chunked review
+const MergePromptIntroFormat = `## Merge Chunked Review
+
+This change was too large for one review, so it was split into %d chunks reviewed separately. Merge the chunk reviews below into one review of the whole change:
+- Keep every finding exactly once, under its severity heading. %d findings raised by several chunks were already removed; merge any that remain as the same issue in different words.
+- Keep each finding's category tag and file reference unchanged.
+- Keep suggested patches unchanged.
+- Replace the per-chunk summaries with one coherent summary of the whole change.
+- Say which files were not reviewed when a chunk failed.
+
+`
+
+// BuildMergePrompt constructs the prompt that merges the chunk reviews of a chunked review
+// reviews holds one section per chunk, titled with the chunk and its files
+func BuildMergePrompt(reviews []Section, duplicates int) string {
 	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf(MergePromptIntroFormat, len(reviews), duplicates))
 
-	builder.WriteString("## Code Review Request\n\n")
-	builder.WriteString("Please review the following code changes.\n\n")
-
-	// Add the diff
-	builder.WriteString("### Git Diff (Changes)\n\n")
-	builder.WriteString("```diff\n")
-	builder.WriteString(rawDiff)
-	builder.WriteString("\n```\n\n")
-
-	// Add file contents for context
-	if len(fileContents) > 0 {
-		builder.WriteString("### Full File Context\n\n")
-		builder.WriteString("Below are the complete contents of the modified files for additional context:\n\n")
-
-		for path, content := range fileContents {
-			// Check if file is pruned
-			if prunedFiles != nil {
-				if summary, pruned := prunedFiles[path]; pruned {
-					// Use summary instead of full content
-					builder.WriteString(fmt.Sprintf("#### File: `%s` (Pruned)\n\n", path))
-					builder.WriteString(fmt.Sprintf("*Summary: %s*\n\n", summary))
-					continue
-				}
-			}
-
-			// Determine language for syntax highlighting
-			lang := getLanguageFromPath(path)
-
-			builder.WriteString(fmt.Sprintf("#### File: `%s`\n\n", path))
-			builder.WriteString(fmt.Sprintf("```%s\n", lang))
-
-			// Truncate very large files
-			if len(content) > 50000 {
-				builder.WriteString(content[:50000])
-				builder.WriteString("\n\n... (file truncated due to size) ...\n")
-			} else {
-				builder.WriteString(content)
-			}
-
-			builder.WriteString("\n```\n\n")
-		}
+	for _, review := range reviews {
+		builder.WriteString(fmt.Sprintf("### %s\n\n", review.Title))
+		builder.WriteString(strings.TrimRight(review.Body, "\n"))
+		builder.WriteString("\n\n")
 	}
 
 	builder.WriteString("---\n\n")
-	builder.WriteString("Please provide your code review based on the diff and file context above.\n")
-
+	builder.WriteString(FindingFormatInstructions)
+	builder.WriteString("\n")
+	builder.WriteString(PatchFormatInstructions)
+	builder.WriteString("\n---\n\n")
+	builder.WriteString("Please provide the merged code review.\n")
 	return builder.String()
 }
 
 // BuildFollowUpPrompt constructs a prompt for follow-up questions
-func BuildFollowUpPrompt(question string) string {
-	return fmt.Sprintf("Follow-up question about the code review:\n\n%s", question)
-}
-
-// getLanguageFromPath returns the language identifier for syntax highlighting
-func getLanguageFromPath(path string) string {
-	switch {
-	case strings.HasSuffix(path, ".go"):
-		return "go"
-	case strings.HasSuffix(path, ".js"):
-		return "javascript"
-	case strings.HasSuffix(path, ".ts"):
-		return "typescript"
-	case strings.HasSuffix(path, ".py"):
-		return "python"
-	case strings.HasSuffix(path, ".rs"):
-		return "rust"
-	case strings.HasSuffix(path, ".java"):
-		return "java"
-	case strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml"):
-		return "yaml"
-	case strings.HasSuffix(path, ".json"):
-		return "json"
-	case strings.HasSuffix(path, ".md"):
-		return "markdown"
-	case strings.HasSuffix(path, ".sql"):
-		return "sql"
-	case strings.HasSuffix(path, ".sh") || strings.HasSuffix(path, ".bash"):
-		return "bash"
-	case strings.HasSuffix(path, ".dockerfile") || path == "Dockerfile":
-		return "dockerfile"
-	default:
-		return ""
+// rejected holds patches the user rejected (may be nil) so they aren't suggested again
+func BuildFollowUpPrompt(question string, rejected []string) string {
+	followUp := fmt.Sprintf("Follow-up question about the code review:\n\n%s", question)
+	if len(rejected) == 0 {
+		return followUp
 	}
-}
 
-// EstimateTokens provides a rough estimate of tokens in the prompt
-// This is a simple heuristic: ~4 characters per token for English text
-func EstimateTokens(text string) int {
-	return len(text) / 4
+	var builder strings.Builder
+	builder.WriteString(followUp)
+	builder.WriteString("\n\n---\n\n")
+	builder.WriteString("The user rejected these suggested patches earlier in this review. Do not suggest them again:\n\n")
+	for _, diff := range rejected {
+		builder.WriteString("```diff\n")
+		builder.WriteString(strings.TrimRight(diff, "\n"))
+		builder.WriteString("\n```\n\n")
+	}
+	return builder.String()
 }
 
-// MaxTokenWarning returns a warning if the prompt is too large
-func MaxTokenWarning(prompt string, maxTokens int) string {
-	estimated := EstimateTokens(prompt)
-	if estimated > maxTokens {
-		return fmt.Sprintf("Warning: Estimated %d tokens exceeds recommended limit of %d. Consider reviewing fewer files.", estimated, maxTokens)
-	}
-	return ""
+// getLanguageFromPath returns the language identifier for syntax highlighting
+func getLanguageFromPath(path string) string {
+	return DetectLanguage(path, "").Fence()
 }
@@ -0,0 +1,62 @@
+package prune
+
+import (
+	"context"
+
+	"github.com/samber/lo"
+	"golang.org/x/sync/errgroup"
+
+	appcontext "github.com/trankhanh040147/revcli/internal/context"
+	"github.com/trankhanh040147/revcli/internal/tokens"
+)
+
+// Auto prunes the least relevant files until every changed file fits the token budget, in full or pruned
+// Files are summarized in batches of up to Concurrency, and the context is packed again after each batch
+// Returns the pruned files in pruning order; nothing is pruned when the diff alone exceeds the budget
+func Auto(ctx context.Context, rc *appcontext.ReviewContext, summarizer *Summarizer) ([]string, error) {
+	candidates := lo.Reject(Rank(rc.RawDiff, rc.FileContents), func(path string, _ int) bool {
+		_, pruned := rc.Summaries[path]
+		return pruned
+	})
+
+	var pruned []string
+	for len(candidates) > 0 {
+		unfit := unfitFiles(rc.TokenPlan)
+		if unfit == 0 || rc.TokenPlan.Overflow() > 0 {
+			break
+		}
+		// Pruning a file usually makes room for about one other, so the batch grows with what doesn't fit
+		batch := candidates[:min(unfit, Concurrency, len(candidates))]
+		candidates = candidates[len(batch):]
+
+		summaries := make([]string, len(batch))
+		g, gCtx := errgroup.WithContext(ctx)
+		for i, path := range batch {
+			g.Go(func() error {
+				summary, err := summarizer.Summarize(gCtx, path, rc.FileContents[path])
+				summaries[i] = summary
+				return err
+			})
+		}
+		if err := g.Wait(); err != nil {
+			return pruned, err
+		}
+
+		batchSummaries := make(map[string]string, len(batch))
+		for i, path := range batch {
+			batchSummaries[path] = summaries[i]
+		}
+		if err := rc.Prune(batchSummaries); err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, batch...)
+	}
+	return pruned, nil
+}
+
+// unfitFiles counts the changed files that were summarized by their outline or dropped
+func unfitFiles(plan *tokens.Plan) int {
+	return lo.CountBy(plan.Entries, func(e tokens.Entry) bool {
+		return e.Priority == tokens.PriorityChangedFile && e.Decision != tokens.DecisionIncluded
+	})
+}
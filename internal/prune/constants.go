@@ -0,0 +1,25 @@
+package prune
+
+// SystemPrompt instructs the small model to summarize a file the reviewer only needs for context
+const SystemPrompt = `You summarize source files for a code reviewer who will not see the full file.
+
+- Start with one sentence on what the file does and its main purpose.
+- Then list every exported declaration (types, functions, methods, constants, variables, classes) with its exact signature, copied verbatim, without bodies.
+- Add one short line per declaration only when its behavior isn't obvious from the name.
+- Mention side effects (IO, goroutines, global state) and the errors it returns.
+- Output plain markdown, with signatures in a code block. Do not review the code or suggest changes.`
+
+// PromptFormat is the summarization prompt: the file path, its code fence language and its content
+const PromptFormat = "Summarize this file.\n\nFile: %s\n\n```%s\n%s\n```"
+
+// PromptVersion is part of the summary cache key; bump it when SystemPrompt, PromptFormat or MaxFileChars change
+const PromptVersion = "1"
+
+// MaxFileChars caps the content sent to the summarizer
+const MaxFileChars = 60000
+
+// TruncatedNote marks content cut to MaxFileChars
+const TruncatedNote = "\n... (file truncated) ..."
+
+// Concurrency is the number of files summarized at once by auto-prune
+const Concurrency = 4
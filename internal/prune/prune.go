@@ -0,0 +1,110 @@
+// Package prune replaces changed files the reviewer only needs for context with a summary
+// written by the small model, so large changes fit the token budget
+package prune
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/trankhanh040147/revcli/internal/git"
+	"github.com/trankhanh040147/revcli/internal/prompt"
+)
+
+// GenerateFunc sends a one-shot prompt to the summarizer model and returns its text response
+type GenerateFunc func(ctx context.Context, systemPrompt, prompt string) (string, error)
+
+// Summarizer summarizes files with the small model, reusing cached summaries of the same content
+type Summarizer struct {
+	// cache stores summaries by CacheKey (nil disables caching)
+	cache Service
+	// model identifies the summarizer model in the cache key
+	model    string
+	generate GenerateFunc
+}
+
+// NewSummarizer creates a summarizer for model; cache may be nil
+func NewSummarizer(cache Service, model string, generate GenerateFunc) *Summarizer {
+	return &Summarizer{cache: cache, model: model, generate: generate}
+}
+
+// Summarize returns the summary of a file, from the cache when its content was summarized before
+func (s *Summarizer) Summarize(ctx context.Context, path, content string) (string, error) {
+	key := CacheKey(s.model, content)
+	if s.cache != nil {
+		summary, ok, err := s.cache.Get(ctx, key)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return summary, nil
+		}
+	}
+
+	sent := content
+	if len(sent) > MaxFileChars {
+		sent = sent[:MaxFileChars] + TruncatedNote
+	}
+	fence := prompt.DetectLanguage(path, content).Fence()
+	summary, err := s.generate(ctx, SystemPrompt, fmt.Sprintf(PromptFormat, path, fence, sent))
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize %s: %w", path, err)
+	}
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return "", fmt.Errorf("failed to summarize %s: empty response", path)
+	}
+
+	if s.cache != nil {
+		if err := s.cache.Save(ctx, path, key, summary); err != nil {
+			return "", err
+		}
+	}
+	return summary, nil
+}
+
+// CacheKey identifies a summary of content written by model with the current prompt
+func CacheKey(model, content string) string {
+	sum := sha256.Sum256([]byte(PromptVersion + "\x00" + model + "\x00" + content))
+	return hex.EncodeToString(sum[:])
+}
+
+// Rank orders the changed files from least to most relevant to the review
+// Relevance is the share of a file's lines the diff touches, so large files with small edits come first;
+// ties put the larger file first, then sort by path
+func Rank(diff string, files map[string]string) []string {
+	changed := make(map[string]int)
+	for _, f := range git.SplitDiff(diff) {
+		changed[f.Path] = changedLines(f.Diff)
+	}
+
+	relevance := func(path string) float64 {
+		return float64(changed[path]) / float64(max(strings.Count(files[path], "\n"), 1))
+	}
+	return slices.SortedFunc(maps.Keys(files), func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(relevance(a), relevance(b)),
+			cmp.Compare(len(files[b]), len(files[a])),
+			cmp.Compare(a, b),
+		)
+	})
+}
+
+// changedLines counts the added and removed lines of a file diff
+func changedLines(diff string) int {
+	count := 0
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") {
+			continue
+		}
+		if strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") {
+			count++
+		}
+	}
+	return count
+}
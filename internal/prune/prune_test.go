@@ -0,0 +1,132 @@
+package prune
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	appcontext "github.com/trankhanh040147/revcli/internal/context"
+	"github.com/trankhanh040147/revcli/internal/tokens"
+)
+
+// memoryCache is an in-memory summary cache
+type memoryCache struct {
+	mu        sync.Mutex
+	summaries map[string]string
+}
+
+func (c *memoryCache) Get(_ context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	summary, ok := c.summaries[key]
+	return summary, ok, nil
+}
+
+func (c *memoryCache) Save(_ context.Context, _, key, summary string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.summaries[key] = summary
+	return nil
+}
+
+// fileDiff returns a diff that adds lines to path
+func fileDiff(path string, lines int) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n@@ -1,0 +1,%d @@\n", path, path, path, path, lines))
+	for i := range lines {
+		builder.WriteString(fmt.Sprintf("+line %d of %s\n", i, path))
+	}
+	return builder.String()
+}
+
+// fileContent returns a file with lines lines
+func fileContent(path string, lines int) string {
+	var builder strings.Builder
+	for i := range lines {
+		builder.WriteString(fmt.Sprintf("// line %d of %s\n", i, path))
+	}
+	return builder.String()
+}
+
+func TestRank(t *testing.T) {
+	t.Parallel()
+
+	diff := fileDiff("hot.go", 10) + fileDiff("big.go", 2) + fileDiff("small.go", 2)
+	files := map[string]string{
+		"hot.go":   fileContent("hot.go", 20),
+		"big.go":   fileContent("big.go", 200),
+		"small.go": fileContent("small.go", 100),
+		"same.go":  fileContent("same.go", 200),
+	}
+	require.Equal(t, []string{"same.go", "big.go", "small.go", "hot.go"}, Rank(diff, files))
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	generate := func(_ context.Context, systemPrompt, prompt string) (string, error) {
+		calls.Add(1)
+		require.Contains(t, systemPrompt, "exported declaration")
+		require.Contains(t, prompt, "File: a.go\n\n```go\n")
+		return "  Summary of a.go.\n", nil
+	}
+	cache := &memoryCache{summaries: make(map[string]string)}
+	summarizer := NewSummarizer(cache, "small", generate)
+
+	for range 2 {
+		summary, err := summarizer.Summarize(t.Context(), "a.go", "package a\n")
+		require.NoError(t, err)
+		require.Equal(t, "Summary of a.go.", summary)
+	}
+	require.Equal(t, int32(1), calls.Load())
+
+	// Another model doesn't reuse the summary
+	_, err := NewSummarizer(cache, "other", generate).Summarize(t.Context(), "a.go", "package a\n")
+	require.NoError(t, err)
+	require.Equal(t, int32(2), calls.Load())
+	require.NotEqual(t, CacheKey("small", "package a\n"), CacheKey("other", "package a\n"))
+
+	_, err = NewSummarizer(nil, "small", func(context.Context, string, string) (string, error) { return " ", nil }).
+		Summarize(t.Context(), "a.go", "package a\n")
+	require.ErrorContains(t, err, "empty response")
+}
+
+func TestAuto(t *testing.T) {
+	t.Parallel()
+
+	diff := fileDiff("hot.go", 5) + fileDiff("cold.go", 1)
+	files := map[string]string{
+		"hot.go":  fileContent("hot.go", 10),
+		"cold.go": fileContent("cold.go", 400),
+	}
+	tokenizer := tokens.NewTokenizer(tokens.FamilyGeneric)
+	// Room for the diff and hot.go, but not cold.go in full
+	budget := tokens.Budget{
+		ContextWindow: tokenizer.Count(diff+files["hot.go"]) + 200,
+		Tokenizer:     tokenizer,
+	}
+	reviewCtx := &appcontext.ReviewContext{
+		RawDiff:      diff,
+		FileContents: files,
+		TokenPlan:    &tokens.Plan{Budget: budget},
+	}
+	require.NoError(t, reviewCtx.Prune(nil))
+	require.False(t, reviewCtx.TokenPlan.Fits())
+
+	generate := func(context.Context, string, string) (string, error) {
+		return "Comment lines.", nil
+	}
+	pruned, err := Auto(t.Context(), reviewCtx, NewSummarizer(nil, "small", generate))
+	require.NoError(t, err)
+	require.Equal(t, []string{"cold.go"}, pruned)
+	require.True(t, reviewCtx.TokenPlan.Fits())
+	require.Equal(t, "Comment lines.", reviewCtx.PrunedFiles["cold.go"])
+	require.NotContains(t, reviewCtx.PrunedFiles, "hot.go")
+	require.Contains(t, reviewCtx.UserPrompt, "Comment lines.")
+}
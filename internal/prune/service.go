@@ -0,0 +1,50 @@
+package prune
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/trankhanh040147/revcli/internal/db"
+)
+
+// Service caches file summaries by CacheKey
+type Service interface {
+	// Get returns the summary cached under key, if any
+	Get(ctx context.Context, key string) (string, bool, error)
+	// Save caches the summary of path under key
+	Save(ctx context.Context, path, key, summary string) error
+}
+
+type service struct {
+	q *db.Queries
+}
+
+// NewService creates a summary cache backed by the database
+func NewService(q *db.Queries) Service {
+	return &service{q: q}
+}
+
+func (s *service) Get(ctx context.Context, key string) (string, bool, error) {
+	summary, err := s.q.GetFileSummary(ctx, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to load file summary: %w", err)
+	}
+	return summary.Summary, true, nil
+}
+
+func (s *service) Save(ctx context.Context, path, key, summary string) error {
+	err := s.q.UpsertFileSummary(ctx, db.UpsertFileSummaryParams{
+		ContentHash: key,
+		Path:        path,
+		Summary:     summary,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save file summary: %w", err)
+	}
+	return nil
+}
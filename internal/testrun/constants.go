@@ -0,0 +1,33 @@
+package testrun
+
+// Statuses of a tested package
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+	// StatusSkip is a package without tests
+	StatusSkip Status = "skip"
+)
+
+// SectionTitle is the prompt section with the test results of the changed packages
+const SectionTitle = "Test Results"
+
+// SectionIntroFormat explains the test results to the model (passed and failed package counts)
+const SectionIntroFormat = "`go test` ran on the packages the diff touches: %d passed, %d failed. Connect findings to these failures where the diff explains them, and say which failures the diff doesn't explain.\n"
+
+// TimedOutNote is added to the section when the test run hit its timeout
+const TimedOutNote = "\nThe test run timed out; packages that didn't finish are reported as failed.\n"
+
+// MaxFailedTests caps the failing tests listed per package
+const MaxFailedTests = 10
+
+// SummaryFormat summarizes a report (passed, failed and skipped package counts)
+const SummaryFormat = "Tests: %d passed, %d failed, %d without tests"
+
+// CoverageSectionTitle is the prompt section with the changed lines no test runs
+const CoverageSectionTitle = "Uncovered Changed Lines"
+
+// CoverageSectionIntroFormat explains the uncovered lines to the model (percent, covered and total line counts)
+const CoverageSectionIntroFormat = "%.1f%% of the changed executable lines (%d of %d) ran in the tests of their package. The lines below didn't; suggest concrete test cases (inputs and expected results) that would cover them.\n\n"
+
+// CoverageSummaryFormat summarizes a coverage report (percent, covered and total line counts)
+const CoverageSummaryFormat = "Changed-line coverage: %.1f%% (%d of %d lines)"
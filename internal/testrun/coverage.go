@@ -0,0 +1,155 @@
+package testrun
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/trankhanh040147/revcli/internal/git"
+	"github.com/trankhanh040147/revcli/internal/prompt"
+)
+
+// FileCoverage is the coverage of a file's changed executable lines
+type FileCoverage struct {
+	Path    string `json:"path"`
+	Covered int    `json:"covered"`
+	Total   int    `json:"total"`
+	// Uncovered are the changed lines no test ran, in order
+	Uncovered []int `json:"uncovered_lines,omitempty"`
+}
+
+// Coverage is the coverage of a diff's added lines
+// Lines without statements (comments, declarations, blank lines) don't count
+type Coverage struct {
+	// Percent is the share of the changed executable lines that ran, 0-100
+	Percent float64        `json:"percent"`
+	Covered int            `json:"covered"`
+	Total   int            `json:"total"`
+	Files   []FileCoverage `json:"files"`
+}
+
+// ParseProfile parses a go test -coverprofile file into the executable lines of each file,
+// keyed by repo-relative path (module is the module path); a line is true when a test ran it
+func ParseProfile(profile, module string) map[string]map[int]bool {
+	lines := make(map[string]map[int]bool)
+	for _, line := range strings.Split(profile, "\n") {
+		// Format: name.go:line.column,line.column numberOfStatements count
+		file, rest, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) != 3 {
+			continue
+		}
+		start, end, ok := blockLines(fields[0])
+		count, err := strconv.Atoi(fields[2])
+		if !ok || err != nil {
+			continue
+		}
+
+		file = shortName(file, module)
+		if lines[file] == nil {
+			lines[file] = make(map[int]bool)
+		}
+		for l := start; l <= end; l++ {
+			lines[file][l] = lines[file][l] || count > 0
+		}
+	}
+	return lines
+}
+
+// ChangedLineCoverage maps a parsed profile onto the lines a diff adds
+// Returns nil when no added line is executable
+func ChangedLineCoverage(profile map[string]map[int]bool, diff string) *Coverage {
+	coverage := &Coverage{}
+	for _, f := range git.SplitDiff(diff) {
+		executable, ok := profile[f.Path]
+		if !ok {
+			continue
+		}
+		file := FileCoverage{Path: f.Path}
+		for _, line := range git.AddedLines(f.Diff) {
+			covered, ok := executable[line]
+			if !ok {
+				continue
+			}
+			file.Total++
+			if covered {
+				file.Covered++
+			} else {
+				file.Uncovered = append(file.Uncovered, line)
+			}
+		}
+		if file.Total > 0 {
+			coverage.Files = append(coverage.Files, file)
+			coverage.Covered += file.Covered
+			coverage.Total += file.Total
+		}
+	}
+	if coverage.Total == 0 {
+		return nil
+	}
+	slices.SortFunc(coverage.Files, func(a, b FileCoverage) int { return cmp.Compare(a.Path, b.Path) })
+	coverage.Percent = float64(coverage.Covered) * 100 / float64(coverage.Total)
+	return coverage
+}
+
+// Summary returns the share of changed lines covered
+func (c *Coverage) Summary() string {
+	return fmt.Sprintf(CoverageSummaryFormat, c.Percent, c.Covered, c.Total)
+}
+
+// Sections renders the uncovered changed lines per file as a prompt section
+// Returns nil for nil coverage or when every changed line is covered
+func (c *Coverage) Sections() []prompt.Section {
+	if c == nil || c.Covered == c.Total {
+		return nil
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf(CoverageSectionIntroFormat, c.Percent, c.Covered, c.Total))
+	for _, file := range c.Files {
+		if len(file.Uncovered) > 0 {
+			builder.WriteString(fmt.Sprintf("- `%s`: lines %s\n", file.Path, LineRanges(file.Uncovered)))
+		}
+	}
+	return []prompt.Section{{Title: CoverageSectionTitle, Body: builder.String()}}
+}
+
+// LineRanges renders sorted line numbers as ranges, e.g. "3-5, 9"
+func LineRanges(lines []int) string {
+	var ranges []string
+	for i := 0; i < len(lines); {
+		j := i
+		for j+1 < len(lines) && lines[j+1] == lines[j]+1 {
+			j++
+		}
+		if j == i {
+			ranges = append(ranges, strconv.Itoa(lines[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", lines[i], lines[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, ", ")
+}
+
+// blockLines returns the first and last line of a profile block ("12.5,14.2")
+func blockLines(block string) (int, int, bool) {
+	from, to, ok := strings.Cut(block, ",")
+	if !ok {
+		return 0, 0, false
+	}
+	start, err := strconv.Atoi(strings.Split(from, ".")[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	end, err := strconv.Atoi(strings.Split(to, ".")[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, end, true
+}
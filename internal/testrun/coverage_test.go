@@ -0,0 +1,48 @@
+package testrun
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedLineCoverage(t *testing.T) {
+	t.Parallel()
+
+	profile := ParseProfile(`mode: set
+example.com/app/calc/calc.go:3.21,4.11 1 1
+example.com/app/calc/calc.go:4.11,6.3 1 0
+example.com/app/calc/calc.go:7.2,7.10 1 1
+example.com/app/calc/calc.go:10.20,12.2 1 0
+example.com/other/x.go:1.1,2.2 1 1
+not a block
+`, "example.com/app")
+	require.Equal(t, map[int]bool{3: true, 4: true, 5: false, 6: false, 7: true, 10: false, 11: false, 12: false}, profile["calc/calc.go"])
+	require.Contains(t, profile, "example.com/other/x.go")
+
+	diff := "diff --git a/calc/calc.go b/calc/calc.go\n--- a/calc/calc.go\n+++ b/calc/calc.go\n@@ -3,0 +4,4 @@\n+\tif x < 0 {\n+\t\treturn -x\n+\t}\n+\n@@ -9,0 +11,1 @@\n+\t// comment\n" +
+		"diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n@@ -1,0 +1,1 @@\n+text\n"
+	coverage := ChangedLineCoverage(profile, diff)
+	require.NotNil(t, coverage)
+	require.Equal(t, []FileCoverage{{Path: "calc/calc.go", Covered: 2, Total: 5, Uncovered: []int{5, 6, 11}}}, coverage.Files)
+	require.InDelta(t, 40.0, coverage.Percent, 0.01)
+	require.Equal(t, "Changed-line coverage: 40.0% (2 of 5 lines)", coverage.Summary())
+
+	sections := coverage.Sections()
+	require.Len(t, sections, 1)
+	require.Equal(t, CoverageSectionTitle, sections[0].Title)
+	require.Contains(t, sections[0].Body, "- `calc/calc.go`: lines 5-6, 11\n")
+
+	require.Nil(t, ChangedLineCoverage(profile, "diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n@@ -1,0 +1,1 @@\n+text\n"))
+	var nilCoverage *Coverage
+	require.Nil(t, nilCoverage.Sections())
+	require.Nil(t, (&Coverage{Covered: 2, Total: 2}).Sections())
+}
+
+func TestLineRanges(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "", LineRanges(nil))
+	require.Equal(t, "7", LineRanges([]int{7}))
+	require.Equal(t, "3-5, 9, 11-12", LineRanges([]int{3, 4, 5, 9, 11, 12}))
+}
@@ -0,0 +1,121 @@
+package testrun
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/gotool"
+)
+
+// event is one line of go test -json output
+type event struct {
+	Action  string `json:"Action"`
+	Package string `json:"Package"`
+	Test    string `json:"Test"`
+	Output  string `json:"Output"`
+	// ImportPath is set on build-output events, e.g. "example.com/app [example.com/app.test]"
+	ImportPath string `json:"ImportPath"`
+	// FailedBuild is the ImportPath of the build that failed the package
+	FailedBuild string `json:"FailedBuild"`
+}
+
+// packageRun collects the events of one package
+type packageRun struct {
+	status      Status
+	failedTests []string
+	testOutput  map[string][]string
+	output      []string
+}
+
+// progressPrefixes start the output lines that only track test progress
+var progressPrefixes = []string{"=== RUN", "=== PAUSE", "=== CONT", "=== NAME"}
+
+// Parse parses go test -json output; module is the module path, used for the package names
+// Lines that aren't JSON (e.g. build errors of older Go versions) are added to the output of failed packages
+// without output of their own, and packages that never finished (a timeout) are reported as failed
+func Parse(output, module string) []PackageResult {
+	runs := make(map[string]*packageRun)
+	var order []string
+	run := func(pkg string) *packageRun {
+		r, ok := runs[pkg]
+		if !ok {
+			r = &packageRun{testOutput: make(map[string][]string)}
+			runs[pkg] = r
+			order = append(order, pkg)
+		}
+		return r
+	}
+
+	var stray []string
+	buildOutput := make(map[string][]string)
+	for _, line := range strings.Split(output, "\n") {
+		var e event
+		if !strings.HasPrefix(line, "{") || sonic.UnmarshalString(line, &e) != nil {
+			if strings.TrimSpace(line) != "" {
+				stray = append(stray, line)
+			}
+			continue
+		}
+
+		if e.Action == "build-output" {
+			buildOutput[e.ImportPath] = append(buildOutput[e.ImportPath], strings.TrimRight(e.Output, "\n"))
+			continue
+		}
+		if e.Package == "" {
+			continue
+		}
+
+		r := run(e.Package)
+		switch {
+		case e.Action == "output" && e.Test != "":
+			r.testOutput[e.Test] = append(r.testOutput[e.Test], strings.TrimRight(e.Output, "\n"))
+		case e.Action == "output":
+			r.output = append(r.output, strings.TrimRight(e.Output, "\n"))
+		case e.Action == "fail" && e.Test != "":
+			r.failedTests = append(r.failedTests, e.Test)
+		case e.Action == "fail":
+			r.status = StatusFail
+			r.output = slices.Concat(buildOutput[e.FailedBuild], r.output)
+		case e.Action == "pass" && e.Test == "":
+			r.status = StatusPass
+		case e.Action == "skip" && e.Test == "":
+			r.status = StatusSkip
+		}
+	}
+
+	var results []PackageResult
+	for _, pkg := range order {
+		r := runs[pkg]
+		result := PackageResult{Package: pkg, Name: shortName(pkg, module), Status: r.status, FailedTests: r.failedTests}
+		if result.Status == "" {
+			result.Status = StatusFail
+		}
+		if result.Status == StatusFail {
+			result.Output = r.failureOutput(stray)
+		}
+		results = append(results, result)
+	}
+	sortResults(results)
+	return results
+}
+
+// failureOutput returns the trimmed output of the failing tests, or of the package (falling back to stray lines)
+func (r *packageRun) failureOutput(stray []string) string {
+	var lines []string
+	for _, test := range r.failedTests {
+		lines = append(lines, r.testOutput[test]...)
+	}
+	if len(lines) == 0 {
+		lines = r.output
+	}
+	if len(lines) == 0 {
+		lines = stray
+	}
+	lines = lo.Reject(lines, func(line string, _ int) bool {
+		return lo.SomeBy(progressPrefixes, func(prefix string) bool { return strings.HasPrefix(strings.TrimSpace(line), prefix) })
+	})
+	return gotool.TrimOutput(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,200 @@
+// Package testrun runs go test on the packages a diff touches and reports failing tests with their output
+package testrun
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/git"
+	"github.com/trankhanh040147/revcli/internal/gotool"
+	"github.com/trankhanh040147/revcli/internal/prompt"
+)
+
+// Status is the outcome of a tested package
+type Status string
+
+// PackageResult is the outcome of one tested package
+type PackageResult struct {
+	// Package is the import path
+	Package string `json:"package"`
+	// Name is the package's directory in the repository ("." for the repository root)
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	// FailedTests are the failing tests (subtests included), in order of failure
+	FailedTests []string `json:"failed_tests,omitempty"`
+	// Output is the trimmed output of the failing tests, or of the package when it failed without a failing test
+	Output string `json:"output,omitempty"`
+}
+
+// Report is the test run of the changed packages
+type Report struct {
+	Packages []PackageResult `json:"packages"`
+	// TimedOut is true when the run hit gotool.DefaultTimeout
+	TimedOut bool `json:"timed_out,omitempty"`
+	// Coverage is the coverage of the changed lines (nil without coverage, or when no changed line is executable)
+	Coverage *Coverage `json:"-"`
+}
+
+// Run runs go test -json on the Go packages a diff touches, from the root of each package's module in rootDir,
+// bounded by gotool.DefaultTimeout
+// With coverage, it also writes a coverage profile and maps it onto the diff's added lines
+// Returns nil when the diff touches no Go packages
+func Run(rootDir, diff string, coverage bool) (*Report, error) {
+	paths := lo.Map(git.SplitDiff(diff), func(f git.FileDiff, _ int) string { return f.Path })
+	modules := gotool.ModulesForFiles(rootDir, paths)
+
+	ctx, cancel := context.WithTimeout(context.Background(), gotool.DefaultTimeout)
+	defer cancel()
+	report := &Report{}
+	executable := make(map[string]map[int]bool)
+	tested := false
+	for _, module := range modules {
+		moduleDir := filepath.Join(rootDir, module.Dir)
+		// Packages deleted by the diff can't be tested
+		pkgs := lo.Filter(module.Packages, func(pkg string, _ int) bool {
+			info, err := os.Stat(filepath.Join(moduleDir, pkg))
+			return err == nil && info.IsDir()
+		})
+		if len(pkgs) == 0 {
+			continue
+		}
+		tested = true
+
+		results, profile, err := runModule(ctx, moduleDir, module.Path, pkgs, coverage)
+		if err != nil {
+			return nil, err
+		}
+		for i := range results {
+			results[i].Name = path.Join(module.Dir, results[i].Name)
+		}
+		report.Packages = append(report.Packages, results...)
+		for file, lines := range profile {
+			executable[path.Join(module.Dir, file)] = lines
+		}
+	}
+	if !tested {
+		return nil, nil
+	}
+
+	sortResults(report.Packages)
+	report.TimedOut = errors.Is(ctx.Err(), context.DeadlineExceeded)
+	if coverage {
+		report.Coverage = ChangedLineCoverage(executable, diff)
+	}
+	return report, nil
+}
+
+// runModule runs go test -json on packages of a module, in the module's root
+// With coverage, it also returns the executable lines keyed by module-relative path
+func runModule(ctx context.Context, moduleDir, module string, pkgs []string, coverage bool) ([]PackageResult, map[string]map[int]bool, error) {
+	flags := []string{"-json"}
+	var profilePath string
+	if coverage {
+		profile, err := os.CreateTemp("", "revcli-cover-*.out")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create coverage profile: %w", err)
+		}
+		profilePath = profile.Name()
+		profile.Close()
+		defer os.Remove(profilePath)
+		flags = append(flags, "-coverprofile="+profilePath)
+	}
+
+	// go test exits non-zero when a test fails, so a failed run only counts when nothing was tested
+	output, runErr := gotool.NewRunner(moduleDir).Test(ctx, pkgs, flags...)
+	results := Parse(output, module)
+	if runErr != nil && len(results) == 0 {
+		return nil, nil, fmt.Errorf("%w\n%s", runErr, gotool.TrimOutput(output))
+	}
+	if !coverage {
+		return results, nil, nil
+	}
+	// The profile is empty when no package built
+	profile, err := os.ReadFile(profilePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read coverage profile: %w", err)
+	}
+	return results, ParseProfile(string(profile), module), nil
+}
+
+// Passed returns the packages whose tests passed
+func (r *Report) Passed() []PackageResult {
+	return r.withStatus(StatusPass)
+}
+
+// Failed returns the packages with a failing test, build or timeout
+func (r *Report) Failed() []PackageResult {
+	return r.withStatus(StatusFail)
+}
+
+// Summary returns the package counts
+func (r *Report) Summary() string {
+	return fmt.Sprintf(SummaryFormat, len(r.Passed()), len(r.Failed()), len(r.withStatus(StatusSkip)))
+}
+
+// Sections renders the failing tests and their output, then the uncovered changed lines, as prompt sections
+// Returns nil for a nil report or one without tested packages
+func (r *Report) Sections() []prompt.Section {
+	if r == nil || len(r.Passed())+len(r.Failed()) == 0 {
+		return nil
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf(SectionIntroFormat, len(r.Passed()), len(r.Failed())))
+	if r.TimedOut {
+		builder.WriteString(TimedOutNote)
+	}
+	for _, pkg := range r.Failed() {
+		builder.WriteString(fmt.Sprintf("\nFAIL `%s`\n", pkg.Package))
+		for _, test := range pkg.FailedTests[:min(len(pkg.FailedTests), MaxFailedTests)] {
+			builder.WriteString(fmt.Sprintf("- %s\n", test))
+		}
+		if omitted := len(pkg.FailedTests) - MaxFailedTests; omitted > 0 {
+			builder.WriteString(fmt.Sprintf("- (%d more)\n", omitted))
+		}
+		if pkg.Output != "" {
+			builder.WriteString("```text\n" + pkg.Output + "\n```\n")
+		}
+	}
+	if passed := r.Passed(); len(passed) > 0 {
+		names := lo.Map(passed, func(p PackageResult, _ int) string { return "`" + p.Package + "`" })
+		builder.WriteString(fmt.Sprintf("\nPassed: %s\n", strings.Join(names, ", ")))
+	}
+	return append([]prompt.Section{{Title: SectionTitle, Body: builder.String()}}, r.Coverage.Sections()...)
+}
+
+// withStatus returns the packages with a status
+func (r *Report) withStatus(status Status) []PackageResult {
+	if r == nil {
+		return nil
+	}
+	return lo.Filter(r.Packages, func(p PackageResult, _ int) bool { return p.Status == status })
+}
+
+// shortName returns a package's path in the module
+func shortName(pkg, module string) string {
+	if module == "" {
+		return pkg
+	}
+	if pkg == module {
+		return "."
+	}
+	if rest, ok := strings.CutPrefix(pkg, module+"/"); ok {
+		return rest
+	}
+	return pkg
+}
+
+// sortResults orders packages by import path
+func sortResults(results []PackageResult) {
+	slices.SortFunc(results, func(a, b PackageResult) int { return cmp.Compare(a.Package, b.Package) })
+}
@@ -0,0 +1,197 @@
+package testrun
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":          "module example.com/app\n\ngo 1.22\n",
+		"ok/ok.go":        "package ok\n\nfunc Abs(x int) int {\n\tif x < 0 {\n\t\treturn -x\n\t}\n\treturn x\n}\n",
+		"ok/ok_test.go":   "package ok\n\nimport \"testing\"\n\nfunc TestOK(t *testing.T) { Abs(1) }\n",
+		"bad/bad.go":      "package bad\n",
+		"bad/bad_test.go": "package bad\n\nimport \"testing\"\n\nfunc TestBad(t *testing.T) { t.Fatal(\"boom\") }\n",
+	}
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, path), []byte(content), 0o644))
+	}
+	diff := "diff --git a/ok/ok.go b/ok/ok.go\n--- a/ok/ok.go\n+++ b/ok/ok.go\n@@ -1,2 +1,8 @@\n package ok\n \n" +
+		"+func Abs(x int) int {\n+\tif x < 0 {\n+\t\treturn -x\n+\t}\n+\treturn x\n+}\n" +
+		"diff --git a/bad/bad.go b/bad/bad.go\n--- a/bad/bad.go\n+++ b/bad/bad.go\n" +
+		"diff --git a/gone/gone.go b/gone/gone.go\n--- a/gone/gone.go\n+++ /dev/null\n" +
+		"diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n"
+
+	report, err := Run(dir, "diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n", true)
+	require.NoError(t, err)
+	require.Nil(t, report)
+
+	report, err = Run(dir, diff, false)
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.False(t, report.TimedOut)
+	require.Len(t, report.Packages, 2)
+	require.Equal(t, "bad", report.Packages[0].Name)
+	require.Equal(t, StatusFail, report.Packages[0].Status)
+	require.Equal(t, []string{"TestBad"}, report.Packages[0].FailedTests)
+	require.Contains(t, report.Packages[0].Output, "boom")
+	require.Equal(t, "ok", report.Packages[1].Name)
+	require.Equal(t, StatusPass, report.Packages[1].Status)
+	require.Nil(t, report.Coverage)
+
+	report, err = Run(dir, diff, true)
+	require.NoError(t, err)
+	require.NotNil(t, report.Coverage)
+	require.Equal(t, []FileCoverage{{Path: "ok/ok.go", Covered: 3, Total: 5, Uncovered: []int{5, 6}}}, report.Coverage.Files)
+	require.InDelta(t, 60.0, report.Coverage.Percent, 0.01)
+}
+
+func TestRunNestedModule(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                "module example.com/app\n\ngo 1.22\n",
+		"tools/go.mod":          "module example.com/tools\n\ngo 1.22\n",
+		"tools/gen/gen.go":      "package gen\n\nfunc Gen(ok bool) int {\n\tif ok {\n\t\treturn 1\n\t}\n\treturn 0\n}\n",
+		"tools/gen/gen_test.go": "package gen\n\nimport \"testing\"\n\nfunc TestGen(t *testing.T) { Gen(true) }\n",
+	}
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, path), []byte(content), 0o644))
+	}
+	diff := "diff --git a/tools/gen/gen.go b/tools/gen/gen.go\n--- a/tools/gen/gen.go\n+++ b/tools/gen/gen.go\n" +
+		"@@ -1,2 +1,8 @@\n package gen\n \n+func Gen(ok bool) int {\n+\tif ok {\n+\t\treturn 1\n+\t}\n+\treturn 0\n+}\n"
+
+	// The package is tested from its own module's root, and its paths stay repo-relative
+	report, err := Run(dir, diff, true)
+	require.NoError(t, err)
+	require.Len(t, report.Packages, 1)
+	require.Equal(t, "example.com/tools/gen", report.Packages[0].Package)
+	require.Equal(t, "tools/gen", report.Packages[0].Name)
+	require.Equal(t, StatusPass, report.Packages[0].Status)
+	require.NotNil(t, report.Coverage)
+	require.Equal(t, []FileCoverage{{Path: "tools/gen/gen.go", Covered: 4, Total: 5, Uncovered: []int{7}}}, report.Coverage.Files)
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		output string
+		want   []PackageResult
+	}{
+		{
+			name: "pass and skip",
+			output: `{"Action":"start","Package":"example.com/app/ok"}
+{"Action":"output","Package":"example.com/app/ok","Test":"TestOK","Output":"=== RUN   TestOK\n"}
+{"Action":"pass","Package":"example.com/app/ok","Test":"TestOK"}
+{"Action":"pass","Package":"example.com/app/ok"}
+{"Action":"output","Package":"example.com/app/none","Output":"?   \texample.com/app/none\t[no test files]\n"}
+{"Action":"skip","Package":"example.com/app/none"}`,
+			want: []PackageResult{
+				{Package: "example.com/app/none", Name: "none", Status: StatusSkip},
+				{Package: "example.com/app/ok", Name: "ok", Status: StatusPass},
+			},
+		},
+		{
+			name: "failing subtest",
+			output: `{"Action":"run","Package":"example.com/app","Test":"TestBad"}
+{"Action":"output","Package":"example.com/app","Test":"TestBad","Output":"=== RUN   TestBad\n"}
+{"Action":"output","Package":"example.com/app","Test":"TestBad/sub","Output":"=== RUN   TestBad/sub\n"}
+{"Action":"output","Package":"example.com/app","Test":"TestBad/sub","Output":"    bad_test.go:3: boom\n"}
+{"Action":"output","Package":"example.com/app","Test":"TestBad/sub","Output":"--- FAIL: TestBad/sub (0.00s)\n"}
+{"Action":"fail","Package":"example.com/app","Test":"TestBad/sub"}
+{"Action":"output","Package":"example.com/app","Test":"TestBad","Output":"--- FAIL: TestBad (0.00s)\n"}
+{"Action":"fail","Package":"example.com/app","Test":"TestBad"}
+{"Action":"output","Package":"example.com/app","Test":"TestFine","Output":"--- PASS: TestFine (0.00s)\n"}
+{"Action":"pass","Package":"example.com/app","Test":"TestFine"}
+{"Action":"output","Package":"example.com/app","Output":"FAIL\n"}
+{"Action":"fail","Package":"example.com/app"}`,
+			want: []PackageResult{{
+				Package:     "example.com/app",
+				Name:        ".",
+				Status:      StatusFail,
+				FailedTests: []string{"TestBad/sub", "TestBad"},
+				Output:      "bad_test.go:3: boom\n--- FAIL: TestBad/sub (0.00s)\n--- FAIL: TestBad (0.00s)",
+			}},
+		},
+		{
+			name: "build failure",
+			output: `{"ImportPath":"example.com/app/broken [example.com/app/broken.test]","Action":"build-output","Output":"# example.com/app/broken [example.com/app/broken.test]\n"}
+{"ImportPath":"example.com/app/broken [example.com/app/broken.test]","Action":"build-output","Output":"broken/broken.go:2:12: undefined: undefined\n"}
+{"ImportPath":"example.com/app/broken [example.com/app/broken.test]","Action":"build-fail"}
+{"Action":"start","Package":"example.com/app/broken"}
+{"Action":"output","Package":"example.com/app/broken","Output":"FAIL\texample.com/app/broken [build failed]\n"}
+{"Action":"fail","Package":"example.com/app/broken","FailedBuild":"example.com/app/broken [example.com/app/broken.test]"}`,
+			want: []PackageResult{{
+				Package: "example.com/app/broken",
+				Name:    "broken",
+				Status:  StatusFail,
+				Output:  "# example.com/app/broken [example.com/app/broken.test]\nbroken/broken.go:2:12: undefined: undefined\nFAIL\texample.com/app/broken [build failed]",
+			}},
+		},
+		{
+			name: "unfinished package and stray output",
+			output: `# example.com/app/slow
+panic: test timed out after 5m0s
+{"Action":"start","Package":"example.com/app/slow"}
+{"Action":"run","Package":"example.com/app/slow","Test":"TestSlow"}`,
+			want: []PackageResult{{
+				Package: "example.com/app/slow",
+				Name:    "slow",
+				Status:  StatusFail,
+				Output:  "# example.com/app/slow\npanic: test timed out after 5m0s",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, Parse(tt.output, "example.com/app"))
+		})
+	}
+}
+
+func TestSections(t *testing.T) {
+	t.Parallel()
+
+	var nilReport *Report
+	require.Nil(t, nilReport.Sections())
+	require.Nil(t, (&Report{Packages: []PackageResult{{Package: "example.com/app/none", Status: StatusSkip}}}).Sections())
+
+	report := &Report{
+		Packages: []PackageResult{
+			{Package: "example.com/app/bad", Name: "bad", Status: StatusFail, FailedTests: []string{"TestBad"}, Output: "boom"},
+			{Package: "example.com/app/ok", Name: "ok", Status: StatusPass},
+			{Package: "example.com/app/none", Name: "none", Status: StatusSkip},
+		},
+		TimedOut: true,
+	}
+	require.Equal(t, "Tests: 1 passed, 1 failed, 1 without tests", report.Summary())
+
+	sections := report.Sections()
+	require.Len(t, sections, 1)
+	require.Equal(t, SectionTitle, sections[0].Title)
+	require.Contains(t, sections[0].Body, TimedOutNote)
+	require.Contains(t, sections[0].Body, "FAIL `example.com/app/bad`\n- TestBad\n```text\nboom\n```")
+	require.Contains(t, sections[0].Body, "Passed: `example.com/app/ok`")
+}
+
+func TestShortName(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, ".", shortName("example.com/app", "example.com/app"))
+	require.Equal(t, "internal/git", shortName("example.com/app/internal/git", "example.com/app"))
+	require.Equal(t, "example.com/other", shortName("example.com/other", "example.com/app"))
+	require.Equal(t, "example.com/app/git", shortName("example.com/app/git", ""))
+}
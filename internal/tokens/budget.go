@@ -0,0 +1,190 @@
+package tokens
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// Priority orders review context; lower values are packed first
+type Priority int
+
+const (
+	// PriorityDiff is the diff itself, which is always sent
+	PriorityDiff Priority = iota
+	// PriorityChangedFile is the full content of a changed file
+	PriorityChangedFile
+	// PriorityRelated is extra context such as prompt sections and related files
+	PriorityRelated
+)
+
+// String returns the report label of a priority
+func (p Priority) String() string {
+	switch p {
+	case PriorityDiff:
+		return "diff"
+	case PriorityChangedFile:
+		return "changed file"
+	default:
+		return "related"
+	}
+}
+
+// Decision is what the packer did with an item
+type Decision string
+
+const (
+	DecisionIncluded   Decision = "included"
+	DecisionSummarized Decision = "summarized"
+	DecisionDropped    Decision = "dropped"
+)
+
+// Item is one piece of review context competing for the budget
+type Item struct {
+	Name     string
+	Priority Priority
+	Content  string
+	// Summary replaces Content when the full content doesn't fit ("" drops the item instead)
+	Summary string
+}
+
+// Entry records the packer's decision for one item
+type Entry struct {
+	Name     string
+	Priority Priority
+	Decision Decision
+	// Tokens is what the item costs in the prompt (0 when dropped)
+	Tokens int
+	// FullTokens is what the full content would have cost
+	FullTokens int
+}
+
+// Budget is the token budget of the selected model
+type Budget struct {
+	// ContextWindow is the model's context window
+	ContextWindow int
+	// Reserved is kept free for the response, system prompt and review instructions
+	Reserved  int
+	Tokenizer *Tokenizer
+}
+
+// NewBudget creates the budget of a model
+// contextWindow and maxOutput come from the model metadata; zero values fall back to defaults
+func NewBudget(contextWindow, maxOutput int64, family Family) Budget {
+	if contextWindow <= 0 {
+		contextWindow = DefaultContextWindow
+	}
+	if maxOutput <= 0 {
+		maxOutput = DefaultOutputReserve
+	}
+	return Budget{
+		ContextWindow: int(contextWindow),
+		Reserved:      int(maxOutput) + PromptOverhead,
+		Tokenizer:     NewTokenizer(family),
+	}
+}
+
+// Available returns the tokens left for review context
+func (b Budget) Available() int {
+	return max(b.ContextWindow-b.Reserved, 0)
+}
+
+// Pack fits items into the budget by priority
+// - the diff is always included, even when it alone exceeds the budget
+// - other items are included in full, then as their summary, then dropped
+// - items of the same priority keep their order; a dropped item doesn't stop smaller ones after it
+func (b Budget) Pack(items []Item) *Plan {
+	ordered := slices.Clone(items)
+	slices.SortStableFunc(ordered, func(x, y Item) int { return cmp.Compare(x.Priority, y.Priority) })
+
+	plan := &Plan{Budget: b}
+	available := b.Available()
+	for _, item := range ordered {
+		entry := Entry{
+			Name:       item.Name,
+			Priority:   item.Priority,
+			FullTokens: b.Tokenizer.Count(item.Content) + ItemOverhead,
+		}
+		summaryTokens := b.Tokenizer.Count(item.Summary) + ItemOverhead
+
+		switch {
+		case item.Priority == PriorityDiff || plan.Used+entry.FullTokens <= available:
+			entry.Decision = DecisionIncluded
+			entry.Tokens = entry.FullTokens
+		case item.Summary != "" && plan.Used+summaryTokens <= available:
+			entry.Decision = DecisionSummarized
+			entry.Tokens = summaryTokens
+		default:
+			entry.Decision = DecisionDropped
+		}
+		plan.Used += entry.Tokens
+		plan.Entries = append(plan.Entries, entry)
+	}
+	return plan
+}
+
+// Plan is the outcome of packing review context into a budget
+type Plan struct {
+	Budget  Budget
+	Entries []Entry
+	// Used is the number of tokens the packed items cost
+	Used int
+}
+
+// Summarized returns the items that were replaced by their summary
+func (p *Plan) Summarized() []Entry {
+	return p.withDecision(DecisionSummarized)
+}
+
+// Dropped returns the items that were left out
+func (p *Plan) Dropped() []Entry {
+	return p.withDecision(DecisionDropped)
+}
+
+// Decision returns what the packer did with the named item (DecisionDropped when it wasn't packed)
+func (p *Plan) Decision(name string) Decision {
+	entry, ok := lo.Find(p.Entries, func(e Entry) bool { return e.Name == name })
+	if !ok {
+		return DecisionDropped
+	}
+	return entry.Decision
+}
+
+// withDecision returns the entries with one decision
+func (p *Plan) withDecision(decision Decision) []Entry {
+	return lo.Filter(p.Entries, func(e Entry, _ int) bool { return e.Decision == decision })
+}
+
+// Overflow returns how many tokens the plan exceeds the budget by (0 when it fits)
+func (p *Plan) Overflow() int {
+	return max(p.Used-p.Budget.Available(), 0)
+}
+
+// Fits reports whether everything was included in full within the budget
+func (p *Plan) Fits() bool {
+	return p.Overflow() == 0 && len(p.Summarized()) == 0 && len(p.Dropped()) == 0
+}
+
+// Warning returns a warning when the diff alone doesn't fit the budget, or ""
+func (p *Plan) Warning() string {
+	if p.Overflow() == 0 {
+		return ""
+	}
+	return fmt.Sprintf(OverflowWarningFormat, p.Used, p.Budget.Available(), p.Budget.ContextWindow)
+}
+
+// Report describes the budget and every item that was summarized or dropped
+func (p *Plan) Report() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf(BudgetLineFormat, p.Used, p.Budget.Available(), p.Budget.ContextWindow, p.Budget.Tokenizer.Family()))
+	for _, e := range p.Summarized() {
+		builder.WriteString(fmt.Sprintf(SummarizedLineFormat, e.Name, e.Priority, e.FullTokens, e.Tokens))
+	}
+	for _, e := range p.Dropped() {
+		builder.WriteString(fmt.Sprintf(DroppedLineFormat, e.Name, e.Priority, e.FullTokens))
+	}
+	return builder.String()
+}
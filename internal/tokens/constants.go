@@ -0,0 +1,23 @@
+package tokens
+
+// DefaultContextWindow is used when the selected model's context window is unknown
+const DefaultContextWindow = 128000
+
+// DefaultOutputReserve is kept free for the response when the model has no max tokens
+const DefaultOutputReserve = 8192
+
+// PromptOverhead is kept free for the system prompt, tool definitions and review instructions
+const PromptOverhead = 12000
+
+// ItemOverhead covers the heading and code fence wrapped around every packed item
+const ItemOverhead = 16
+
+// Plan report lines
+const (
+	BudgetLineFormat     = "Token budget: %d of %d tokens used (%d context window, %s tokenizer)\n"
+	SummarizedLineFormat = "Summarized %s (%s): %d → %d tokens\n"
+	DroppedLineFormat    = "Dropped %s (%s): %d tokens\n"
+)
+
+// OverflowWarningFormat warns that the diff alone exceeds the budget
+const OverflowWarningFormat = "Warning: review context needs ~%d tokens but only %d fit in the %d-token context window. Consider reviewing fewer files."
@@ -0,0 +1,125 @@
+// Package tokens approximates provider tokenizers and packs review context into a model's token budget
+package tokens
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+)
+
+// Family groups models that share a tokenizer
+type Family string
+
+const (
+	// FamilyOpenAI covers GPT and o-series models (o200k/cl100k BPE)
+	FamilyOpenAI Family = "openai"
+	// FamilyAnthropic covers Claude models
+	FamilyAnthropic Family = "anthropic"
+	// FamilyGemini covers Gemini and Gemma models (SentencePiece)
+	FamilyGemini Family = "gemini"
+	// FamilyGeneric is a conservative fallback for unknown models
+	FamilyGeneric Family = "generic"
+)
+
+// profile holds how many characters of each piece class fit in one token
+type profile struct {
+	letters     float64
+	digits      float64
+	punctuation float64
+	whitespace  float64
+}
+
+// profiles are calibrated on Go source and English review prose
+var profiles = map[Family]profile{
+	// BPE merges common words whole and groups digits by three
+	FamilyOpenAI: {letters: 6, digits: 3, punctuation: 2, whitespace: 8},
+	// Claude's vocabulary is smaller, so words and symbols split more often
+	FamilyAnthropic: {letters: 5, digits: 3, punctuation: 1.5, whitespace: 4},
+	// SentencePiece splits every digit and merges indentation runs
+	FamilyGemini:  {letters: 5.5, digits: 1, punctuation: 1.8, whitespace: 8},
+	FamilyGeneric: {letters: 5, digits: 1, punctuation: 1.5, whitespace: 4},
+}
+
+// pieceRe splits text the way BPE pre-tokenizers do: contractions, words with one leading
+// non-letter, digit runs, punctuation runs and whitespace runs
+var pieceRe = regexp.MustCompile(`'(?:[sdmt]|ll|ve|re)|[^\r\n\pL\pN]?\pL+|\pN+|[^\s\pL\pN]+|\s+`)
+
+// Tokenizer approximates the token count of one model family
+type Tokenizer struct {
+	family  Family
+	profile profile
+}
+
+// NewTokenizer creates a tokenizer for a model family; unknown families use FamilyGeneric
+func NewTokenizer(family Family) *Tokenizer {
+	p, ok := profiles[family]
+	if !ok {
+		family = FamilyGeneric
+		p = profiles[FamilyGeneric]
+	}
+	return &Tokenizer{family: family, profile: p}
+}
+
+// Family returns the tokenizer's model family
+func (t *Tokenizer) Family() Family {
+	return t.family
+}
+
+// Count approximates the number of tokens in text
+func (t *Tokenizer) Count(text string) int {
+	count := 0
+	for _, piece := range pieceRe.FindAllString(text, -1) {
+		count += t.countPiece(piece)
+	}
+	return count
+}
+
+// countPiece approximates the tokens of one pre-tokenized piece
+func (t *Tokenizer) countPiece(piece string) int {
+	first, _ := utf8.DecodeRuneInString(piece)
+	perToken := t.profile.letters
+	switch {
+	case strings.TrimSpace(piece) == "":
+		perToken = t.profile.whitespace
+	case unicode.IsNumber(first):
+		perToken = t.profile.digits
+	case !strings.ContainsFunc(piece, unicode.IsLetter):
+		perToken = t.profile.punctuation
+	}
+	return int(math.Ceil(float64(utf8.RuneCountInString(piece)) / perToken))
+}
+
+// FamilyFor picks the tokenizer family of a model
+// The model ID wins over the provider type, since gateways (OpenRouter, Bedrock, ...) serve several families
+func FamilyFor(providerType catwalk.Type, modelID string) Family {
+	id := strings.ToLower(modelID)
+	switch {
+	case strings.Contains(id, "claude"):
+		return FamilyAnthropic
+	case strings.Contains(id, "gemini"), strings.Contains(id, "gemma"):
+		return FamilyGemini
+	case strings.Contains(id, "gpt"), isOSeries(id):
+		return FamilyOpenAI
+	}
+
+	switch providerType {
+	case catwalk.TypeAnthropic, catwalk.TypeBedrock:
+		return FamilyAnthropic
+	case catwalk.TypeGoogle, catwalk.TypeVertexAI:
+		return FamilyGemini
+	case catwalk.TypeOpenAI, catwalk.TypeAzure:
+		return FamilyOpenAI
+	default:
+		return FamilyGeneric
+	}
+}
+
+// isOSeries reports whether a model ID names an OpenAI reasoning model (o1, o3-mini, openai/o4-mini, ...)
+func isOSeries(id string) bool {
+	id = id[strings.LastIndex(id, "/")+1:]
+	return len(id) >= 2 && id[0] == 'o' && unicode.IsDigit(rune(id[1]))
+}
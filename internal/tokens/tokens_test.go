@@ -0,0 +1,121 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenizerCount(t *testing.T) {
+	t.Parallel()
+
+	openai := NewTokenizer(FamilyOpenAI)
+	require.Zero(t, openai.Count(""))
+	require.Equal(t, 2, openai.Count("hello world"))
+	// Digit runs are grouped by three for OpenAI and split per digit for Gemini
+	require.Equal(t, 2, openai.Count("123456"))
+	require.Equal(t, 6, NewTokenizer(FamilyGemini).Count("123456"))
+
+	code := strings.Repeat("func (s *Server) Handle(ctx context.Context, req *Request) error {\n\treturn nil\n}\n", 50)
+	require.Less(t, openai.Count(code), NewTokenizer(FamilyAnthropic).Count(code))
+	require.Less(t, openai.Count(code), len(code)/2)
+
+	require.Equal(t, FamilyGeneric, NewTokenizer("unknown").Family())
+}
+
+func TestFamilyFor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		providerType catwalk.Type
+		modelID      string
+		want         Family
+	}{
+		{catwalk.TypeAnthropic, "claude-sonnet-4", FamilyAnthropic},
+		{catwalk.TypeOpenRouter, "anthropic/claude-3.5-haiku", FamilyAnthropic},
+		{catwalk.TypeOpenRouter, "openai/o4-mini", FamilyOpenAI},
+		{catwalk.TypeOpenAI, "gpt-4o", FamilyOpenAI},
+		{catwalk.TypeOpenAICompat, "gemini-2.5-pro", FamilyGemini},
+		{catwalk.TypeVertexAI, "custom-model", FamilyGemini},
+		{catwalk.TypeBedrock, "custom-model", FamilyAnthropic},
+		{catwalk.TypeOpenAICompat, "qwen3-coder", FamilyGeneric},
+	}
+	for _, tt := range tests {
+		t.Run(tt.modelID, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, FamilyFor(tt.providerType, tt.modelID))
+		})
+	}
+}
+
+func TestNewBudget(t *testing.T) {
+	t.Parallel()
+
+	budget := NewBudget(200000, 64000, FamilyAnthropic)
+	require.Equal(t, 200000-64000-PromptOverhead, budget.Available())
+
+	fallback := NewBudget(0, 0, FamilyGeneric)
+	require.Equal(t, DefaultContextWindow, fallback.ContextWindow)
+	require.Equal(t, DefaultOutputReserve+PromptOverhead, fallback.Reserved)
+
+	require.Zero(t, NewBudget(1000, 4000, FamilyGeneric).Available())
+}
+
+func TestPack(t *testing.T) {
+	t.Parallel()
+
+	tokenizer := NewTokenizer(FamilyGeneric)
+	large := strings.Repeat("word ", 400)
+	small := strings.Repeat("word ", 20)
+	budget := Budget{
+		ContextWindow: tokenizer.Count(large) + 200,
+		Reserved:      0,
+		Tokenizer:     tokenizer,
+	}
+
+	plan := budget.Pack([]Item{
+		{Name: "related", Priority: PriorityRelated, Content: large},
+		{Name: "big.go", Priority: PriorityChangedFile, Content: large, Summary: "func Big()"},
+		{Name: "huge.txt", Priority: PriorityChangedFile, Content: large + large},
+		{Name: "diff", Priority: PriorityDiff, Content: large},
+		{Name: "small.go", Priority: PriorityChangedFile, Content: small},
+	})
+
+	require.Equal(t, []string{"diff", "big.go", "huge.txt", "small.go", "related"}, entryNames(plan.Entries))
+	require.Equal(t, DecisionIncluded, plan.Decision("diff"))
+	require.Equal(t, DecisionSummarized, plan.Decision("big.go"))
+	require.Equal(t, DecisionDropped, plan.Decision("huge.txt"))
+	// A dropped file doesn't stop smaller files after it from fitting
+	require.Equal(t, DecisionIncluded, plan.Decision("small.go"))
+	require.Equal(t, DecisionDropped, plan.Decision("related"))
+	require.Equal(t, DecisionDropped, plan.Decision("missing"))
+	require.Zero(t, plan.Overflow())
+	require.Empty(t, plan.Warning())
+
+	report := plan.Report()
+	require.Contains(t, report, "Summarized big.go (changed file)")
+	require.Contains(t, report, "Dropped huge.txt (changed file)")
+	require.Contains(t, report, "Dropped related (related)")
+	require.NotContains(t, report, "small.go")
+}
+
+func TestPackDiffOverflow(t *testing.T) {
+	t.Parallel()
+
+	budget := Budget{ContextWindow: 10, Tokenizer: NewTokenizer(FamilyGeneric)}
+	plan := budget.Pack([]Item{{Name: "diff", Priority: PriorityDiff, Content: strings.Repeat("word ", 100)}})
+
+	require.Equal(t, DecisionIncluded, plan.Decision("diff"))
+	require.Positive(t, plan.Overflow())
+	require.NotEmpty(t, plan.Warning())
+}
+
+func entryNames(entries []Entry) []string {
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	return names
+}
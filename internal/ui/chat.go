@@ -36,9 +36,10 @@ type ChatMessage struct {
 }
 
 // SendChatMessage sends a follow-up question using coordinator
-func SendChatMessage(ctx context.Context, appInstance *app.App, sessionID, question string) tea.Cmd {
+// rejected holds the diffs of rejected suggestions (may be nil)
+func SendChatMessage(ctx context.Context, appInstance *app.App, sessionID, question string, rejected []string) tea.Cmd {
 	return func() tea.Msg {
-		followUp := prompt.BuildFollowUpPrompt(question)
+		followUp := prompt.BuildFollowUpPrompt(question, rejected)
 
 		result, err := appInstance.AgentCoordinator.Run(ctx, sessionID, followUp)
 		if err != nil {
@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/app"
+	"github.com/trankhanh040147/revcli/internal/chunk"
+	appcontext "github.com/trankhanh040147/revcli/internal/context"
+)
+
+// coordinatorReviewFunc reviews a chunk through the coordinator, in a task session of the review session
+// with the reviewer's system prompt and read-only tools: chunks run in the background, where no one can approve a tool call
+// onToolCall, if set, is called with each tool call the chunk's reviewer makes
+func coordinatorReviewFunc(appInstance *app.App, sessionID, systemPrompt string, toolBudget int, onToolCall func(string)) chunk.ReviewFunc {
+	return func(ctx context.Context, c *appcontext.Chunk) (string, error) {
+		title := fmt.Sprintf(ChunkSessionTitleFormat, c.Index, c.Total)
+		chunkSession, err := appInstance.Sessions.CreateTaskSession(ctx, uuid.NewString(), sessionID, title)
+		if err != nil {
+			return "", fmt.Errorf("failed to create chunk session: %w", err)
+		}
+		appInstance.AgentCoordinator.SetSystemPrompt(chunkSession.ID, systemPrompt)
+		defer appInstance.AgentCoordinator.SetSystemPrompt(chunkSession.ID, "")
+		appInstance.AgentCoordinator.SetReadOnlyTools(chunkSession.ID, ReadOnlyToolPolicy(toolBudget))
+		defer appInstance.AgentCoordinator.SetReadOnlyTools(chunkSession.ID, nil)
+
+		if onToolCall != nil {
+			// Stop reporting before returning: the caller closes its channel once every chunk is reviewed
+			watchCtx, stopWatching := context.WithCancel(ctx)
+			watchDone := make(chan struct{})
+			events := appInstance.Messages.Subscribe(watchCtx)
+			go func() {
+				defer close(watchDone)
+				reportToolCalls(watchCtx, events, chunkSession.ID, onToolCall)
+			}()
+			defer func() {
+				stopWatching()
+				<-watchDone
+			}()
+		}
+
+		result, err := appInstance.AgentCoordinator.Run(ctx, chunkSession.ID, c.Prompt, fileAttachments(c.Attachments)...)
+		if err != nil {
+			return "", err
+		}
+		return result.Response.Content.Text(), nil
+	}
+}
+
+// reviewChunksCmd reviews the chunks concurrently, reporting progress, and prepares the merge step
+func reviewChunksCmd(ctx context.Context, appInstance *app.App, sessionID string, reviewCtx *appcontext.ReviewContext) tea.Cmd {
+	return func() tea.Msg {
+		// Every chunk reports at most twice, so progress never blocks the review
+		progressChan := make(chan chunk.Progress, 2*len(reviewCtx.Chunks))
+		doneChan := make(chan ChunksDoneMsg, 1)
+		toolCallChan := make(chan string, 100)
+
+		onToolCall := func(call string) {
+			select {
+			case toolCallChan <- call:
+			case <-ctx.Done():
+			}
+		}
+
+		go func() {
+			results, err := chunk.Review(ctx, reviewCtx.Chunks, coordinatorReviewFunc(appInstance, sessionID, reviewCtx.SystemPrompt, reviewCtx.ToolBudget, onToolCall), func(p chunk.Progress) {
+				progressChan <- p
+			})
+			close(progressChan)
+			close(toolCallChan)
+			if err != nil {
+				doneChan <- ChunksDoneMsg{Err: err}
+				return
+			}
+			doneChan <- ChunksDoneMsg{MergePrompt: chunk.MergePrompt(results, reviewCtx.FileContents)}
+		}()
+
+		return ChunksStartMsg{ProgressChan: progressChan, DoneChan: doneChan, ToolCallChan: toolCallChan}
+	}
+}
+
+// pendingChunks returns the initial progress of a chunked review
+func pendingChunks(chunks []*appcontext.Chunk) []chunk.Progress {
+	return lo.Map(chunks, func(c *appcontext.Chunk, _ int) chunk.Progress {
+		return chunk.Progress{Chunk: c, Status: chunk.StatusPending}
+	})
+}
+
+// chunkProgressCmd waits for the next chunk status change
+func chunkProgressCmd(progressChan chan chunk.Progress) tea.Cmd {
+	return func() tea.Msg {
+		progress, ok := <-progressChan
+		if !ok {
+			return nil
+		}
+		return ChunkProgressMsg{Progress: progress}
+	}
+}
+
+// chunksDoneCmd waits for every chunk to finish
+func chunksDoneCmd(doneChan chan ChunksDoneMsg) tea.Cmd {
+	return func() tea.Msg {
+		done, ok := <-doneChan
+		if !ok {
+			return nil
+		}
+		return done
+	}
+}
+
+// handleChunkMessages tracks chunk progress and streams the merge step once every chunk is reviewed
+// Returns (model, cmd, shouldReturnEarly)
+func (m *Model) handleChunkMessages(msg tea.Msg) (*Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case ChunksStartMsg:
+		m.chunkProgressChan = msg.ProgressChan
+		m.streamToolCallChan = msg.ToolCallChan
+		return m, tea.Batch(chunkProgressCmd(msg.ProgressChan), chunksDoneCmd(msg.DoneChan), streamToolCallCmd(msg.ToolCallChan)), true
+	case ChunkProgressMsg:
+		m.chunkProgress[msg.Progress.Chunk.Index-1] = msg.Progress
+		return m, chunkProgressCmd(m.chunkProgressChan), true
+	case ChunksDoneMsg:
+		if msg.Err != nil {
+			return m, func() tea.Msg { return ReviewErrorMsg{Err: msg.Err} }, true
+		}
+		ctx, cancel := context.WithCancel(m.rootCtx)
+		m.activeCancel = cancel
+		return m, streamReviewCmd(ctx, m.app, m.sessionID, msg.MergePrompt, nil), true
+	}
+	return m, nil, false
+}
+
+// renderChunkProgress renders one line per chunk with its review status
+func renderChunkProgress(progress []chunk.Progress) string {
+	var s strings.Builder
+	for _, p := range progress {
+		line := fmt.Sprintf("   %s %s", chunkStatusIcons[p.Status], chunk.Title(p.Chunk))
+		if p.Err != nil {
+			line += ": " + p.Err.Error()
+		}
+		s.WriteString(line)
+		s.WriteString("\n")
+	}
+	return s.String()
+}
+
+// reviewChunksSimple reviews the chunks in non-interactive mode, printing progress, and returns the merge prompt
+func reviewChunksSimple(ctx context.Context, w io.Writer, reviewCtx *appcontext.ReviewContext, appInstance *app.App, sessionID string) (string, error) {
+	fmt.Fprintf(w, ChunkedReviewFormat+"\n", len(reviewCtx.Chunks))
+
+	// Progress is reported from concurrent chunk reviews
+	var mu sync.Mutex
+	results, err := chunk.Review(ctx, reviewCtx.Chunks, coordinatorReviewFunc(appInstance, sessionID, reviewCtx.SystemPrompt, reviewCtx.ToolBudget, nil), func(p chunk.Progress) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprint(w, renderChunkProgress([]chunk.Progress{p}))
+	})
+	if err != nil {
+		return "", fmt.Errorf("chunked review failed: %w", err)
+	}
+
+	fmt.Fprintln(w, ChunksMergingFeedback)
+	fmt.Fprintln(w)
+	return chunk.MergePrompt(results, reviewCtx.FileContents), nil
+}
@@ -1,18 +1,73 @@
 package ui
 
-import "time"
+import (
+	"time"
+
+	"github.com/trankhanh040147/revcli/internal/chunk"
+)
 
 // UI feedback durations
 const (
-	YankFeedbackDuration      = 2 * time.Second
+	YankFeedbackDuration       = 2 * time.Second
 	PruneErrorFeedbackDuration = 3 * time.Second
-	YankChordTimeout          = 300 * time.Millisecond
+	YankChordTimeout           = 300 * time.Millisecond
+	PatchFeedbackDuration      = 4 * time.Second
 )
 
-// PruneFilePromptTemplate is the template for file summarization prompts
-const PruneFilePromptTemplate = `Summarize this code file in one sentence. Focus on what the file does, its main purpose, and key functionality. Be concise.
+// Patch verification feedback
+const (
+	PatchVerifyingFeedback = "Verifying suggested patches..."
+)
 
-File: %s
+// Suggestion action feedback
+const (
+	SuggestionNoneFeedback     = "No suggested patches in this review"
+	SuggestionNothingToUndo    = "Nothing to undo"
+	SuggestionUndoPending      = "Undo in progress…"
+	SuggestionAlreadyApplied   = "Suggestion already applied (u to undo)"
+	SuggestionUnusableFeedback = "Suggestion failed verification; not applying"
+	SuggestionRejectedFeedback = "✓ Rejected suggestion %d; follow-ups won't repeat it"
+	SuggestionAppliedFeedback  = "✓ Applied suggestion %d (%s)"
+	SuggestionUndoneFeedback   = "✓ Undid suggestion %d"
+	SuggestionStatusFormat     = "Suggestion %d/%d • %s • %s • %s"
+)
+
+// Findings feedback
+const (
+	// FindingsSaveFailedFormat is shown when findings can't be stored (suppress by ID won't find them)
+	FindingsSaveFailedFormat  = "Failed to save findings: %v"
+	FindingsVerifyingFeedback = "Verifying %d finding(s)..."
+	FindingsVerifyErrorSuffix = " (some findings unverified: %v)"
+)
 
-%s`
+// Chunked review
+const (
+	ChunkedReviewFormat     = "Change too large for one review; reviewing in %d chunks..."
+	ChunksMergingFeedback   = "Merging chunk reviews..."
+	ChunkSessionTitleFormat = "Chunk %d of %d"
+)
+
+// RelatedFileDescription describes related (unchanged) files in the file list
+const RelatedFileDescription = "related · calls changed exported symbols"
+
+// FileDiagnosticsFormat describes a changed file with compiler diagnostics (size, errors, warnings)
+const FileDiagnosticsFormat = "%s · %d error(s), %d warning(s)"
+
+// TestBadgesLabel prefixes the per-package test badges
+const TestBadgesLabel = "🧪 Tests: "
+
+// Repository exploration by the reviewer
+const (
+	// ToolCallsHeaderFormat heads the reviewer's tool calls (calls made, budget)
+	ToolCallsHeaderFormat = "🔎 Exploring the repository (%d/%d tool calls)"
+	// MaxToolCallsShown caps the most recent tool calls shown while the review streams
+	MaxToolCallsShown = 5
+)
 
+// chunkStatusIcons prefix each chunk's progress line
+var chunkStatusIcons = map[chunk.Status]string{
+	chunk.StatusPending:   "·",
+	chunk.StatusReviewing: "…",
+	chunk.StatusDone:      "✓",
+	chunk.StatusFailed:    "✗",
+}
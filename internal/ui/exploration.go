@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/bytedance/sonic"
+
+	"github.com/trankhanh040147/revcli/internal/agent/tools"
+	"github.com/trankhanh040147/revcli/internal/filter"
+	"github.com/trankhanh040147/revcli/internal/message"
+	"github.com/trankhanh040147/revcli/internal/pubsub"
+)
+
+// ToolCallMsg reports a tool call the reviewer made while the review streams
+type ToolCallMsg struct {
+	Call string
+}
+
+// ReadOnlyToolPolicy is the reviewer's tool policy: read-only tools within a call budget,
+// returning only what the initial context's ignore and secret filters let through
+func ReadOnlyToolPolicy(budget int) *tools.ReadOnlyPolicy {
+	return &tools.ReadOnlyPolicy{
+		Budget: budget,
+		Ignore: filter.ShouldIgnore,
+		Redact: filter.RedactSecrets,
+	}
+}
+
+// streamToolCallCmd creates a command to listen for tool calls from a channel
+func streamToolCallCmd(toolCallChan chan string) tea.Cmd {
+	return func() tea.Msg {
+		call, ok := <-toolCallChan
+		if !ok {
+			return nil
+		}
+		return ToolCallMsg{Call: call}
+	}
+}
+
+// reportToolCalls reports each tool call the session makes once its input is complete, until ctx is done
+func reportToolCalls(ctx context.Context, events <-chan pubsub.Event[message.Message], sessionID string, report func(string)) {
+	seen := make(map[string]bool)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			msg := event.Payload
+			if msg.SessionID != sessionID || msg.Role != message.Assistant {
+				continue
+			}
+			for _, call := range msg.ToolCalls() {
+				if !call.Finished || seen[call.ID] {
+					continue
+				}
+				seen[call.ID] = true
+				report(describeToolCall(call))
+			}
+		}
+	}
+}
+
+// describeToolCall renders a tool call as its name and main argument, e.g. "view internal/ui/model.go"
+func describeToolCall(call message.ToolCall) string {
+	var params struct {
+		FilePath string `json:"file_path"`
+		Path     string `json:"path"`
+		Pattern  string `json:"pattern"`
+		Symbol   string `json:"symbol"`
+	}
+	_ = sonic.UnmarshalString(call.Input, &params)
+
+	parts := []string{call.Name}
+	if query := cmp.Or(params.Symbol, params.Pattern); query != "" {
+		parts = append(parts, fmt.Sprintf("%q", query))
+	}
+	if path := cmp.Or(params.FilePath, params.Path); path != "" {
+		parts = append(parts, path)
+	}
+	return strings.Join(parts, " ")
+}
+
+// renderToolCalls renders the reviewer's most recent tool calls under a header with the budget
+// Returns "" before the first call
+func renderToolCalls(calls []string, budget int) string {
+	if len(calls) == 0 {
+		return ""
+	}
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf(ToolCallsHeaderFormat, len(calls), budget))
+	s.WriteString("\n")
+	for _, call := range calls[max(0, len(calls)-MaxToolCallsShown):] {
+		s.WriteString(RenderHelp("   • " + call))
+		s.WriteString("\n")
+	}
+	return s.String()
+}
@@ -6,6 +6,7 @@ import (
 	"charm.land/bubbles/v2/list"
 	"charm.land/lipgloss/v2"
 	appcontext "github.com/trankhanh040147/revcli/internal/context"
+	"github.com/trankhanh040147/revcli/internal/lint"
 )
 
 // FileListItem represents an item in the file list
@@ -14,6 +15,10 @@ type FileListItem struct {
 	Size    int
 	Pruned  bool
 	Pruning bool // Whether file is currently being pruned
+	Related bool // Whether file is unchanged and only calls changed symbols
+	// Errors and Warnings count the language server diagnostics near the file's changed lines
+	Errors   int
+	Warnings int
 }
 
 // Title returns the display title for the item
@@ -27,8 +32,14 @@ func (f FileListItem) Title() string {
 	return fmt.Sprintf("%s%s", f.Path, indicator)
 }
 
-// Description returns the description (file size)
+// Description returns the description (file size and compiler diagnostics, or why a related file is listed)
 func (f FileListItem) Description() string {
+	if f.Related {
+		return RelatedFileDescription
+	}
+	if f.Errors > 0 || f.Warnings > 0 {
+		return fmt.Sprintf(FileDiagnosticsFormat, formatFileSize(f.Size), f.Errors, f.Warnings)
+	}
 	return formatFileSize(f.Size)
 }
 
@@ -54,19 +65,7 @@ func formatFileSize(bytes int) string {
 // NewFileListModel creates a new file list model from ReviewContext
 // pruningFiles may be nil if pruning state is not needed
 func NewFileListModel(reviewCtx *appcontext.ReviewContext, pruningFiles map[string]bool) list.Model {
-	items := make([]list.Item, 0, len(reviewCtx.FileContents))
-
-	for path, content := range reviewCtx.FileContents {
-		// PrunedFiles is always initialized in builder.go:91
-		_, pruned := reviewCtx.PrunedFiles[path]
-		pruning := pruningFiles != nil && pruningFiles[path]
-		items = append(items, FileListItem{
-			Path:    path,
-			Size:    len(content),
-			Pruned:  pruned,
-			Pruning: pruning,
-		})
-	}
+	items := fileListItems(reviewCtx, pruningFiles)
 
 	// Create list with custom styling
 	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
@@ -91,22 +90,34 @@ func NewFileListModel(reviewCtx *appcontext.ReviewContext, pruningFiles map[stri
 // UpdateFileListModel updates the file list model with current pruned state
 // pruningFiles may be nil if pruning state is not needed
 func UpdateFileListModel(l list.Model, reviewCtx *appcontext.ReviewContext, pruningFiles map[string]bool) list.Model {
-	items := make([]list.Item, 0, len(reviewCtx.FileContents))
+	items := fileListItems(reviewCtx, pruningFiles)
+
+	l.SetItems(items)
+	return l
+}
+
+// fileListItems lists the changed files, then the related files
+func fileListItems(reviewCtx *appcontext.ReviewContext, pruningFiles map[string]bool) []list.Item {
+	items := make([]list.Item, 0, len(reviewCtx.FileContents)+len(reviewCtx.RelatedFiles))
 
 	for path, content := range reviewCtx.FileContents {
 		// PrunedFiles is always initialized in builder.go:91
 		_, pruned := reviewCtx.PrunedFiles[path]
 		pruning := pruningFiles != nil && pruningFiles[path]
+		errors, warnings := lint.CountSeverity(lint.ForFile(reviewCtx.LSPDiagnostics, path))
 		items = append(items, FileListItem{
-			Path:    path,
-			Size:    len(content),
-			Pruned:  pruned,
-			Pruning: pruning,
+			Path:     path,
+			Size:     len(content),
+			Pruned:   pruned,
+			Pruning:  pruning,
+			Errors:   errors,
+			Warnings: warnings,
 		})
 	}
-
-	l.SetItems(items)
-	return l
+	for _, path := range reviewCtx.RelatedFiles {
+		items = append(items, FileListItem{Path: path, Related: true})
+	}
+	return items
 }
 
 // GetSelectedFile returns the currently selected file path
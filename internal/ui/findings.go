@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/trankhanh040147/revcli/internal/app"
+	"github.com/trankhanh040147/revcli/internal/config"
+	appcontext "github.com/trankhanh040147/revcli/internal/context"
+	"github.com/trankhanh040147/revcli/internal/finding"
+)
+
+// findingRun identifies the review run the findings belong to
+func findingRun(reviewCtx *appcontext.ReviewContext, sessionID string) finding.Run {
+	return finding.Run{
+		SessionID: sessionID,
+		Repo:      reviewCtx.RepoRoot,
+		Branch:    reviewCtx.Branch,
+		HeadSHA:   reviewCtx.HeadSHA,
+	}
+}
+
+// CompareLastRun loads the previous run on the same branch and compares the current findings with it
+// Returns nil (and no error) for the first run on a branch
+func CompareLastRun(ctx context.Context, findings finding.Service, reviewCtx *appcontext.ReviewContext, sessionID string, current []*finding.Finding) (*finding.Comparison, error) {
+	previous, records, err := findings.PreviousRun(ctx, findingRun(reviewCtx, sessionID))
+	if errors.Is(err, finding.ErrNoPreviousRun) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return finding.Compare(previous, records, current, reviewCtx.Suppressions, reviewCtx.Branch), nil
+}
+
+// coordinatorGenerateFunc sends verifier prompts to the configured model, outside the review session
+func coordinatorGenerateFunc(appInstance *app.App, modelType config.SelectedModelType) finding.GenerateFunc {
+	return func(ctx context.Context, systemPrompt, prompt string) (string, error) {
+		return appInstance.AgentCoordinator.Generate(ctx, modelType, systemPrompt, prompt)
+	}
+}
+
+// processFindings hides suppressed findings, runs the self-verification pass if enabled,
+// then tags the remaining findings with their ID and stores the run
+func (m *Model) processFindings() tea.Cmd {
+	visible, suppressed := finding.Filter(
+		finding.Parse(m.reviewResponse, m.reviewCtx.FileContents), m.reviewCtx.Suppressions, m.reviewCtx.Branch)
+	if m.reviewCtx.VerifyModel == "" || len(visible) == 0 {
+		return m.finishFindings(visible, suppressed, nil)
+	}
+
+	ctx, cancel := context.WithCancel(m.rootCtx)
+	m.activeCancel = cancel
+	m.yankFeedback = fmt.Sprintf(FindingsVerifyingFeedback, len(visible))
+	m.updateViewportHeight()
+	generate := coordinatorGenerateFunc(m.app, m.reviewCtx.VerifyModel)
+	return verifyFindingsCmd(ctx, visible, suppressed, m.reviewCtx.FileContents, generate)
+}
+
+// finishFindings rewrites the response, stores the run and starts patch verification
+func (m *Model) finishFindings(visible, suppressed, dropped []*finding.Finding) tea.Cmd {
+	m.reviewResponse = finding.Rewrite(m.reviewResponse, visible, suppressed, dropped)
+	m.findings = visible
+	return tea.Batch(
+		saveFindingsCmd(m.rootCtx, m.app.Findings, m.reviewCtx, m.sessionID, visible),
+		m.startPatchVerification(),
+	)
+}
+
+// verifyFindingsCmd runs the self-verification pass off the UI goroutine
+// On cancellation every finding is kept unverified
+func verifyFindingsCmd(ctx context.Context, visible, suppressed []*finding.Finding, fileContents map[string]string, generate finding.GenerateFunc) tea.Cmd {
+	return func() tea.Msg {
+		kept, dropped, err := finding.Verify(ctx, visible, fileContents, generate)
+		if kept == nil && dropped == nil {
+			kept = visible
+		}
+		return FindingsVerifiedMsg{Kept: kept, Suppressed: suppressed, Dropped: dropped, Err: err}
+	}
+}
+
+// saveFindingsCmd compares findings with the previous run and stores them, off the UI goroutine
+func saveFindingsCmd(ctx context.Context, findings finding.Service, reviewCtx *appcontext.ReviewContext, sessionID string, visible []*finding.Finding) tea.Cmd {
+	return func() tea.Msg {
+		comparison, err := CompareLastRun(ctx, findings, reviewCtx, sessionID, visible)
+		if err != nil {
+			return FindingsSavedMsg{Err: err}
+		}
+		err = findings.SaveRun(ctx, findingRun(reviewCtx, sessionID), visible)
+		return FindingsSavedMsg{Comparison: comparison, Err: err}
+	}
+}
+
+// handleFindingMessages applies verification results, badges findings relative to the previous run
+// and reports failures
+// Returns (model, cmd, shouldReturnEarly)
+func (m *Model) handleFindingMessages(msg tea.Msg) (*Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case FindingsVerifiedMsg:
+		m.activeCancel = nil
+		cmd := m.finishFindings(msg.Kept, msg.Suppressed, msg.Dropped)
+		m.updateViewport()
+		feedback := finding.VerificationSummary(msg.Kept, msg.Dropped)
+		if msg.Err != nil {
+			feedback += fmt.Sprintf(FindingsVerifyErrorSuffix, strings.ReplaceAll(msg.Err.Error(), "\n", "; "))
+		}
+		return m, tea.Batch(cmd, m.showFeedback(feedback)), true
+	case FindingsSavedMsg:
+		return m, m.handleFindingsSaved(msg), true
+	}
+	return m, nil, false
+}
+
+// handleFindingsSaved badges findings relative to the previous run
+func (m *Model) handleFindingsSaved(savedMsg FindingsSavedMsg) tea.Cmd {
+	if savedMsg.Err != nil {
+		return m.showFeedback(fmt.Sprintf(FindingsSaveFailedFormat, savedMsg.Err))
+	}
+	if savedMsg.Comparison == nil {
+		return nil
+	}
+
+	m.reviewResponse = finding.Badge(m.reviewResponse, savedMsg.Comparison)
+	m.updateViewport()
+	return m.showFeedback(savedMsg.Comparison.Summary())
+}
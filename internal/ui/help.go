@@ -66,6 +66,15 @@ func (h *HelpOverlay) Render() string {
 				{"Y", "Yank only last response"},
 			},
 		},
+		{
+			title: "Suggestions",
+			bindings: []keybinding{
+				{"[ / ]", "Previous / next suggested patch"},
+				{"a", "Apply selected patch to disk"},
+				{"x", "Reject selected patch (not repeated in follow-ups)"},
+				{"u", "Undo last applied patch"},
+			},
+		},
 		{
 			title: "Chat",
 			bindings: []keybinding{
@@ -159,7 +168,7 @@ func RenderCompactHelp(state string) string {
 
 	switch state {
 	case "reviewing":
-		return helpStyle.Render("j/k: scroll • /: search • i: file list • ?: help • enter: chat • q: quit")
+		return helpStyle.Render("j/k: scroll • /: search • [/]: suggestions • a/x/u: apply/reject/undo • i: file list • ?: help • enter: chat • q: quit")
 	case "chatting":
 		return helpStyle.Render("enter: send • ctrl+w: toggle web search • esc: back • ?: help • q: quit")
 	case "searching":
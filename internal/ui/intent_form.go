@@ -8,9 +8,9 @@ import (
 	appcontext "github.com/trankhanh040147/revcli/internal/context"
 )
 
-// CollectIntent collects user intent using a huh form
+// CollectIntent collects user intent using a huh form, prefilled from initial when set
 // Returns nil if skipped (non-interactive mode)
-func CollectIntent(interactive bool) (*appcontext.Intent, error) {
+func CollectIntent(interactive bool, initial *appcontext.Intent) (*appcontext.Intent, error) {
 	if !interactive {
 		return nil, nil
 	}
@@ -19,6 +19,14 @@ func CollectIntent(interactive bool) (*appcontext.Intent, error) {
 	var focusAreas []string
 	var negativeConstraints string
 	var webSearchEnabled bool = true // Default to enabled
+	var checkIntent bool
+	if initial != nil {
+		customInstruction = initial.CustomInstruction
+		focusAreas = initial.FocusAreas
+		negativeConstraints = strings.Join(initial.NegativeConstraints, ", ")
+		webSearchEnabled = initial.WebSearchEnabled
+		checkIntent = initial.CheckIntent
+	}
 
 	form := huh.NewForm(
 		huh.NewGroup(
@@ -59,6 +67,11 @@ func CollectIntent(interactive bool) (*appcontext.Intent, error) {
 				Title("Enable Web Search").
 				Description("Allow Gemini to search the web for additional context (default: enabled)").
 				Value(&webSearchEnabled),
+
+			huh.NewConfirm().
+				Title("Check Intent").
+				Description("Ask the reviewer whether the diff implements the instructions, branch, commits and linked issues, and to report gaps").
+				Value(&checkIntent),
 		),
 	).WithTheme(huh.ThemeCatppuccin()).
 		WithWidth(80).
@@ -89,6 +102,7 @@ func CollectIntent(interactive bool) (*appcontext.Intent, error) {
 		FocusAreas:          focusAreas,
 		NegativeConstraints: negativeList,
 		WebSearchEnabled:    webSearchEnabled,
+		CheckIntent:         checkIntent,
 	}
 
 	return intent, nil
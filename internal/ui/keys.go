@@ -49,6 +49,13 @@ type KeyMap struct {
 	FileListPrune key.Binding
 	SelectFile    key.Binding
 	Back          key.Binding
+
+	// Suggestions
+	PrevSuggestion   key.Binding
+	NextSuggestion   key.Binding
+	ApplySuggestion  key.Binding
+	RejectSuggestion key.Binding
+	UndoSuggestion   key.Binding
 }
 
 // DefaultKeyMap returns the default keymap
@@ -188,5 +195,27 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "back"),
 		),
+
+		// Suggestions
+		PrevSuggestion: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "previous suggestion"),
+		),
+		NextSuggestion: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next suggestion"),
+		),
+		ApplySuggestion: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "apply suggestion"),
+		),
+		RejectSuggestion: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "reject suggestion"),
+		),
+		UndoSuggestion: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "undo last apply"),
+		),
 	}
 }
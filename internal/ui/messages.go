@@ -4,6 +4,10 @@ import (
 	"time"
 
 	tea "charm.land/bubbletea/v2"
+
+	"github.com/trankhanh040147/revcli/internal/chunk"
+	"github.com/trankhanh040147/revcli/internal/finding"
+	"github.com/trankhanh040147/revcli/internal/patch"
 )
 
 // ReviewStartMsg signals that a review has started
@@ -131,3 +135,60 @@ type PruneFileMsg struct {
 	Summary  string
 	Err      error
 }
+
+// PatchesVerifiedMsg contains the suggested patches after verification (and repair)
+type PatchesVerifiedMsg struct {
+	Patches []*patch.Patch
+	// ChatIndex is the index in the chat history of the follow-up answer suggesting the patches, -1 for the review
+	ChatIndex int
+	Err       error
+}
+
+// SuggestionAppliedMsg contains the result of applying a suggested patch
+type SuggestionAppliedMsg struct {
+	Index   int
+	Applied *patch.Applied
+	Err     error
+}
+
+// SuggestionUndoneMsg contains the result of undoing an applied patch
+type SuggestionUndoneMsg struct {
+	Applied *patch.Applied
+	Err     error
+}
+
+// FindingsVerifiedMsg contains the findings after the self-verification pass
+type FindingsVerifiedMsg struct {
+	Kept       []*finding.Finding
+	Suppressed []*finding.Finding
+	Dropped    []*finding.Finding
+	// Err joins the findings the verifier couldn't check (they are kept unverified)
+	Err error
+}
+
+// FindingsSavedMsg contains the result of storing the review's findings
+type FindingsSavedMsg struct {
+	// Comparison with the previous run on the branch (nil for the first run)
+	Comparison *finding.Comparison
+	Err        error
+}
+
+// ChunksStartMsg signals that a chunked review has started and provides its channels
+type ChunksStartMsg struct {
+	ProgressChan chan chunk.Progress
+	DoneChan     chan ChunksDoneMsg
+	// ToolCallChan reports the tool calls of every chunk's reviewer
+	ToolCallChan chan string
+}
+
+// ChunkProgressMsg contains a status change of one chunk
+type ChunkProgressMsg struct {
+	Progress chunk.Progress
+}
+
+// ChunksDoneMsg signals that every chunk was reviewed
+type ChunksDoneMsg struct {
+	// MergePrompt merges the chunk reviews into one review
+	MergePrompt string
+	Err         error
+}
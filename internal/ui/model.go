@@ -14,7 +14,10 @@ import (
 	"charm.land/lipgloss/v2"
 
 	"github.com/trankhanh040147/revcli/internal/app"
+	"github.com/trankhanh040147/revcli/internal/chunk"
 	appcontext "github.com/trankhanh040147/revcli/internal/context"
+	"github.com/trankhanh040147/revcli/internal/finding"
+	"github.com/trankhanh040147/revcli/internal/patch"
 	"github.com/trankhanh040147/revcli/internal/preset"
 )
 
@@ -80,6 +83,10 @@ type Model struct {
 	streamChunkChan chan string
 	streamErrChan   chan error
 	streamDoneChan  chan string
+	// streamToolCallChan reports the reviewer's tool calls
+	streamToolCallChan chan string
+	// toolCalls are the tool calls of the streaming review, in order
+	toolCalls []string
 
 	// Yank state
 	yankFeedback string // Feedback message for yank
@@ -94,6 +101,20 @@ type Model struct {
 	pruningSpinners map[string]spinner.Model      // Spinners for each file being pruned
 	pruningCancels  map[string]context.CancelFunc // Cancel functions for each pruning operation
 
+	// Suggested patches extracted from the review (verified after streaming completes)
+	patches        []*patch.Patch
+	selectedPatch  int                        // Index into patches (-1 when none)
+	patchDecisions map[int]SuggestionDecision // Apply/reject decisions for this session
+	appliedPatches []*patch.Applied           // Undo stack (most recent last)
+	undoPending    bool                       // An undo is running; u is ignored until it finishes
+
+	// Findings shown in the review (suppressed findings are removed from the response)
+	findings []*finding.Finding
+
+	// Chunked review progress, one entry per chunk (empty when the review isn't chunked)
+	chunkProgress     []chunk.Progress
+	chunkProgressChan chan chunk.Progress
+
 	// Keybindings
 	keys KeyMap
 }
@@ -172,6 +193,9 @@ func NewModel(reviewCtx *appcontext.ReviewContext, appInstance *app.App, session
 		pruningFiles:       make(map[string]bool),
 		pruningSpinners:    make(map[string]spinner.Model),
 		pruningCancels:     make(map[string]context.CancelFunc),
+		selectedPatch:      -1,
+		patchDecisions:     make(map[int]SuggestionDecision),
+		chunkProgress:      pendingChunks(reviewCtx.Chunks),
 		keys:               DefaultKeyMap(),
 	}
 }
@@ -205,6 +229,7 @@ func (m *Model) resetStreamState() {
 	m.streamChunkChan = nil
 	m.streamErrChan = nil
 	m.streamDoneChan = nil
+	m.streamToolCallChan = nil
 }
 
 // transitionToErrorOnCancel handles state transition when a request is cancelled
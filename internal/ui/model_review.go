@@ -2,7 +2,9 @@ package ui
 
 import (
 	"context"
+	"maps"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -13,13 +15,20 @@ import (
 	"github.com/trankhanh040147/revcli/internal/app"
 	appcontext "github.com/trankhanh040147/revcli/internal/context"
 	"github.com/trankhanh040147/revcli/internal/message"
-	"github.com/trankhanh040147/revcli/internal/prompt"
 )
 
-// buildAttachments converts review context files to message attachments
+// buildAttachments converts the files the review context sends as attachments to message attachments
+// Files rendered in the prompt aren't attached again
 func buildAttachments(reviewCtx *appcontext.ReviewContext) []message.Attachment {
+	return fileAttachments(reviewCtx.Attachments)
+}
+
+// fileAttachments converts files to message attachments, sorted by path so the payload is the same
+// on every run (and cacheable by Claude models)
+func fileAttachments(files map[string]string) []message.Attachment {
 	var attachments []message.Attachment
-	for filePath, content := range reviewCtx.FileContents {
+	for _, filePath := range slices.Sorted(maps.Keys(files)) {
+		content := files[filePath]
 		attachments = append(attachments, message.Attachment{
 			FilePath: filePath,
 			FileName: filepath.Base(filePath),
@@ -32,15 +41,8 @@ func buildAttachments(reviewCtx *appcontext.ReviewContext) []message.Attachment
 
 // startReview initiates the code review with streaming support
 func (m *Model) startReview() tea.Cmd {
-	// Rebuild prompt with pruned files if any
+	// The prompt is packed again whenever a file is pruned
 	userPrompt := m.reviewCtx.UserPrompt
-	if len(m.reviewCtx.PrunedFiles) > 0 {
-		userPrompt = prompt.BuildReviewPromptWithPruning(
-			m.reviewCtx.RawDiff,
-			m.reviewCtx.FileContents,
-			m.reviewCtx.PrunedFiles,
-		)
-	}
 
 	// Build attachments
 	attachments := buildAttachments(m.reviewCtx)
@@ -48,6 +50,10 @@ func (m *Model) startReview() tea.Cmd {
 	// Create new context for this command
 	ctx, cancel := context.WithCancel(m.rootCtx)
 	m.activeCancel = cancel
+	// Chunked reviews stream the merge step once every chunk is reviewed
+	if len(m.reviewCtx.Chunks) > 0 {
+		return reviewChunksCmd(ctx, m.app, m.sessionID, m.reviewCtx)
+	}
 	// Return command that starts streaming via coordinator
 	return streamReviewCmd(ctx, m.app, m.sessionID, userPrompt, attachments)
 }
@@ -59,6 +65,7 @@ func streamReviewCmd(ctx context.Context, appInstance *app.App, sessionID, userP
 		chunkChan := make(chan string, 100)
 		errChan := make(chan error, 10)
 		doneChan := make(chan string, 1)
+		toolCallChan := make(chan string, 100)
 
 		// Create separate context for message subscription that can be cancelled independently
 		msgCtx, msgCancel := context.WithCancel(ctx)
@@ -69,6 +76,7 @@ func streamReviewCmd(ctx context.Context, appInstance *app.App, sessionID, userP
 		// Subscribe to messages with separate context
 		messageEvents := appInstance.Messages.Subscribe(msgCtx)
 		messageReadBytes := make(map[string]int)
+		seenToolCalls := make(map[string]bool)
 		var fullResponse strings.Builder
 		var fullResponseMutex sync.Mutex
 
@@ -87,6 +95,19 @@ func streamReviewCmd(ctx context.Context, appInstance *app.App, sessionID, userP
 					msg := event.Payload
 					// Filter by sessionID and assistant role
 					if msg.SessionID == sessionID && msg.Role == message.Assistant && len(msg.Parts) > 0 {
+						// Report each tool call once its input is complete
+						for _, call := range msg.ToolCalls() {
+							if !call.Finished || seenToolCalls[call.ID] {
+								continue
+							}
+							seenToolCalls[call.ID] = true
+							select {
+							case toolCallChan <- describeToolCall(call):
+							case <-msgCtx.Done():
+								return
+							}
+						}
+
 						content := msg.Content().String()
 						readBytes := messageReadBytes[msg.ID]
 
@@ -144,9 +165,14 @@ func streamReviewCmd(ctx context.Context, appInstance *app.App, sessionID, userP
 			// Wait for message goroutine to exit (with timeout)
 			select {
 			case <-messageDone:
-				// Message goroutine exited
+				// Message goroutine exited, nothing sends tool calls anymore
+				close(toolCallChan)
 			case <-time.After(1 * time.Second):
 				// Timeout - message goroutine didn't exit, continue anyway
+				go func() {
+					<-messageDone
+					close(toolCallChan)
+				}()
 			}
 
 			if waitErr != nil {
@@ -196,9 +222,10 @@ func streamReviewCmd(ctx context.Context, appInstance *app.App, sessionID, userP
 
 		// Return initial message to start receiving chunks
 		return StreamStartMsg{
-			ChunkChan: chunkChan,
-			ErrChan:   errChan,
-			DoneChan:  doneChan,
+			ChunkChan:    chunkChan,
+			ErrChan:      errChan,
+			DoneChan:     doneChan,
+			ToolCallChan: toolCallChan,
 		}
 	}
 }
@@ -208,4 +235,6 @@ type StreamStartMsg struct {
 	ChunkChan chan string
 	ErrChan   chan error
 	DoneChan  chan string
+	// ToolCallChan reports the reviewer's tool calls as they're made
+	ToolCallChan chan string
 }
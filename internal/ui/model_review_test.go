@@ -0,0 +1,21 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileAttachmentsAreSortedByPath(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{"c.go": "package c\n", "a.go": "package a\n", "b/b.go": "package b\n"}
+	for range 10 {
+		attachments := fileAttachments(files)
+		require.Len(t, attachments, 3)
+		require.Equal(t, []string{"a.go", "b/b.go", "c.go"},
+			[]string{attachments[0].FilePath, attachments[1].FilePath, attachments[2].FilePath})
+		require.Equal(t, "b.go", attachments[1].FileName)
+		require.Equal(t, []byte("package a\n"), attachments[0].Content)
+	}
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"context"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/app"
+	"github.com/trankhanh040147/revcli/internal/patch"
+)
+
+// coordinatorRepairFunc sends patch repair prompts through the review session
+func coordinatorRepairFunc(appInstance *app.App, sessionID string) patch.RepairFunc {
+	return func(ctx context.Context, prompt string) (string, error) {
+		result, err := appInstance.AgentCoordinator.Run(ctx, sessionID, prompt)
+		if err != nil {
+			return "", err
+		}
+		return result.Response.Content.Text(), nil
+	}
+}
+
+// startPatchVerification verifies the patches suggested in the review, if any
+func (m *Model) startPatchVerification() tea.Cmd {
+	return m.verifyResponsePatches(m.reviewResponse, -1)
+}
+
+// startFollowUpPatchVerification verifies the patches suggested in the latest follow-up answer, if any
+func (m *Model) startFollowUpPatchVerification() tea.Cmd {
+	chatIndex := len(m.chatHistory) - 1
+	return m.verifyResponsePatches(m.chatHistory[chatIndex].Content, chatIndex)
+}
+
+// verifyResponsePatches verifies the patches suggested in a response, if any
+func (m *Model) verifyResponsePatches(response string, chatIndex int) tea.Cmd {
+	patches := patch.Extract(response)
+	if len(patches) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(m.rootCtx)
+	m.activeCancel = cancel
+	m.yankFeedback = PatchVerifyingFeedback
+	m.updateViewportHeight()
+	return verifyPatchesCmd(ctx, patches, chatIndex, coordinatorRepairFunc(m.app, m.sessionID))
+}
+
+// verifyPatchesCmd verifies patches off the UI goroutine (git apply + one repair attempt)
+func verifyPatchesCmd(ctx context.Context, patches []*patch.Patch, chatIndex int, repair patch.RepairFunc) tea.Cmd {
+	return func() tea.Msg {
+		err := patch.VerifyAll(ctx, patches, repair)
+		return PatchesVerifiedMsg{Patches: patches, ChatIndex: chatIndex, Err: err}
+	}
+}
+
+// handlePatchMessages annotates the review or follow-up answer with patch verification results
+// Follow-up patches are appended, so the decisions on earlier patches keep their indexes
+// Returns (model, cmd, shouldReturnEarly)
+func (m *Model) handlePatchMessages(msg tea.Msg) (*Model, tea.Cmd, bool) {
+	verifiedMsg, ok := msg.(PatchesVerifiedMsg)
+	if !ok {
+		return m, nil, false
+	}
+
+	m.activeCancel = nil
+	if verifiedMsg.ChatIndex < 0 {
+		m.patches = verifiedMsg.Patches
+		m.selectedPatch = lo.Ternary(len(m.patches) > 0, 0, -1)
+		m.reviewResponse = patch.Annotate(m.reviewResponse, m.patches)
+	} else {
+		if !m.hasSelectedSuggestion() {
+			m.selectedPatch = len(m.patches)
+		}
+		m.patches = append(m.patches, verifiedMsg.Patches...)
+		answer := &m.chatHistory[verifiedMsg.ChatIndex]
+		answer.Content = patch.Annotate(answer.Content, verifiedMsg.Patches)
+	}
+	m.yankFeedback = patch.Summary(verifiedMsg.Patches)
+	if verifiedMsg.Err != nil {
+		m.yankFeedback += " (repair failed: " + verifiedMsg.Err.Error() + ")"
+	}
+	m.updateViewport()
+	m.updateViewportHeight()
+	return m, ClearYankFeedbackCmd(PatchFeedbackDuration), true
+}
@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"context"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/trankhanh040147/revcli/internal/app"
+	"github.com/trankhanh040147/revcli/internal/config"
+	"github.com/trankhanh040147/revcli/internal/prune"
+)
+
+// NewSummarizer summarizes files with the configured small model, caching the summaries in the database
+func NewSummarizer(appInstance *app.App) *prune.Summarizer {
+	generate := coordinatorGenerateFunc(appInstance, config.SelectedModelTypeSmall)
+	model := appInstance.Config().Models[config.SelectedModelTypeSmall]
+	return prune.NewSummarizer(appInstance.Summaries, model.Provider+"/"+model.Model, prune.GenerateFunc(generate))
+}
+
+// pruneFileCmd summarizes a file in the background
+func pruneFileCmd(ctx context.Context, summarizer *prune.Summarizer, filePath, content string) tea.Cmd {
+	return func() tea.Msg {
+		summary, err := summarizer.Summarize(ctx, filePath, content)
+		return PruneFileMsg{FilePath: filePath, Summary: summary, Err: err}
+	}
+}
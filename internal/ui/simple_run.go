@@ -4,19 +4,32 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"time"
 
 	"charm.land/fantasy"
 
-	appcontext "github.com/trankhanh040147/revcli/internal/context"
 	"github.com/trankhanh040147/revcli/internal/app"
+	appcontext "github.com/trankhanh040147/revcli/internal/context"
+	"github.com/trankhanh040147/revcli/internal/finding"
 	"github.com/trankhanh040147/revcli/internal/message"
+	"github.com/trankhanh040147/revcli/internal/patch"
 	"github.com/trankhanh040147/revcli/internal/preset"
 )
 
+// SimpleResult is the outcome of a non-interactive review
+type SimpleResult struct {
+	// Response is the final review text (patches annotated with verification results)
+	Response string
+	// Patches are the suggested patches after verification
+	Patches []*patch.Patch
+	// Findings are the findings shown in the response (suppressed findings excluded)
+	Findings []*finding.Finding
+}
+
 // RunSimple runs a simple non-interactive review using coordinator
-func RunSimple(ctx context.Context, w io.Writer, reviewCtx *appcontext.ReviewContext, appInstance *app.App, sessionID string, p *preset.Preset) error {
+func RunSimple(ctx context.Context, w io.Writer, reviewCtx *appcontext.ReviewContext, appInstance *app.App, sessionID string, p *preset.Preset) (*SimpleResult, error) {
 	fmt.Fprintln(w, RenderTitle("🔍 Code Review"))
 	fmt.Fprintln(w, RenderSubtitle(reviewCtx.Summary()))
 	fmt.Fprintln(w)
@@ -26,6 +39,14 @@ func RunSimple(ctx context.Context, w io.Writer, reviewCtx *appcontext.ReviewCon
 	// Build prompt and attachments
 	prompt := reviewCtx.UserPrompt
 	attachments := buildAttachments(reviewCtx)
+	if len(reviewCtx.Chunks) > 0 {
+		mergePrompt, err := reviewChunksSimple(ctx, w, reviewCtx, appInstance, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		// The merge step only sees the chunk reviews
+		prompt, attachments = mergePrompt, nil
+	}
 
 	// Use app.RunNonInteractive which handles streaming
 	// Note: RunNonInteractive doesn't support attachments, so we include file contents in prompt if needed
@@ -53,18 +74,26 @@ func RunSimple(ctx context.Context, w io.Writer, reviewCtx *appcontext.ReviewCon
 		select {
 		case result := <-done:
 			if result.err != nil {
-				return fmt.Errorf("review failed: %w", result.err)
+				return nil, fmt.Errorf("review failed: %w", result.err)
 			}
 			// Wait a moment for final message updates
 			time.Sleep(100 * time.Millisecond)
-			
+
 			// Render the full response with markdown
 			fmt.Fprintln(w)
 			fmt.Fprintln(w, RenderDivider(80))
 			fmt.Fprintln(w)
 
-			// Get final content from result
-			finalContent := result.result.Response.Content.Text()
+			// Get final content from result, without suppressed findings and with suggested patches verified
+			finalContent, findings, verification := processSimpleFindings(ctx, w, result.result.Response.Content.Text(), reviewCtx, appInstance)
+			if err := appInstance.Findings.SaveRun(ctx, findingRun(reviewCtx, sessionID), findings); err != nil {
+				fmt.Fprintln(os.Stderr, RenderWarning(err.Error()))
+			}
+			patches := patch.Extract(finalContent)
+			if err := patch.VerifyAll(ctx, patches, coordinatorRepairFunc(appInstance, sessionID)); err != nil {
+				fmt.Fprintln(os.Stderr, RenderWarning(err.Error()))
+			}
+			finalContent = patch.Annotate(finalContent, patches)
 			renderer, err := NewRenderer()
 			if err == nil {
 				rendered, err := renderer.RenderMarkdown(finalContent)
@@ -79,8 +108,14 @@ func RunSimple(ctx context.Context, w io.Writer, reviewCtx *appcontext.ReviewCon
 
 			elapsed := time.Since(startTime)
 			fmt.Fprintln(w)
+			if verification != "" {
+				fmt.Fprintln(w, RenderHelp(verification))
+			}
+			if summary := patch.Summary(patches); summary != "" {
+				fmt.Fprintln(w, RenderHelp(summary))
+			}
 			fmt.Fprintln(w, RenderSuccess(fmt.Sprintf("Review completed in %s", elapsed.Round(time.Millisecond))))
-			return nil
+			return &SimpleResult{Response: finalContent, Patches: patches, Findings: findings}, nil
 
 		case event := <-messageEvents:
 			msg := event.Payload
@@ -99,7 +134,30 @@ func RunSimple(ctx context.Context, w io.Writer, reviewCtx *appcontext.ReviewCon
 			}
 
 		case <-ctx.Done():
-			return ctx.Err()
+			return nil, ctx.Err()
 		}
 	}
 }
+
+// processSimpleFindings hides suppressed findings, runs the self-verification pass if enabled
+// and tags the remaining findings with their ID
+// Returns the rewritten response, the shown findings and the verification summary ("" when disabled)
+func processSimpleFindings(ctx context.Context, w io.Writer, response string, reviewCtx *appcontext.ReviewContext, appInstance *app.App) (string, []*finding.Finding, string) {
+	visible, suppressed := finding.Filter(
+		finding.Parse(response, reviewCtx.FileContents), reviewCtx.Suppressions, reviewCtx.Branch)
+	if reviewCtx.VerifyModel == "" || len(visible) == 0 {
+		return finding.Rewrite(response, visible, suppressed, nil), visible, ""
+	}
+
+	fmt.Fprintln(w, RenderHelp(fmt.Sprintf(FindingsVerifyingFeedback, len(visible))))
+	generate := coordinatorGenerateFunc(appInstance, reviewCtx.VerifyModel)
+	kept, dropped, err := finding.Verify(ctx, visible, reviewCtx.FileContents, generate)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, RenderWarning(err.Error()))
+	}
+	if kept == nil && dropped == nil {
+		// Verification was cancelled; keep every finding unverified
+		return finding.Rewrite(response, visible, suppressed, nil), visible, ""
+	}
+	return finding.Rewrite(response, kept, suppressed, dropped), kept, finding.VerificationSummary(kept, dropped)
+}
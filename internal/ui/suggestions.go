@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/history"
+	"github.com/trankhanh040147/revcli/internal/patch"
+)
+
+// SuggestionDecision is the user's decision on a suggested patch
+type SuggestionDecision int
+
+const (
+	SuggestionPending SuggestionDecision = iota
+	SuggestionApplied
+	SuggestionRejected
+)
+
+// String returns the string representation of SuggestionDecision
+func (d SuggestionDecision) String() string {
+	switch d {
+	case SuggestionApplied:
+		return "applied"
+	case SuggestionRejected:
+		return "rejected"
+	default:
+		return "pending"
+	}
+}
+
+// handleSuggestionKeys handles [ ] a x u in reviewing mode
+// Returns (model, cmd, handled)
+func (m *Model) handleSuggestionKeys(msg tea.KeyMsg) (*Model, tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keys.PrevSuggestion):
+		return m, m.selectSuggestion(-1), true
+	case key.Matches(msg, m.keys.NextSuggestion):
+		return m, m.selectSuggestion(1), true
+	case key.Matches(msg, m.keys.ApplySuggestion):
+		return m, m.applySelectedSuggestion(), true
+	case key.Matches(msg, m.keys.RejectSuggestion):
+		return m, m.rejectSelectedSuggestion(), true
+	case key.Matches(msg, m.keys.UndoSuggestion):
+		return m, m.undoLastSuggestion(), true
+	}
+	return m, nil, false
+}
+
+// hasSelectedSuggestion reports whether selectedPatch points at a patch
+func (m *Model) hasSelectedSuggestion() bool {
+	return m.selectedPatch >= 0 && m.selectedPatch < len(m.patches)
+}
+
+// showFeedback sets the feedback line and schedules clearing it
+func (m *Model) showFeedback(feedback string) tea.Cmd {
+	m.yankFeedback = feedback
+	m.updateViewportHeight()
+	return ClearYankFeedbackCmd(YankFeedbackDuration)
+}
+
+// selectSuggestion moves the selection and scrolls to the patch
+func (m *Model) selectSuggestion(delta int) tea.Cmd {
+	if len(m.patches) == 0 {
+		return m.showFeedback(SuggestionNoneFeedback)
+	}
+	m.selectedPatch = lo.Clamp(m.selectedPatch+delta, 0, len(m.patches)-1)
+	m.scrollToSuggestion()
+	return nil
+}
+
+// applySelectedSuggestion writes the selected patch to disk
+func (m *Model) applySelectedSuggestion() tea.Cmd {
+	if !m.hasSelectedSuggestion() {
+		return m.showFeedback(SuggestionNoneFeedback)
+	}
+	if m.patchDecisions[m.selectedPatch] == SuggestionApplied {
+		return m.showFeedback(SuggestionAlreadyApplied)
+	}
+	p := m.patches[m.selectedPatch]
+	if !p.Usable() {
+		return m.showFeedback(SuggestionUnusableFeedback)
+	}
+	return applyPatchCmd(m.rootCtx, m.app.History, m.sessionID, m.selectedPatch, p)
+}
+
+// rejectSelectedSuggestion remembers the rejection for follow-up prompts
+func (m *Model) rejectSelectedSuggestion() tea.Cmd {
+	if !m.hasSelectedSuggestion() {
+		return m.showFeedback(SuggestionNoneFeedback)
+	}
+	if m.patchDecisions[m.selectedPatch] == SuggestionApplied {
+		return m.showFeedback(SuggestionAlreadyApplied)
+	}
+	m.patchDecisions[m.selectedPatch] = SuggestionRejected
+	return m.showFeedback(fmt.Sprintf(SuggestionRejectedFeedback, m.selectedPatch+1))
+}
+
+// undoLastSuggestion restores the files of the most recently applied patch
+func (m *Model) undoLastSuggestion() tea.Cmd {
+	if m.undoPending {
+		return m.showFeedback(SuggestionUndoPending)
+	}
+	if len(m.appliedPatches) == 0 {
+		return m.showFeedback(SuggestionNothingToUndo)
+	}
+	// Pop before dispatching, so the same patch can't be undone twice
+	applied := m.appliedPatches[len(m.appliedPatches)-1]
+	m.appliedPatches = m.appliedPatches[:len(m.appliedPatches)-1]
+	m.undoPending = true
+	return undoPatchCmd(m.rootCtx, m.app.History, m.sessionID, applied)
+}
+
+// applyPatchCmd applies a patch off the UI goroutine
+func applyPatchCmd(ctx context.Context, historyService history.Service, sessionID string, index int, p *patch.Patch) tea.Cmd {
+	return func() tea.Msg {
+		applier, err := patch.NewApplier(historyService, sessionID)
+		if err != nil {
+			return SuggestionAppliedMsg{Index: index, Err: err}
+		}
+		applied, err := applier.Apply(ctx, p)
+		return SuggestionAppliedMsg{Index: index, Applied: applied, Err: err}
+	}
+}
+
+// undoPatchCmd undoes an applied patch off the UI goroutine
+func undoPatchCmd(ctx context.Context, historyService history.Service, sessionID string, applied *patch.Applied) tea.Cmd {
+	return func() tea.Msg {
+		applier, err := patch.NewApplier(historyService, sessionID)
+		if err != nil {
+			return SuggestionUndoneMsg{Applied: applied, Err: err}
+		}
+		return SuggestionUndoneMsg{Applied: applied, Err: applier.Undo(ctx, applied)}
+	}
+}
+
+// handleSuggestionMessages records apply/undo results
+// Returns (model, cmd, shouldReturnEarly)
+func (m *Model) handleSuggestionMessages(msg tea.Msg) (*Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case SuggestionAppliedMsg:
+		if msg.Err != nil {
+			return m, m.showFeedback(fmt.Sprintf("Apply failed: %v", msg.Err)), true
+		}
+		m.patchDecisions[msg.Index] = SuggestionApplied
+		m.appliedPatches = append(m.appliedPatches, msg.Applied)
+		files := strings.Join(msg.Applied.Patch.Files, ", ")
+		return m, m.showFeedback(fmt.Sprintf(SuggestionAppliedFeedback, msg.Index+1, files)), true
+
+	case SuggestionUndoneMsg:
+		m.undoPending = false
+		if msg.Err != nil {
+			// The patch is still applied: put it back so the undo can be retried
+			m.appliedPatches = append(m.appliedPatches, msg.Applied)
+			return m, m.showFeedback(fmt.Sprintf("Undo failed: %v", msg.Err)), true
+		}
+		index := lo.IndexOf(m.patches, msg.Applied.Patch)
+		delete(m.patchDecisions, index)
+		return m, m.showFeedback(fmt.Sprintf(SuggestionUndoneFeedback, index+1)), true
+	}
+	return m, nil, false
+}
+
+// rejectedPatchDiffs returns the diffs of rejected suggestions for follow-up prompts
+func (m *Model) rejectedPatchDiffs() []string {
+	rejected := lo.Filter(lo.Range(len(m.patches)), func(i int, _ int) bool {
+		return m.patchDecisions[i] == SuggestionRejected
+	})
+	return lo.Map(rejected, func(i int, _ int) string { return m.patches[i].Diff })
+}
+
+// renderSuggestionStatus renders the selected suggestion line ("" when there are no patches)
+func (m *Model) renderSuggestionStatus() string {
+	if !m.hasSelectedSuggestion() {
+		return ""
+	}
+	p := m.patches[m.selectedPatch]
+	category := lo.Ternary(p.Category != "", p.Category, "uncategorized")
+	return RenderHelp(fmt.Sprintf(SuggestionStatusFormat,
+		m.selectedPatch+1, len(m.patches), category, p.Status, m.patchDecisions[m.selectedPatch]))
+}
+
+// scrollToSuggestion scrolls the viewport to the selected patch's file header
+// Patches render in order, so the nth "+++ " header belongs to the nth file across patches
+func (m *Model) scrollToSuggestion() {
+	target := lo.SumBy(m.patches[:m.selectedPatch], func(p *patch.Patch) int { return len(p.Files) })
+
+	seen := 0
+	for i, line := range strings.Split(ansi.Strip(m.rawContent), "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "+++ ") {
+			continue
+		}
+		if seen == target {
+			m.viewport.SetYOffset(max(0, i-2))
+			return
+		}
+		seen++
+	}
+}
@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trankhanh040147/revcli/internal/app"
+	appcontext "github.com/trankhanh040147/revcli/internal/context"
+	"github.com/trankhanh040147/revcli/internal/patch"
+)
+
+func TestUndoLastSuggestion(t *testing.T) {
+	t.Parallel()
+
+	first := &patch.Applied{Patch: &patch.Patch{Diff: "first"}}
+	second := &patch.Applied{Patch: &patch.Patch{Diff: "second"}}
+	m := &Model{
+		app:            &app.App{},
+		reviewCtx:      &appcontext.ReviewContext{},
+		patches:        []*patch.Patch{first.Patch, second.Patch},
+		patchDecisions: map[int]SuggestionDecision{0: SuggestionApplied, 1: SuggestionApplied},
+		appliedPatches: []*patch.Applied{first, second},
+	}
+
+	// A second u while the undo runs is ignored instead of undoing the same patch again
+	require.NotNil(t, m.undoLastSuggestion())
+	require.Equal(t, []*patch.Applied{first}, m.appliedPatches)
+	m.undoLastSuggestion()
+	require.Equal(t, SuggestionUndoPending, m.yankFeedback)
+	require.Equal(t, []*patch.Applied{first}, m.appliedPatches)
+
+	// A failed undo puts the patch back
+	m.handleSuggestionMessages(SuggestionUndoneMsg{Applied: second, Err: errors.New("disk full")})
+	require.Equal(t, []*patch.Applied{first, second}, m.appliedPatches)
+	require.Equal(t, SuggestionApplied, m.patchDecisions[1])
+
+	m.undoLastSuggestion()
+	m.handleSuggestionMessages(SuggestionUndoneMsg{Applied: second})
+	require.Equal(t, []*patch.Applied{first}, m.appliedPatches)
+	require.NotContains(t, m.patchDecisions, 1)
+	require.Contains(t, m.patchDecisions, 0)
+}
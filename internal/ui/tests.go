@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/samber/lo"
+
+	"github.com/trankhanh040147/revcli/internal/testrun"
+)
+
+// Styles for the per-package test badges (defined once at package level)
+var (
+	testPassBadgeStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#10B981"))
+	testFailBadgeStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#EF4444")).
+				Bold(true)
+)
+
+// renderTestBadges renders a pass/fail badge per tested package on one line
+// Returns "" when no package was tested
+func renderTestBadges(report *testrun.Report) string {
+	if report == nil {
+		return ""
+	}
+	badges := lo.FilterMap(report.Packages, func(p testrun.PackageResult, _ int) (string, bool) {
+		switch p.Status {
+		case testrun.StatusPass:
+			return testPassBadgeStyle.Render("✓ " + p.Name), true
+		case testrun.StatusFail:
+			return testFailBadgeStyle.Render("✗ " + p.Name), true
+		default:
+			return "", false
+		}
+	})
+	if len(badges) == 0 {
+		return ""
+	}
+	return TestBadgesLabel + strings.Join(badges, "  ")
+}
+
+// testBadgeLines returns the lines the test badges take under the title
+func (m *Model) testBadgeLines() int {
+	if renderTestBadges(m.reviewCtx.TestReport) == "" {
+		return 0
+	}
+	return 1
+}
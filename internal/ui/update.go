@@ -33,7 +33,7 @@ func (m *Model) handleWindowSize(msg tea.WindowSizeMsg) {
 	if !m.ready {
 		m.viewport = viewport.New()
 		m.viewport.SetWidth(msg.Width)
-		m.viewport.SetHeight(CalculateViewportHeight(msg.Height, m.state, m.yankFeedback != ""))
+		m.viewport.SetHeight(CalculateViewportHeight(msg.Height-m.testBadgeLines(), m.state, m.yankFeedback != "", len(m.patches) > 0))
 		m.viewport.Style = lipgloss.NewStyle().Padding(0, 2)
 		m.ready = true
 	} else {
@@ -42,7 +42,8 @@ func (m *Model) handleWindowSize(msg tea.WindowSizeMsg) {
 	m.textarea.SetWidth(msg.Width - 4)
 	// Update file list dimensions
 	m.fileList.SetWidth(msg.Width - 4)
-	m.fileList.SetHeight(msg.Height - 4)
+	// Test badges and their spacing take two lines above the list
+	m.fileList.SetHeight(msg.Height - 4 - 2*m.testBadgeLines())
 }
 
 // updateNonKeyMsg handles non-key messages
@@ -70,13 +71,35 @@ func (m *Model) updateNonKeyMsg(msg tea.Msg) (*Model, tea.Cmd) {
 	m.handleReviewMessages(msg)
 
 	// Handle chat messages
-	m.handleChatMessages(msg)
+	if cmd := m.handleChatMessages(msg); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
 
 	// Handle yank messages (may return early)
 	if newM, cmd, shouldReturn := m.handleYankMessages(msg); shouldReturn {
 		return newM, cmd
 	}
 
+	// Handle chunked review messages (may return early)
+	if newM, cmd, shouldReturn := m.handleChunkMessages(msg); shouldReturn {
+		return newM, cmd
+	}
+
+	// Handle patch verification messages (may return early)
+	if newM, cmd, shouldReturn := m.handlePatchMessages(msg); shouldReturn {
+		return newM, cmd
+	}
+
+	// Handle findings persistence messages (may return early)
+	if newM, cmd, shouldReturn := m.handleFindingMessages(msg); shouldReturn {
+		return newM, cmd
+	}
+
+	// Handle suggestion apply/undo messages (may return early)
+	if newM, cmd, shouldReturn := m.handleSuggestionMessages(msg); shouldReturn {
+		return newM, cmd
+	}
+
 	// Handle prune messages (may return early)
 	if newM, cmd, shouldReturn := m.handlePruneMessages(msg); shouldReturn {
 		return newM, cmd
@@ -53,7 +53,7 @@ func (m *Model) updateKeyMsgChatting(msg tea.KeyMsg) (*Model, tea.Cmd) {
 				// Create new context for this command
 				ctx, cancel := context.WithCancel(m.rootCtx)
 				m.activeCancel = cancel
-				return m, SendChatMessage(ctx, m.app, m.sessionID, question)
+				return m, SendChatMessage(ctx, m.app, m.sessionID, question, m.rejectedPatchDiffs())
 			}
 		}
 	case key.Matches(msg, m.keys.PrevPrompt):
@@ -42,12 +42,14 @@ func (m *Model) handleReviewMessages(msg tea.Msg) {
 }
 
 // handleChatMessages handles chat response and error messages
-func (m *Model) handleChatMessages(msg tea.Msg) {
+// Returns the verification of the patches suggested in a response, if any
+func (m *Model) handleChatMessages(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case ChatResponseMsg:
 		// Clear active cancel (command completed)
 		m.activeCancel = nil
 		m.handleChatCompletion(msg.Response, false)
+		return m.startFollowUpPatchVerification()
 
 	case ChatErrorMsg:
 		// Clear active cancel (command errored)
@@ -55,6 +57,7 @@ func (m *Model) handleChatMessages(msg tea.Msg) {
 		m.errorMsg = msg.Err.Error()
 		m.handleChatCompletion("Error: "+msg.Err.Error(), true)
 	}
+	return nil
 }
 
 // handleYankMessages handles yank-related messages
@@ -112,8 +115,12 @@ func (m *Model) handlePruneMessages(msg tea.Msg) (*Model, tea.Cmd, bool) {
 		m.fileList = UpdateFileListModel(m.fileList, m.reviewCtx, m.pruningFiles)
 		return m, ClearYankFeedbackCmd(PruneErrorFeedbackDuration), true
 	}
-	// Update pruned files map (PrunedFiles is always initialized in builder.go)
-	m.reviewCtx.PrunedFiles[filePath] = pruneMsg.Summary
+	// Pack the prompt again with the file's summary
+	if err := m.reviewCtx.Prune(map[string]string{filePath: pruneMsg.Summary}); err != nil {
+		m.yankFeedback = fmt.Sprintf("Error pruning file: %v", err)
+		m.fileList = UpdateFileListModel(m.fileList, m.reviewCtx, m.pruningFiles)
+		return m, ClearYankFeedbackCmd(PruneErrorFeedbackDuration), true
+	}
 	// Update file list to show pruned indicator (and remove pruning indicator)
 	m.fileList = UpdateFileListModel(m.fileList, m.reviewCtx, m.pruningFiles)
 	m.yankFeedback = fmt.Sprintf("✓ Pruned %s", filePath)
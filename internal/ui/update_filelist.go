@@ -2,7 +2,6 @@ package ui
 
 import (
 	"context"
-	"fmt"
 
 	"charm.land/bubbles/v2/key"
 	"charm.land/bubbles/v2/spinner"
@@ -22,8 +21,8 @@ func (m *Model) updateKeyMsgFileList(msg tea.KeyMsg) (*Model, tea.Cmd) {
 		if !ok {
 			return m, nil
 		}
-		// Check if already pruned (PrunedFiles is always initialized in builder.go:91)
-		if _, pruned := m.reviewCtx.PrunedFiles[filePath]; pruned {
+		// Check if already pruned by the summarizer (files the token budget summarized can still be pruned)
+		if _, pruned := m.reviewCtx.Summaries[filePath]; pruned {
 			// Already pruned, skip
 			return m, nil
 		}
@@ -33,7 +32,8 @@ func (m *Model) updateKeyMsgFileList(msg tea.KeyMsg) (*Model, tea.Cmd) {
 			return m, nil
 		}
 		// Check if file exists
-		if _, ok := m.reviewCtx.FileContents[filePath]; !ok {
+		content, ok := m.reviewCtx.FileContents[filePath]
+		if !ok {
 			return m, nil
 		}
 		// Mark file as pruning
@@ -44,22 +44,14 @@ func (m *Model) updateKeyMsgFileList(msg tea.KeyMsg) (*Model, tea.Cmd) {
 		fileSpinner.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#7C3AED"))
 		m.pruningSpinners[filePath] = fileSpinner
 		// Create new context for this command
-		_, cancel := context.WithCancel(m.rootCtx)
+		ctx, cancel := context.WithCancel(m.rootCtx)
 		m.pruningCancels[filePath] = cancel
 		// Update file list to show pruning indicator
 		m.fileList = UpdateFileListModel(m.fileList, m.reviewCtx, m.pruningFiles)
 		// Start spinner tick and prune command
 		return m, tea.Batch(
 			fileSpinner.Tick,
-			// Prune functionality temporarily disabled - needs migration to coordinator
-			// pruneFileCmd(ctx, m.flashClient, filePath, content),
-			tea.Cmd(func() tea.Msg {
-				return PruneFileMsg{
-					FilePath: filePath,
-					Summary:  "",
-					Err:      fmt.Errorf("prune functionality is temporarily disabled during migration"),
-				}
-			}),
+			pruneFileCmd(ctx, NewSummarizer(m.app), filePath, content),
 		)
 	case key.Matches(msg, m.keys.SelectFile):
 		// View selected file (for now, just go back)
@@ -74,6 +74,10 @@ func (m *Model) updateKeyMsgReviewing(msg tea.KeyMsg) (*Model, tea.Cmd) {
 	if newM, cmd, handled := m.handleYank(msg); handled {
 		return newM, cmd
 	}
+	if newM, cmd, handled := m.handleSuggestionKeys(msg); handled {
+		m.resetYankChord()
+		return newM, cmd
+	}
 
 	switch {
 	case key.Matches(msg, m.keys.Quit), key.Matches(msg, m.keys.ForceQuit):
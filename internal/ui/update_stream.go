@@ -45,13 +45,21 @@ func (m *Model) handleStreamMessages(msg tea.Msg) (*Model, tea.Cmd, bool) {
 		m.streamChunkChan = msg.ChunkChan
 		m.streamErrChan = msg.ErrChan
 		m.streamDoneChan = msg.DoneChan
-		// Return commands to listen for chunks, errors, and completion
+		m.streamToolCallChan = msg.ToolCallChan
+		m.toolCalls = nil
+		// Return commands to listen for chunks, errors, completion and tool calls
 		return m, tea.Batch(
 			streamChunkCmd(m.streamChunkChan),
 			streamErrorCmd(m.streamErrChan),
 			streamDoneCmd(m.streamDoneChan),
+			streamToolCallCmd(m.streamToolCallChan),
 		), true
 
+	case ToolCallMsg:
+		// Show the call and keep listening (the chunk listeners are already waiting)
+		m.toolCalls = append(m.toolCalls, msg.Call)
+		return m, streamToolCallCmd(m.streamToolCallChan), true
+
 	case StreamChunkMsg:
 		// Append chunk to response and update viewport incrementally
 		m.reviewResponse += msg.Chunk
@@ -70,9 +78,9 @@ func (m *Model) handleStreamMessages(msg tea.Msg) (*Model, tea.Cmd, bool) {
 		m.resetStreamState()
 		// Clear active cancel (command completed)
 		m.activeCancel = nil
+		cmd := m.processFindings()
 		m.updateViewport()
-		return m, nil, false
+		return m, cmd, true
 	}
 	return m, nil, false
 }
-
@@ -26,6 +26,19 @@ func (m *Model) viewLoading() string {
 	s.WriteString("\n")
 	s.WriteString(m.spinner.View())
 	s.WriteString(" Analyzing your code changes...\n\n")
+	if badges := renderTestBadges(m.reviewCtx.TestReport); badges != "" {
+		s.WriteString(badges)
+		s.WriteString("\n\n")
+	}
+	if calls := renderToolCalls(m.toolCalls, m.reviewCtx.ToolBudget); calls != "" {
+		s.WriteString(calls)
+		s.WriteString("\n")
+	}
+	if len(m.chunkProgress) > 0 {
+		s.WriteString(fmt.Sprintf(ChunkedReviewFormat+"\n", len(m.chunkProgress)))
+		s.WriteString(renderChunkProgress(m.chunkProgress))
+		s.WriteString("\n")
+	}
 	s.WriteString(RenderSubtitle(m.reviewCtx.Summary()))
 	s.WriteString("\n")
 	s.WriteString(RenderHelp("q: quit"))
@@ -54,6 +67,10 @@ func (m *Model) viewMain() string {
 	var s strings.Builder
 	s.WriteString(RenderTitle("🔍 LLM Review"))
 	s.WriteString("\n")
+	if badges := renderTestBadges(m.reviewCtx.TestReport); badges != "" {
+		s.WriteString(badges)
+		s.WriteString("\n")
+	}
 	s.WriteString(m.viewport.View())
 	s.WriteString("\n")
 
@@ -85,6 +102,12 @@ func (m *Model) viewMain() string {
 		s.WriteString(RenderSuccess(m.yankFeedback))
 	}
 
+	// Selected suggestion
+	if status := m.renderSuggestionStatus(); status != "" && m.state == StateReviewing {
+		s.WriteString("\n")
+		s.WriteString(status)
+	}
+
 	// Footer
 	s.WriteString("\n")
 	s.WriteString(m.viewFooter())
@@ -155,6 +178,10 @@ func (m *Model) viewFileList() string {
 	var s strings.Builder
 	s.WriteString(RenderTitle("📁 Files to Review"))
 	s.WriteString("\n\n")
+	if badges := renderTestBadges(m.reviewCtx.TestReport); badges != "" {
+		s.WriteString(badges)
+		s.WriteString("\n\n")
+	}
 	s.WriteString(m.fileList.View())
 	s.WriteString("\n")
 
@@ -55,7 +55,7 @@ func BuildViewportContent(reviewResponse string, chatHistory []ChatMessage, rend
 }
 
 // CalculateViewportHeight calculates the dynamic viewport height based on UI state
-func CalculateViewportHeight(height int, state State, hasYankFeedback bool) int {
+func CalculateViewportHeight(height int, state State, hasYankFeedback, hasSuggestions bool) int {
 	// Base reserved lines: header (2) + footer (2)
 	reserved := 4
 
@@ -69,6 +69,11 @@ func CalculateViewportHeight(height int, state State, hasYankFeedback bool) int
 		reserved += 1
 	}
 
+	// Selected suggestion line in reviewing mode
+	if hasSuggestions && state == StateReviewing {
+		reserved += 1
+	}
+
 	// Chat textarea when in chat mode
 	if state == StateChatting {
 		reserved += 4
@@ -131,7 +136,8 @@ func UpdateViewportWithSearch(vp *viewport.Model, rawContent string, search *Sea
 
 // updateViewportHeight updates the viewport height based on current UI state
 func (m *Model) updateViewportHeight() {
-	m.viewport.SetHeight(CalculateViewportHeight(m.height, m.state, m.yankFeedback != ""))
+	// Test badges take a line under the title
+	m.viewport.SetHeight(CalculateViewportHeight(m.height-m.testBadgeLines(), m.state, m.yankFeedback != "", len(m.patches) > 0))
 }
 
 // resetYankChord resets the yank chord state